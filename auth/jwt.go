@@ -0,0 +1,231 @@
+// Package auth establishes the identity of requests from JSON Web
+// Tokens.
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"mooncamp.com/dgraphtools"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	kitjwt "github.com/go-kit/kit/auth/jwt"
+	"github.com/go-kit/kit/endpoint"
+)
+
+// JWT validates tokens and extracts the identity from their claims.
+type JWT struct {
+	// Keys verify the signatures of tokens by their key id, the key
+	// stored under "" verifies tokens without one. Keys are []byte for
+	// HMAC, *rsa.PublicKey or *ecdsa.PublicKey, e.g. as parsed by
+	// jwt.ParseRSAPublicKeyFromPEM or LoadJWKS. The type of the key
+	// determines the accepted signing methods.
+	Keys map[string]interface{}
+	// Issuer and Audience have to be the iss and one of the aud claims
+	// of the token if set.
+	Issuer   string
+	Audience string
+	// TenantClaim holds the tenant of the identity, "tenant" by default.
+	TenantClaim string
+	// UIDClaim holds the uid of the identity's node, if it is part of
+	// the token. Otherwise the node is resolved from the subject.
+	UIDClaim string
+	// Leeway allows for clock skew when checking exp, nbf and iat.
+	Leeway time.Duration
+
+	now func() time.Time
+}
+
+// Identity validates token and returns the identity it was issued for.
+// Tokens need a subject and an expiry. Errors are of type
+// dgraphtools.Unauthenticated.
+func (j *JWT) Identity(token string) (dgraphtools.Identity, error) {
+	claims := jwt.MapClaims{}
+	// Numbers are kept as json.Number, as float64 can't hold every uid.
+	parser := jwt.Parser{UseJSONNumber: true, SkipClaimsValidation: true}
+	if _, err := parser.ParseWithClaims(token, claims, j.key); err != nil {
+		return dgraphtools.Identity{}, dgraphtools.Unauthenticated{Reason: err.Error()}
+	}
+
+	if err := j.validate(claims); err != nil {
+		return dgraphtools.Identity{}, dgraphtools.Unauthenticated{Reason: err.Error()}
+	}
+
+	identity, err := j.identity(claims)
+	if err != nil {
+		return dgraphtools.Identity{}, dgraphtools.Unauthenticated{Reason: err.Error()}
+	}
+	return identity, nil
+}
+
+// Middleware sets the identity of requests from the token stored in
+// the context by kitjwt.HTTPToContext or kitjwt.GRPCToContext.
+func (j *JWT) Middleware() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			req := request.(dgraphtools.QueryRequest)
+
+			token, ok := ctx.Value(kitjwt.JWTTokenContextKey).(string)
+			if !ok {
+				return dgraphtools.QueryResponse{Error: dgraphtools.Unauthenticated{Reason: "missing token"}}, nil
+			}
+
+			identity, err := j.Identity(token)
+			if err != nil {
+				return dgraphtools.QueryResponse{Error: err}, nil
+			}
+
+			req.Identity = identity
+			return next(ctx, req)
+		}
+	}
+}
+
+func (j *JWT) key(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	key, ok := j.Keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	// Checking the method keeps public keys from being used as HMAC
+	// secrets.
+	switch k := key.(type) {
+	case []byte:
+		// An empty secret, e.g. from an unset environment variable,
+		// would let anyone sign tokens.
+		if len(k) == 0 {
+			return nil, fmt.Errorf("empty secret for key %q", kid)
+		}
+		_, ok = t.Method.(*jwt.SigningMethodHMAC)
+	case *rsa.PublicKey:
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			ok = true
+		default:
+			ok = false
+		}
+	case *ecdsa.PublicKey:
+		_, ok = t.Method.(*jwt.SigningMethodECDSA)
+	default:
+		return nil, fmt.Errorf("unsupported key of type %T", key)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+	}
+	return key, nil
+}
+
+func (j *JWT) validate(claims jwt.MapClaims) error {
+	now := time.Now()
+	if j.now != nil {
+		now = j.now()
+	}
+
+	exp, ok := timeClaim(claims, "exp")
+	if !ok {
+		return fmt.Errorf("missing exp")
+	}
+
+	if !now.Before(exp.Add(j.Leeway)) {
+		return fmt.Errorf("token is expired")
+	}
+
+	if nbf, ok := timeClaim(claims, "nbf"); ok && now.Add(j.Leeway).Before(nbf) {
+		return fmt.Errorf("token is not valid yet")
+	}
+
+	if iat, ok := timeClaim(claims, "iat"); ok && now.Add(j.Leeway).Before(iat) {
+		return fmt.Errorf("token used before issued")
+	}
+
+	if j.Issuer != "" && claims["iss"] != j.Issuer {
+		return fmt.Errorf("unexpected issuer %v", claims["iss"])
+	}
+
+	if j.Audience != "" && !hasAudience(claims["aud"], j.Audience) {
+		return fmt.Errorf("unexpected audience %v", claims["aud"])
+	}
+
+	return nil
+}
+
+func timeClaim(claims jwt.MapClaims, name string) (time.Time, bool) {
+	n, ok := claims[name].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	v, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(v), 0), true
+}
+
+// hasAudience reports whether aud, a string or a list of strings,
+// contains audience.
+func hasAudience(aud interface{}, audience string) bool {
+	switch t := aud.(type) {
+	case string:
+		return t == audience
+	case []interface{}:
+		for _, e := range t {
+			if e == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (j *JWT) identity(claims jwt.MapClaims) (dgraphtools.Identity, error) {
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return dgraphtools.Identity{}, fmt.Errorf("missing sub")
+	}
+
+	tenantClaim := j.TenantClaim
+	if tenantClaim == "" {
+		tenantClaim = "tenant"
+	}
+
+	identity := dgraphtools.Identity{Subject: subject}
+	if v, ok := claims[tenantClaim]; ok {
+		tenant, ok := v.(string)
+		if !ok {
+			return dgraphtools.Identity{}, fmt.Errorf("invalid %s", tenantClaim)
+		}
+		identity.Tenant = tenant
+	}
+
+	if j.UIDClaim == "" {
+		return identity, nil
+	}
+
+	switch v := claims[j.UIDClaim].(type) {
+	case string:
+		uid, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return dgraphtools.Identity{}, fmt.Errorf("invalid %s: %v", j.UIDClaim, err)
+		}
+		identity.UID = uid
+	case json.Number:
+		uid, err := strconv.ParseUint(v.String(), 10, 64)
+		if err != nil {
+			return dgraphtools.Identity{}, fmt.Errorf("invalid %s: %v", j.UIDClaim, err)
+		}
+		identity.UID = uid
+	case nil:
+	default:
+		return dgraphtools.Identity{}, fmt.Errorf("invalid %s", j.UIDClaim)
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,246 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"mooncamp.com/dgraphtools"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	kitjwt "github.com/go-kit/kit/auth/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	secret := []byte("secret")
+	now := time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC)
+	exp := float64(now.Add(time.Hour).Unix())
+
+	j := &JWT{
+		Keys:     map[string]interface{}{"": secret, "rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey, "empty": []byte{}},
+		Issuer:   "https://auth.example.com/",
+		Audience: "dgraph",
+		UIDClaim: "uid",
+		Leeway:   time.Minute,
+		now:      func() time.Time { return now },
+	}
+
+	claims := func(extra jwt.MapClaims) jwt.MapClaims {
+		res := jwt.MapClaims{"sub": "auth0|harry", "iss": "https://auth.example.com/", "aud": "dgraph", "exp": exp}
+		for k, v := range extra {
+			if v == nil {
+				delete(res, k)
+				continue
+			}
+			res[k] = v
+		}
+		return res
+	}
+
+	cases := []struct {
+		name     string
+		token    string
+		expected dgraphtools.Identity
+		err      string
+	}{
+		{
+			name:     "hmac",
+			token:    sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"tenant": "hogwarts", "uid": "0x5"})),
+			expected: dgraphtools.Identity{UID: 5, Subject: "auth0|harry", Tenant: "hogwarts"},
+		},
+		{
+			name:     "numeric uid",
+			token:    sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"uid": uint64(1<<60 + 1)})),
+			expected: dgraphtools.Identity{UID: 1<<60 + 1, Subject: "auth0|harry"},
+		},
+		{
+			name:  "fractional uid",
+			token: sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"uid": 5.5})),
+			err:   `unauthenticated: invalid uid: strconv.ParseUint: parsing "5.5": invalid syntax`,
+		},
+		{
+			name:  "negative uid",
+			token: sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"uid": -5})),
+			err:   `unauthenticated: invalid uid: strconv.ParseUint: parsing "-5": invalid syntax`,
+		},
+		{
+			name:  "uid of another type",
+			token: sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"uid": true})),
+			err:   "unauthenticated: invalid uid",
+		},
+		{
+			name:     "rsa",
+			token:    sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims(jwt.MapClaims{"aud": []string{"api", "dgraph"}})),
+			expected: dgraphtools.Identity{Subject: "auth0|harry"},
+		},
+		{
+			name:     "ecdsa",
+			token:    sign(t, jwt.SigningMethodES256, "ec", ecKey, claims(nil)),
+			expected: dgraphtools.Identity{Subject: "auth0|harry"},
+		},
+		{
+			name:     "leeway",
+			token:    sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"exp": float64(now.Add(-30 * time.Second).Unix())})),
+			expected: dgraphtools.Identity{Subject: "auth0|harry"},
+		},
+		{
+			name:  "wrong secret",
+			token: sign(t, jwt.SigningMethodHS256, "", []byte("guessed"), claims(nil)),
+			err:   "unauthenticated: signature is invalid",
+		},
+		{
+			name:  "public key as hmac secret",
+			token: sign(t, jwt.SigningMethodHS256, "rsa", []byte("public key"), claims(nil)),
+			err:   "unauthenticated: unexpected signing method HS256",
+		},
+		{
+			name:  "empty secret",
+			token: sign(t, jwt.SigningMethodHS256, "empty", []byte{}, claims(nil)),
+			err:   `unauthenticated: empty secret for key "empty"`,
+		},
+		{
+			name:  "unsigned",
+			token: sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, claims(nil)),
+			err:   "unauthenticated: unexpected signing method none",
+		},
+		{
+			name:  "unknown key",
+			token: sign(t, jwt.SigningMethodHS256, "other", secret, claims(nil)),
+			err:   `unauthenticated: unknown key "other"`,
+		},
+		{
+			name:  "expired",
+			token: sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"exp": float64(now.Add(-time.Hour).Unix())})),
+			err:   "unauthenticated: token is expired",
+		},
+		{
+			name:  "missing expiry",
+			token: sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"exp": nil})),
+			err:   "unauthenticated: missing exp",
+		},
+		{
+			name:  "not valid yet",
+			token: sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"nbf": float64(now.Add(time.Hour).Unix())})),
+			err:   "unauthenticated: token is not valid yet",
+		},
+		{
+			name:  "issuer",
+			token: sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"iss": "https://evil.example.com/"})),
+			err:   "unauthenticated: unexpected issuer https://evil.example.com/",
+		},
+		{
+			name:  "audience",
+			token: sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"aud": []string{"api"}})),
+			err:   "unauthenticated: unexpected audience [api]",
+		},
+		{
+			name:  "missing subject",
+			token: sign(t, jwt.SigningMethodHS256, "", secret, claims(jwt.MapClaims{"sub": nil})),
+			err:   "unauthenticated: missing sub",
+		},
+		{
+			name:  "malformed",
+			token: "not a token",
+			err:   "unauthenticated: token contains an invalid number of segments",
+		},
+	}
+
+	for _, e := range cases {
+		t.Run(e.name, func(t *testing.T) {
+			identity, err := j.Identity(e.token)
+			if e.err != "" {
+				require.EqualError(t, err, e.err)
+				require.IsType(t, dgraphtools.Unauthenticated{}, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, e.expected, identity)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	j := &JWT{Keys: map[string]interface{}{"": []byte("secret")}}
+	token := sign(t, jwt.SigningMethodHS256, "", []byte("secret"), jwt.MapClaims{"sub": "auth0|harry", "exp": float64(time.Now().Add(time.Hour).Unix())})
+
+	var actual dgraphtools.Identity
+	next := func(ctx context.Context, request interface{}) (interface{}, error) {
+		actual = request.(dgraphtools.QueryRequest).Identity
+		return dgraphtools.QueryResponse{}, nil
+	}
+
+	ctx := context.WithValue(context.Background(), kitjwt.JWTTokenContextKey, token)
+	_, err := j.Middleware()(next)(ctx, dgraphtools.QueryRequest{})
+	require.NoError(t, err)
+	require.Equal(t, dgraphtools.Identity{Subject: "auth0|harry"}, actual)
+
+	resp, err := j.Middleware()(next)(context.Background(), dgraphtools.QueryRequest{})
+	require.NoError(t, err)
+	require.Equal(t, dgraphtools.Unauthenticated{Reason: "missing token"}, resp.(dgraphtools.QueryResponse).Error)
+}
+
+func TestLoadJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	enc := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-384", "x": %q, "y": %q},
+		{"kty": "oct", "kid": "hmac", "k": %q},
+		{"kty": "RSA", "kid": "encryption", "use": "enc", "n": "", "e": ""}
+	]}`,
+		enc(rsaKey.N), enc(big.NewInt(int64(rsaKey.E))),
+		enc(ecKey.X), enc(ecKey.Y),
+		base64.RawURLEncoding.EncodeToString([]byte("secret")),
+	)
+
+	keys, err := LoadJWKS(strings.NewReader(jwks))
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"rsa":  &rsaKey.PublicKey,
+		"ec":   &ecKey.PublicKey,
+		"hmac": []byte("secret"),
+	}
+	require.Equal(t, expected, keys)
+
+	_, err = LoadJWKS(strings.NewReader(`{"keys": [{"kty": "EC", "kid": "ec", "crv": "P-192"}]}`))
+	require.EqualError(t, err, `key "ec": unsupported curve "P-192"`)
+
+	_, err = LoadJWKS(strings.NewReader(`{"keys": [{"kty": "oct", "kid": "hmac", "k": ""}]}`))
+	require.EqualError(t, err, `key "hmac": empty secret`)
+}
@@ -0,0 +1,323 @@
+// Package builder provides chainable constructors for the data
+// representation of GraphQL+- queries, so queries don't have to be
+// written as gql.GraphQuery struct literals.
+package builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"mooncamp.com/dgraphtools/gql"
+)
+
+const (
+	uidVar   = 1
+	valueVar = 2
+	listVar  = 3
+)
+
+// Block builds a single gql.GraphQuery node. Every method modifies the
+// block in place and returns it, so calls can be chained.
+type Block struct {
+	gq       gql.GraphQuery
+	children []*Block
+}
+
+// Query starts a root block with the given name, e.g. `me(func: ...)`.
+func Query(alias string) *Block {
+	return &Block{gq: gql.GraphQuery{Alias: alias}}
+}
+
+// Var starts a root var block, e.g. `var(func: ...)`.
+func Var() *Block {
+	return Query("var")
+}
+
+// Edge selects a predicate. Language tags can be appended to the
+// predicate as in GraphQL+-, e.g. `name@en:fr`.
+func Edge(pred string) *Block {
+	attr, langs := parsePredicate(pred)
+	return &Block{gq: gql.GraphQuery{Attr: attr, Langs: langs}}
+}
+
+// Fields is a shorthand for selecting several predicates without
+// further arguments.
+func Fields(preds ...string) []*Block {
+	res := make([]*Block, 0, len(preds))
+	for _, e := range preds {
+		res = append(res, Edge(e))
+	}
+	return res
+}
+
+// Count selects the number of values of a predicate, e.g. `count(friends)`.
+func Count(pred string) *Block {
+	b := Edge(pred)
+	b.gq.IsCount = true
+	return b
+}
+
+// Val selects the value of a value variable, e.g. `val(a)`.
+func Val(name string) *Block {
+	return &Block{gq: gql.GraphQuery{
+		Attr:       "val",
+		IsInternal: true,
+		NeedsVar:   []gql.VarContext{{Name: name, Typ: valueVar}},
+	}}
+}
+
+// Aggregate selects an aggregation over a value variable, e.g.
+// `sum(val(a))`. Supported functions are min, max, sum and avg.
+func Aggregate(fn, name string) *Block {
+	needsVar := []gql.VarContext{{Name: name, Typ: valueVar}}
+	return &Block{gq: gql.GraphQuery{
+		Attr:       "val",
+		IsInternal: true,
+		NeedsVar:   needsVar,
+		Func:       &gql.Function{Name: fn, NeedsVar: needsVar},
+	}}
+}
+
+// Min selects `min(val(name))`.
+func Min(name string) *Block {
+	return Aggregate("min", name)
+}
+
+// Max selects `max(val(name))`.
+func Max(name string) *Block {
+	return Aggregate("max", name)
+}
+
+// Sum selects `sum(val(name))`.
+func Sum(name string) *Block {
+	return Aggregate("sum", name)
+}
+
+// Avg selects `avg(val(name))`.
+func Avg(name string) *Block {
+	return Aggregate("avg", name)
+}
+
+// Expand selects `expand(_all_)`, `expand(_forward_)` or `expand(_reverse_)`.
+func Expand(what string) *Block {
+	return &Block{gq: gql.GraphQuery{
+		Attr:       "expand",
+		Expand:     what,
+		IsInternal: true,
+	}}
+}
+
+// ExpandVar selects `expand(val(name))`.
+func ExpandVar(name string) *Block {
+	return &Block{gq: gql.GraphQuery{
+		Attr:       "expand",
+		Expand:     name,
+		IsInternal: true,
+		NeedsVar:   []gql.VarContext{{Name: name, Typ: listVar}},
+	}}
+}
+
+// CheckPwd selects `checkpwd(pred, "password")`.
+func CheckPwd(pred, password string) *Block {
+	return &Block{gq: gql.GraphQuery{
+		Attr: pred,
+		Func: &gql.Function{
+			Name: "checkpwd",
+			Attr: pred,
+			Args: []gql.Arg{{Value: password}, {Value: pred}},
+		},
+	}}
+}
+
+// Math selects a math expression. GraphQL+- requires math blocks to be
+// assigned to a variable, see As.
+func Math(expr *MathExpr) *Block {
+	mt := expr.Build()
+	return &Block{gq: gql.GraphQuery{
+		Attr:       "math",
+		MathExp:    &mt,
+		IsInternal: true,
+	}}
+}
+
+// Func sets the root function of a block.
+func (b *Block) Func(fn *Func) *Block {
+	f := fn.Build()
+	if f.Name == "uid" {
+		b.gq.UID = append(b.gq.UID, f.UID...)
+		f.UID = nil
+	}
+
+	b.gq.Func = f
+	b.gq.NeedsVar = append(b.gq.NeedsVar, f.NeedsVar...)
+	return b
+}
+
+// Filter sets the `@filter` directive of a block.
+func (b *Block) Filter(filter Filter) *Block {
+	ft := filter.filterTree()
+	b.gq.Filter = &ft
+	return b
+}
+
+// Select appends child blocks.
+func (b *Block) Select(children ...*Block) *Block {
+	b.children = append(b.children, children...)
+	return b
+}
+
+// Alias renames the block in the response.
+func (b *Block) Alias(alias string) *Block {
+	b.gq.Alias = alias
+	return b
+}
+
+// As assigns the block to a query variable, e.g. `a as friends`.
+func (b *Block) As(name string) *Block {
+	b.gq.Var = name
+	return b
+}
+
+// Default sets the value used by the default extension if the
+// predicate is missing in the response.
+func (b *Block) Default(value interface{}) *Block {
+	b.gq.Default = value
+	return b
+}
+
+// Cardinality declares how many nodes the cardinality extension
+// expects for the block.
+func (b *Block) Cardinality(c gql.Cardinality) *Block {
+	b.gq.Cardinality = c
+	return b
+}
+
+// First limits the number of results.
+func (b *Block) First(n int) *Block {
+	return b.arg("first", strconv.Itoa(n))
+}
+
+// Offset skips the first n results.
+func (b *Block) Offset(n int) *Block {
+	return b.arg("offset", strconv.Itoa(n))
+}
+
+// After starts the results after the given uid.
+func (b *Block) After(uid uint64) *Block {
+	return b.arg("after", fmt.Sprintf("0x%x", uid))
+}
+
+func (b *Block) arg(key, value string) *Block {
+	if b.gq.Args == nil {
+		b.gq.Args = make(map[string]string)
+	}
+	b.gq.Args[key] = value
+	return b
+}
+
+// OrderAsc sorts the results ascending by a predicate or, using
+// `val(name)`, by a value variable.
+func (b *Block) OrderAsc(pred string) *Block {
+	return b.order(pred, false)
+}
+
+// OrderDesc sorts the results descending by a predicate or, using
+// `val(name)`, by a value variable.
+func (b *Block) OrderDesc(pred string) *Block {
+	return b.order(pred, true)
+}
+
+func (b *Block) order(pred string, desc bool) *Block {
+	if name, ok := unwrap("val", pred); ok {
+		b.gq.NeedsVar = append(b.gq.NeedsVar, gql.VarContext{Name: name, Typ: valueVar})
+		b.gq.Order = append(b.gq.Order, gql.Order{Attr: name, Desc: desc})
+		return b
+	}
+
+	attr, langs := parsePredicate(pred)
+	b.gq.Order = append(b.gq.Order, gql.Order{Attr: attr, Desc: desc, Langs: langs})
+	return b
+}
+
+// GroupBy sets the `@groupby` directive of a block.
+func (b *Block) GroupBy(preds ...string) *Block {
+	for _, e := range preds {
+		b.GroupByAs("", e)
+	}
+	return b
+}
+
+// GroupByAs adds an aliased predicate to the `@groupby` directive.
+func (b *Block) GroupByAs(alias, pred string) *Block {
+	attr, langs := parsePredicate(pred)
+	b.gq.IsGroupby = true
+	b.gq.GroupbyAttrs = append(b.gq.GroupbyAttrs, gql.GroupByAttr{Attr: attr, Alias: alias, Langs: langs})
+	return b
+}
+
+// Normalize sets the `@normalize` directive of a block.
+func (b *Block) Normalize() *Block {
+	b.gq.Normalize = true
+	return b
+}
+
+// Cascade sets the `@cascade` directive of a block. If fields are given
+// only those have to be present, e.g. `@cascade(name)`.
+func (b *Block) Cascade(fields ...string) *Block {
+	b.gq.Cascade = true
+	b.gq.CascadeFields = append(b.gq.CascadeFields, fields...)
+	return b
+}
+
+// Recurse sets the `@recurse` directive of a block. A depth of zero
+// leaves the depth unbounded.
+func (b *Block) Recurse(depth uint64, loop bool) *Block {
+	b.gq.Recurse = true
+	b.gq.RecurseArgs = gql.RecurseArgs{Depth: depth, AllowLoop: loop}
+	return b
+}
+
+// IgnoreReflex sets the `@ignorereflex` directive of a block.
+func (b *Block) IgnoreReflex() *Block {
+	b.gq.IgnoreReflex = true
+	return b
+}
+
+// Build returns the data representation of the block.
+func (b *Block) Build() gql.GraphQuery {
+	gq := b.gq
+	if gq.Facets != nil {
+		gq.Facets = &gql.FacetParams{
+			AllKeys: gq.Facets.AllKeys,
+			Param:   sortFacetParams(gq.Facets.Param),
+		}
+	}
+
+	if len(b.children) > 0 {
+		gq.Children = make([]gql.GraphQuery, 0, len(b.children))
+		for _, e := range b.children {
+			gq.Children = append(gq.Children, e.Build())
+		}
+	}
+
+	return gq
+}
+
+func parsePredicate(pred string) (string, []string) {
+	i := strings.Index(pred, "@")
+	if i < 0 {
+		return pred, nil
+	}
+
+	return pred[:i], strings.Split(pred[i+1:], ":")
+}
+
+func unwrap(fn, s string) (string, bool) {
+	prefix := fmt.Sprintf("%s(", fn)
+	if !strings.HasPrefix(s, prefix) || !strings.HasSuffix(s, ")") {
+		return "", false
+	}
+
+	return strings.TrimSpace(s[len(prefix) : len(s)-1]), true
+}
@@ -0,0 +1,226 @@
+package builder
+
+import (
+	"testing"
+
+	"mooncamp.com/dgraphtools/gql/dgraph"
+
+	dgraphgql "github.com/dgraph-io/dgraph/gql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	table := []struct {
+		name  string
+		query string
+		built *QuerySet
+	}{
+		{
+			name: "readme example",
+			query: `{
+				bladerunner(func: eq(name@en, "Blade Runner")) {
+					name@en
+					initial_release_date
+				}
+			}`,
+			built: Queries(
+				Query("bladerunner").
+					Func(Eq("name@en", "Blade Runner")).
+					Select(Fields("name@en", "initial_release_date")...),
+			),
+		},
+		{
+			name: "pagination, order and filter",
+			query: `{
+				me(func: uid(0x1), orderdesc: name, first: 5) {
+					friend(orderasc: name@en, offset: 2) @filter(lt(alias, "Pat") and not has(dead)) {
+						alias
+					}
+				}
+			}`,
+			built: Queries(
+				Query("me").
+					Func(UID(1)).
+					OrderDesc("name").
+					First(5).
+					Select(
+						Edge("friend").
+							OrderAsc("name@en").
+							Offset(2).
+							Filter(And(Lt("alias", "Pat"), Not(Has("dead")))).
+							Select(Edge("alias")),
+					),
+			),
+		},
+		{
+			name: "var blocks",
+			query: `{
+				var(func: anyofterms(name, "Rick Michonne")) {
+					a as age
+					f as friends
+				}
+
+				me(func: uid(f), orderasc: val(a)) {
+					name
+					val(a)
+					sum(val(a))
+				}
+			}`,
+			built: Queries(
+				Var().
+					Func(AnyOfTerms("name", "Rick Michonne")).
+					Select(Edge("age").As("a"), Edge("friends").As("f")),
+				Query("me").
+					Func(UIDVar("f")).
+					OrderAsc("val(a)").
+					Select(Edge("name"), Val("a"), Sum("a")),
+			),
+		},
+		{
+			name: "directives",
+			query: `{
+				me(func: uid(0x1)) @filter(uid_in(school, 0x1388)) @recurse(depth: 3, loop: true) @ignorereflex {
+					name
+					friend
+				}
+			}`,
+			built: Queries(
+				Query("me").
+					Func(UID(1)).
+					Filter(UIDIn("school", 5000)).
+					Recurse(3, true).
+					IgnoreReflex().
+					Select(Fields("name", "friend")...),
+			),
+		},
+		{
+			name: "math",
+			query: `{
+				var(func: uid(0x0a)) {
+					friends {
+						a as age
+						b as count(friends)
+						d as math(a + b * 2)
+					}
+				}
+
+				me(func: uid(0x0a), orderdesc: val(d)) {
+					name
+				}
+			}`,
+			built: Queries(
+				Var().
+					Func(UID(10)).
+					Select(
+						Edge("friends").Select(
+							Edge("age").As("a"),
+							Count("friends").As("b"),
+							Math(Add(V("a"), Mul(V("b"), C(2)))).As("d"),
+						),
+					),
+				Query("me").
+					Func(UID(10)).
+					OrderDesc("val(d)").
+					Select(Edge("name")),
+			),
+		},
+		{
+			name: "facets",
+			query: `{
+				me(func: uid(0x1)) {
+					friends @facets(orderdesc: closeness, b as some, a1: key1) {
+						val(b)
+					}
+					friend @facets(eq(close, true) or eq(family, true)) @facets {
+						name
+					}
+				}
+			}`,
+			built: Queries(
+				Query("me").
+					Func(UID(1)).
+					Select(
+						Edge("friends").
+							FacetOrderDesc("closeness").
+							FacetVar("b", "some").
+							FacetAs("a1", "key1").
+							Select(Val("b")),
+						Edge("friend").
+							FacetsFilter(Or(Eq("close", "true"), Eq("family", "true"))).
+							Facets().
+							Select(Edge("name")),
+					),
+			),
+		},
+		{
+			name: "group by",
+			query: `{
+				me(func: uid(0x1)) {
+					friends @groupby(name@en, SchooL: school) {
+						count(uid)
+					}
+				}
+			}`,
+			built: Queries(
+				Query("me").
+					Func(UID(1)).
+					Select(
+						Edge("friends").
+							GroupBy("name@en").
+							GroupByAs("SchooL", "school").
+							Select(Count("uid")),
+					),
+			),
+		},
+		{
+			name: "internal functions",
+			query: `{
+				me(func: regexp(name@en, /^Steven/i)) @filter(gt(count(friends), 2)) {
+					checkpwd(password, "123456")
+					friends {
+						expand(_all_)
+					}
+				}
+			}`,
+			built: Queries(
+				Query("me").
+					Func(Regexp("name@en", "^Steven", "i")).
+					Filter(Gt("count(friends)", "2")).
+					Select(
+						CheckPwd("password", "123456"),
+						Edge("friends").Select(Expand("_all_")),
+					),
+			),
+		},
+	}
+
+	for _, e := range table {
+		query, set, built := e.query, e.built, e.built.Build()
+		t.Run(e.name, func(t *testing.T) {
+			gqlVariables := make(map[string]string, len(built.Variables))
+			for k := range built.Variables {
+				gqlVariables[k] = k
+			}
+
+			gqlExpected, err := dgraphgql.Parse(dgraphgql.Request{Str: query, Variables: gqlVariables})
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			expected := dgraph.DecodeGraphQueries(gqlExpected.Query)
+			require.Equal(t, expected, built.Queries)
+
+			renderedQuery, err := set.Render()
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+
+			gqlActual, err := dgraphgql.Parse(dgraphgql.Request{Str: renderedQuery, Variables: gqlVariables})
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			require.Equal(t, expected, dgraph.DecodeGraphQueries(gqlActual.Query))
+		})
+	}
+}
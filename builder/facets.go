@@ -0,0 +1,97 @@
+package builder
+
+import (
+	"sort"
+
+	"mooncamp.com/dgraphtools/gql"
+)
+
+// Facets requests the given facet keys of an edge. Without keys all
+// facets are requested.
+func (b *Block) Facets(keys ...string) *Block {
+	b.facets()
+	if len(keys) == 0 {
+		b.gq.Facets.AllKeys = true
+		return b
+	}
+
+	for _, e := range keys {
+		b.gq.Facets.Param = append(b.gq.Facets.Param, gql.FacetParam{Key: e})
+	}
+	return b
+}
+
+// FacetAs requests a facet under a different name.
+func (b *Block) FacetAs(alias, key string) *Block {
+	b.facets()
+	b.gq.Facets.Param = append(b.gq.Facets.Param, gql.FacetParam{Key: key, Alias: alias})
+	return b
+}
+
+// FacetVar requests a facet and assigns it to a value variable, e.g.
+// `@facets(a as weight)`.
+func (b *Block) FacetVar(name, key string) *Block {
+	b.facets()
+	b.gq.Facets.Param = append(b.gq.Facets.Param, gql.FacetParam{Key: key})
+	if b.gq.FacetVar == nil {
+		b.gq.FacetVar = make(map[string]string)
+	}
+	b.gq.FacetVar[key] = name
+	return b
+}
+
+// FacetOrderAsc sorts the edge ascending by a facet.
+func (b *Block) FacetOrderAsc(key string) *Block {
+	return b.facetOrder(key, false)
+}
+
+// FacetOrderDesc sorts the edge descending by a facet.
+func (b *Block) FacetOrderDesc(key string) *Block {
+	return b.facetOrder(key, true)
+}
+
+func (b *Block) facetOrder(key string, desc bool) *Block {
+	b.facets()
+	b.gq.Facets.Param = append(b.gq.Facets.Param, gql.FacetParam{Key: key})
+	b.gq.FacetOrder = key
+	b.gq.FacetDesc = desc
+	return b
+}
+
+// FacetsFilter sets the facet filter of an edge, e.g.
+// `@facets(eq(close, true))`.
+func (b *Block) FacetsFilter(filter Filter) *Block {
+	ft := filter.filterTree()
+	b.gq.FacetsFilter = &ft
+	return b
+}
+
+func (b *Block) facets() {
+	if b.gq.Facets == nil {
+		b.gq.Facets = &gql.FacetParams{}
+	}
+}
+
+// sortFacetParams orders and deduplicates the facet keys the same way
+// the GraphQL+- parser does.
+func sortFacetParams(params []gql.FacetParam) []gql.FacetParam {
+	if len(params) == 0 {
+		return nil
+	}
+
+	res := make([]gql.FacetParam, len(params))
+	copy(res, params)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Key < res[j].Key
+	})
+
+	out := res[:1]
+	for _, e := range res[1:] {
+		if e.Key == out[len(out)-1].Key {
+			out[len(out)-1] = e
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package builder
+
+import "mooncamp.com/dgraphtools/gql"
+
+// Filter is either a function or a combination of filters using And,
+// Or and Not.
+type Filter interface {
+	filterTree() gql.FilterTree
+}
+
+type filterTree gql.FilterTree
+
+func (f filterTree) filterTree() gql.FilterTree {
+	return gql.FilterTree(f)
+}
+
+// And combines filters with `and`.
+func And(filters ...Filter) Filter {
+	return combine("and", filters)
+}
+
+// Or combines filters with `or`.
+func Or(filters ...Filter) Filter {
+	return combine("or", filters)
+}
+
+// Not negates a filter.
+func Not(filter Filter) Filter {
+	return filterTree{Op: "not", Child: []gql.FilterTree{filter.filterTree()}}
+}
+
+// combine folds the filters into a left-leaning binary tree, the same
+// shape the GraphQL+- parser produces for `a and b and c`.
+func combine(op string, filters []Filter) Filter {
+	if len(filters) == 0 {
+		return filterTree{}
+	}
+
+	res := filters[0].filterTree()
+	for _, e := range filters[1:] {
+		res = gql.FilterTree{Op: op, Child: []gql.FilterTree{res, e.filterTree()}}
+	}
+
+	return filterTree(res)
+}
@@ -0,0 +1,167 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	"mooncamp.com/dgraphtools/gql"
+)
+
+// Func builds a gql.Function. Functions are used as root functions of
+// a block or as leaves of a filter.
+type Func struct {
+	fn gql.Function
+}
+
+// Fn creates an arbitrary function. The attribute may be given as
+// `pred@lang`, `count(pred)` or `val(name)`.
+func Fn(name, attr string, args ...string) *Func {
+	f := &Func{fn: gql.Function{Name: name}}
+	f.attr(attr)
+	return f.Args(args...)
+}
+
+// Eq creates `eq(attr, values...)`.
+func Eq(attr string, values ...string) *Func {
+	return Fn("eq", attr, values...)
+}
+
+// Le creates `le(attr, value)`.
+func Le(attr, value string) *Func {
+	return Fn("le", attr, value)
+}
+
+// Lt creates `lt(attr, value)`.
+func Lt(attr, value string) *Func {
+	return Fn("lt", attr, value)
+}
+
+// Ge creates `ge(attr, value)`.
+func Ge(attr, value string) *Func {
+	return Fn("ge", attr, value)
+}
+
+// Gt creates `gt(attr, value)`.
+func Gt(attr, value string) *Func {
+	return Fn("gt", attr, value)
+}
+
+// Has creates `has(attr)`.
+func Has(attr string) *Func {
+	return Fn("has", attr)
+}
+
+// AllOfTerms creates `allofterms(attr, terms)`.
+func AllOfTerms(attr, terms string) *Func {
+	return Fn("allofterms", attr, terms)
+}
+
+// AnyOfTerms creates `anyofterms(attr, terms)`.
+func AnyOfTerms(attr, terms string) *Func {
+	return Fn("anyofterms", attr, terms)
+}
+
+// AllOfText creates `alloftext(attr, text)`.
+func AllOfText(attr, text string) *Func {
+	return Fn("alloftext", attr, text)
+}
+
+// AnyOfText creates `anyoftext(attr, text)`.
+func AnyOfText(attr, text string) *Func {
+	return Fn("anyoftext", attr, text)
+}
+
+// Regexp creates `regexp(attr, /pattern/flags)`.
+func Regexp(attr, pattern, flags string) *Func {
+	return Fn("regexp", attr, pattern, flags)
+}
+
+// UID creates `uid(uids...)`.
+func UID(uids ...uint64) *Func {
+	return &Func{fn: gql.Function{Name: "uid", UID: uids}}
+}
+
+// UIDVar creates `uid(vars...)` referencing uid variables.
+func UIDVar(names ...string) *Func {
+	f := &Func{fn: gql.Function{Name: "uid"}}
+	for _, e := range names {
+		f.fn.NeedsVar = append(f.fn.NeedsVar, gql.VarContext{Name: e, Typ: uidVar})
+	}
+	return f
+}
+
+// Type creates `type(name)`. It requires Dgraph v1.1 or later.
+func Type(name string) *Func {
+	return &Func{fn: gql.Function{Name: "type", Args: []gql.Arg{{Value: name}}}}
+}
+
+// Between creates `between(attr, from, to)`. Filters are rewritten to
+// `ge` and `le` for Dgraph versions before v20.07.
+func Between(attr, from, to string) *Func {
+	return Fn("between", attr, from, to)
+}
+
+// UIDIn creates `uid_in(attr, uids...)`. Several uids require Dgraph
+// v21.03 or later.
+func UIDIn(attr string, uids ...uint64) *Func {
+	f := Fn("uid_in", attr)
+	for _, e := range uids {
+		f.Args(fmt.Sprintf("0x%x", e))
+	}
+	return f
+}
+
+// Args appends literal arguments.
+func (f *Func) Args(values ...string) *Func {
+	for _, e := range values {
+		f.fn.Args = append(f.fn.Args, gql.Arg{Value: e})
+	}
+	return f
+}
+
+// GraphQLVar appends a GraphQL variable argument, e.g. `$name`.
+func (f *Func) GraphQLVar(name string) *Func {
+	if !strings.HasPrefix(name, "$") {
+		name = "$" + name
+	}
+
+	f.fn.Args = append(f.fn.Args, gql.Arg{Value: name, IsGraphQLVar: true})
+	return f
+}
+
+// ValArg appends a value variable argument, e.g. `val(a)`.
+func (f *Func) ValArg(name string) *Func {
+	f.fn.Args = append(f.fn.Args, gql.Arg{Value: name, IsValueVar: true})
+	f.fn.NeedsVar = append(f.fn.NeedsVar, gql.VarContext{Name: name, Typ: valueVar})
+	return f
+}
+
+// Build returns the data representation of the function.
+func (f *Func) Build() *gql.Function {
+	fn := f.fn
+	return &fn
+}
+
+func (f *Func) filterTree() gql.FilterTree {
+	return gql.FilterTree{Func: f.Build()}
+}
+
+func (f *Func) attr(attr string) {
+	if pred, ok := unwrap("count", attr); ok {
+		f.fn.IsCount = true
+		attr = pred
+	}
+
+	if name, ok := unwrap("val", attr); ok {
+		f.fn.Attr = name
+		f.fn.IsValueVar = true
+		f.fn.NeedsVar = append(f.fn.NeedsVar, gql.VarContext{Name: name, Typ: valueVar})
+		return
+	}
+
+	pred, langs := parsePredicate(attr)
+	f.fn.Attr = pred
+	if len(langs) > 0 {
+		f.fn.Lang = langs[0]
+	}
+}
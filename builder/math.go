@@ -0,0 +1,86 @@
+package builder
+
+import "mooncamp.com/dgraphtools/gql"
+
+// floatID is the type id dgraph uses for float values. Math constants
+// are always parsed as floats.
+const floatID gql.TypeID = 3
+
+// MathExpr builds a gql.MathTree.
+type MathExpr struct {
+	mt gql.MathTree
+}
+
+// V references a value variable in a math expression.
+func V(name string) *MathExpr {
+	return &MathExpr{mt: gql.MathTree{Var: name}}
+}
+
+// C creates a constant in a math expression.
+func C(value float64) *MathExpr {
+	return &MathExpr{mt: gql.MathTree{Const: gql.Val{Tid: floatID, Value: value}}}
+}
+
+// MathFn applies a math function or operator to its operands.
+func MathFn(fn string, operands ...*MathExpr) *MathExpr {
+	mt := gql.MathTree{Fn: fn}
+	for _, e := range operands {
+		mt.Child = append(mt.Child, e.Build())
+	}
+	return &MathExpr{mt: mt}
+}
+
+// Add creates `a + b`.
+func Add(a, b *MathExpr) *MathExpr {
+	return MathFn("+", a, b)
+}
+
+// Sub creates `a - b`.
+func Sub(a, b *MathExpr) *MathExpr {
+	return MathFn("-", a, b)
+}
+
+// Mul creates `a * b`.
+func Mul(a, b *MathExpr) *MathExpr {
+	return MathFn("*", a, b)
+}
+
+// Div creates `a / b`.
+func Div(a, b *MathExpr) *MathExpr {
+	return MathFn("/", a, b)
+}
+
+// Mod creates `a % b`.
+func Mod(a, b *MathExpr) *MathExpr {
+	return MathFn("%", a, b)
+}
+
+// Neg creates `-a`.
+func Neg(a *MathExpr) *MathExpr {
+	return MathFn("u-", a)
+}
+
+// Ln creates `ln(a)`.
+func Ln(a *MathExpr) *MathExpr {
+	return MathFn("ln", a)
+}
+
+// Exp creates `exp(a)`.
+func Exp(a *MathExpr) *MathExpr {
+	return MathFn("exp", a)
+}
+
+// Sqrt creates `sqrt(a)`.
+func Sqrt(a *MathExpr) *MathExpr {
+	return MathFn("sqrt", a)
+}
+
+// Cond creates `cond(condition, then, otherwise)`.
+func Cond(condition, then, otherwise *MathExpr) *MathExpr {
+	return MathFn("cond", condition, then, otherwise)
+}
+
+// Build returns the data representation of the expression.
+func (e *MathExpr) Build() gql.MathTree {
+	return e.mt
+}
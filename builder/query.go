@@ -0,0 +1,53 @@
+package builder
+
+import (
+	"mooncamp.com/dgraphtools/gql"
+	"mooncamp.com/dgraphtools/render"
+)
+
+// QuerySet combines root blocks into a render.Query.
+type QuerySet struct {
+	blocks    []*Block
+	alias     string
+	variables map[string]string
+}
+
+// Queries creates a query set from root blocks.
+func Queries(blocks ...*Block) *QuerySet {
+	return &QuerySet{blocks: blocks}
+}
+
+// Alias names the query, e.g. `query me(...) { ... }`.
+func (s *QuerySet) Alias(alias string) *QuerySet {
+	s.alias = alias
+	return s
+}
+
+// Variable declares a GraphQL variable with its type, e.g.
+// Variable("$name", "string").
+func (s *QuerySet) Variable(name, typ string) *QuerySet {
+	if s.variables == nil {
+		s.variables = make(map[string]string)
+	}
+	s.variables[name] = typ
+	return s
+}
+
+// Build returns the query ready to be rendered.
+func (s *QuerySet) Build() render.Query {
+	queries := make([]gql.GraphQuery, 0, len(s.blocks))
+	for _, e := range s.blocks {
+		queries = append(queries, e.Build())
+	}
+
+	return render.Query{
+		Queries:   queries,
+		Alias:     s.alias,
+		Variables: s.variables,
+	}
+}
+
+// Render renders the query to its string representation.
+func (s *QuerySet) Render(opts ...render.Option) (string, error) {
+	return render.Render(s.Build(), opts...)
+}
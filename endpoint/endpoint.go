@@ -11,7 +11,45 @@ import (
 	"github.com/go-kit/kit/endpoint"
 )
 
-func Query(qh dgraphtools.QueryHandler) endpoint.Endpoint {
+// QueryOption configures the query endpoint.
+type QueryOption func(*queryOptions)
+
+type queryOptions struct {
+	extensions    extension.Chain
+	renderOptions []render.Option
+}
+
+// Extensions applies the given extensions in order instead of the
+// standard ones of the extension package.
+func Extensions(extensions ...extension.Extension) QueryOption {
+	return func(o *queryOptions) {
+		o.extensions = append(o.extensions, extensions...)
+	}
+}
+
+// RenderOptions renders the queries with the given options, e.g.
+// render.WithVersion for the version of the Dgraph cluster. Pass the
+// same options to render.TemplateErrorMiddleware.
+func RenderOptions(opts ...render.Option) QueryOption {
+	return func(o *queryOptions) {
+		o.renderOptions = append(o.renderOptions, opts...)
+	}
+}
+
+// Query renders the requested queries, runs them and applies the
+// extensions to the response. Failing extensions are reported as
+// extension.Error.
+func Query(qh dgraphtools.QueryHandler, opts ...QueryOption) endpoint.Endpoint {
+	o := queryOptions{}
+	for _, e := range opts {
+		e(&o)
+	}
+
+	chain := o.extensions
+	if len(chain) == 0 {
+		chain = extension.Standard()
+	}
+
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(dgraphtools.QueryRequest)
 
@@ -20,27 +58,31 @@ func Query(qh dgraphtools.QueryHandler) endpoint.Endpoint {
 			Alias:     req.Alias,
 			Variables: req.Variables,
 		}
-		renderedQuery, err := render.Render(q)
+		renderedQuery, err := render.Render(q, o.renderOptions...)
 		if err != nil {
-			return dgraphtools.QueryResponse{Error: err}, nil
+			return dgraphtools.QueryResponse{Error: dgraphtools.InvalidRequest{Err: err}}, nil
 		}
 
 		resp, err := qh.Query(ctx, renderedQuery, req.Variables)
 		if err != nil {
-			return dgraphtools.QueryResponse{Error: err}, nil
+			return dgraphtools.QueryResponse{Error: dgraphtools.Upstream(err)}, nil
 		}
 
 		var data map[string]interface{}
 		if err := json.Unmarshal(resp.GetJson(), &data); err != nil {
+			return dgraphtools.QueryResponse{Error: dgraphtools.UpstreamError{Err: err}}, nil
+		}
+
+		extendedData, err := chain.Apply(req.Queries, data)
+		if err != nil {
 			return dgraphtools.QueryResponse{Error: err}, nil
 		}
 
-		defaultedData := extension.ApplyDefaults(req.Queries, data)
-		defaultedJSON, err := json.Marshal(defaultedData)
+		extendedJSON, err := json.Marshal(extendedData)
 		if err != nil {
 			return dgraphtools.QueryResponse{Error: err}, nil
 		}
 
-		return dgraphtools.QueryResponse{Response: defaultedJSON, Error: err}, nil
+		return dgraphtools.QueryResponse{Response: extendedJSON, Error: err}, nil
 	}
 }
@@ -0,0 +1,40 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+
+	"mooncamp.com/dgraphtools"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// ResolveIdentity looks up the node of requests made by an external
+// identity, i.e. one with a subject but without uid. Requests of unknown
+// identities fail with dgraphtools.Unauthenticated.
+func ResolveIdentity(resolver dgraphtools.IdentityResolver) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			req := request.(dgraphtools.QueryRequest)
+			if req.Identity.UID != 0 || req.Identity.Subject == "" {
+				return next(ctx, req)
+			}
+
+			uid, err := resolver.ResolveIdentity(ctx, req.Identity)
+			if err != nil {
+				var unauthenticated dgraphtools.Unauthenticated
+				if errors.As(err, &unauthenticated) {
+					return dgraphtools.QueryResponse{Error: unauthenticated}, nil
+				}
+				return dgraphtools.QueryResponse{Error: dgraphtools.Upstream(err)}, nil
+			}
+
+			if uid == 0 {
+				return dgraphtools.QueryResponse{Error: dgraphtools.Unauthenticated{Reason: "unknown subject"}}, nil
+			}
+
+			req.Identity.UID = uid
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mooncamp.com/dgraphtools"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveIdentity(t *testing.T) {
+	resolver := dgraphtools.IdentityResolverFunc(func(ctx context.Context, identity dgraphtools.Identity) (uint64, error) {
+		switch {
+		case identity.Subject == "auth0|harry" && identity.Tenant == "hogwarts":
+			return 5, nil
+		case identity.Subject == "auth0|hermione":
+			return 0, dgraphtools.Unauthenticated{Reason: "blocked"}
+		case identity.Subject == "auth0|neville":
+			return 0, errors.New("connection refused")
+		}
+		return 0, nil
+	})
+
+	cases := []struct {
+		name     string
+		identity dgraphtools.Identity
+		expected dgraphtools.Identity
+		err      error
+	}{
+		{
+			name:     "subject",
+			identity: dgraphtools.Identity{Subject: "auth0|harry", Tenant: "hogwarts"},
+			expected: dgraphtools.Identity{UID: 5, Subject: "auth0|harry", Tenant: "hogwarts"},
+		},
+		{
+			name:     "uid",
+			identity: dgraphtools.Identity{UID: 1, Subject: "auth0|ron"},
+			expected: dgraphtools.Identity{UID: 1, Subject: "auth0|ron"},
+		},
+		{
+			name:     "unknown subject",
+			identity: dgraphtools.Identity{Subject: "auth0|ron"},
+			err:      dgraphtools.Unauthenticated{Reason: "unknown subject"},
+		},
+		{
+			name:     "unauthenticated",
+			identity: dgraphtools.Identity{Subject: "auth0|hermione"},
+			err:      dgraphtools.Unauthenticated{Reason: "blocked"},
+		},
+		{
+			name:     "resolver failure",
+			identity: dgraphtools.Identity{Subject: "auth0|neville"},
+			err:      dgraphtools.UpstreamError{Err: errors.New("connection refused")},
+		},
+	}
+
+	for _, e := range cases {
+		t.Run(e.name, func(t *testing.T) {
+			var actual dgraphtools.Identity
+			next := func(ctx context.Context, request interface{}) (interface{}, error) {
+				actual = request.(dgraphtools.QueryRequest).Identity
+				return dgraphtools.QueryResponse{}, nil
+			}
+
+			resp, err := ResolveIdentity(resolver)(next)(context.Background(), dgraphtools.QueryRequest{Identity: e.identity})
+			require.NoError(t, err)
+
+			if e.err != nil {
+				require.Equal(t, e.err, resp.(dgraphtools.QueryResponse).Error)
+				return
+			}
+			require.Equal(t, e.expected, actual)
+		})
+	}
+}
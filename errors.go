@@ -0,0 +1,96 @@
+package dgraphtools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// InvalidRequest is returned if a request is malformed or its queries
+// are invalid. Path points to the failing part of the queries if known,
+// e.g. `queries[0].children[2]`.
+type InvalidRequest struct {
+	Path string
+	Err  error
+}
+
+func (e InvalidRequest) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("invalid request: %v", e.Err)
+	}
+	return fmt.Sprintf("invalid request: %s: %v", e.Path, e.Err)
+}
+
+func (e InvalidRequest) Unwrap() error {
+	return e.Err
+}
+
+// Unauthenticated is returned if the identity of a request can't be
+// established, e.g. because its token is missing or invalid.
+type Unauthenticated struct {
+	Reason string
+}
+
+func (e Unauthenticated) Error() string {
+	return fmt.Sprintf("unauthenticated: %s", e.Reason)
+}
+
+// Unauthorized is returned if the identity of a request isn't allowed
+// to run its queries.
+type Unauthorized struct{}
+
+func (Unauthorized) Error() string {
+	return "unauthorized action"
+}
+
+// UpstreamTimeout is returned if Dgraph didn't answer in time.
+type UpstreamTimeout struct {
+	Err error
+}
+
+func (e UpstreamTimeout) Error() string {
+	return fmt.Sprintf("dgraph timed out: %v", e.Err)
+}
+
+func (e UpstreamTimeout) Unwrap() error {
+	return e.Err
+}
+
+// UpstreamError is returned if Dgraph failed to answer a query.
+type UpstreamError struct {
+	Err error
+}
+
+func (e UpstreamError) Error() string {
+	return fmt.Sprintf("dgraph failed: %v", e.Err)
+}
+
+func (e UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+// InternalError is returned if a request failed due to a bug, e.g. if
+// a rendered query can't be parsed again.
+type InternalError struct {
+	Err error
+}
+
+func (e InternalError) Error() string {
+	return fmt.Sprintf("internal error: %v", e.Err)
+}
+
+func (e InternalError) Unwrap() error {
+	return e.Err
+}
+
+// Upstream wraps an error returned by a QueryHandler into an
+// UpstreamTimeout or UpstreamError.
+func Upstream(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+		return UpstreamTimeout{Err: err}
+	}
+	return UpstreamError{Err: err}
+}
@@ -2,23 +2,21 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
-	"strconv"
+	"os"
 
 	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/auth"
 	"mooncamp.com/dgraphtools/endpoint"
-	"mooncamp.com/dgraphtools/gql"
 	"mooncamp.com/dgraphtools/proof"
 	"mooncamp.com/dgraphtools/render"
+	"mooncamp.com/dgraphtools/transport"
 
 	"log"
 
 	"github.com/dgraph-io/dgo"
 	"github.com/dgraph-io/dgo/protos/api"
 	gokitendpoint "github.com/go-kit/kit/endpoint"
-	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
 	"google.golang.org/grpc"
 )
@@ -46,6 +44,11 @@ func errFormatter(err error) interface{} {
 }
 
 func main() {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		log.Fatal("JWT_SECRET is not set")
+	}
+
 	conn, err := grpc.Dial("localhost:9080", grpc.WithInsecure())
 	if err != nil {
 		log.Fatalf("connect to dgraph: %v", err)
@@ -56,56 +59,40 @@ func main() {
 
 	handler := mux.NewRouter()
 
-	verifier := &proof.Proof{&queryHandler{dg: dg}}
+	// DGRAPH_VERSION selects the query syntax, e.g. v21.03.
+	version := render.DefaultVersion
+	if v := os.Getenv("DGRAPH_VERSION"); v != "" {
+		version, err = render.ParseVersion(v)
+		if err != nil {
+			log.Fatalf("dgraph version: %v", err)
+		}
+	}
+	renderOptions := []render.Option{render.WithVersion(version)}
+
+	verifier := &proof.Proof{QueryHandler: &queryHandler{dg: dg}, RenderOptions: renderOptions}
+
+	// The tokens carry the uid of the user's node in the uid claim.
+	authenticator := &auth.JWT{
+		Keys:     map[string]interface{}{"": []byte(secret)},
+		UIDClaim: "uid",
+	}
 
 	var queryEndpoint gokitendpoint.Endpoint
 	{
-		queryEndpoint = endpoint.Query(&queryHandler{dg: dg})
+		queryEndpoint = endpoint.Query(&queryHandler{dg: dg}, endpoint.RenderOptions(renderOptions...))
 		queryEndpoint = proof.Middleware(verifier)(queryEndpoint)
-		queryEndpoint = render.TemplateErrorMiddleware(queryReader, errFormatter)(queryEndpoint)
+		queryEndpoint = render.TemplateErrorMiddleware(queryReader, errFormatter, renderOptions...)(queryEndpoint)
+	}
+
+	identity := func(ctx context.Context, r *http.Request) (dgraphtools.Identity, error) {
+		token, ok := transport.BearerToken(r)
+		if !ok {
+			return dgraphtools.Identity{}, dgraphtools.Unauthenticated{Reason: "missing token"}
+		}
+		return authenticator.Identity(token)
 	}
 
-	queryHandler := httptransport.NewServer(
-		queryEndpoint,
-		func(ctx context.Context, r *http.Request) (request interface{}, err error) {
-			userID, _ := r.Cookie("userid")
-
-			req := struct {
-				Queries   []gql.GraphQuery       `json:"queries"`
-				Alias     string                 `json:"alias"`
-				Variables map[string]string      `json:"variables"`
-				Proof     map[int]gql.GraphQuery `json:"proof"`
-			}{}
-			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-				return nil, err
-			}
-
-			id, err := strconv.ParseInt(userID.Value, 0, 64)
-			if err != nil {
-				return nil, err
-			}
-
-			return dgraphtools.QueryRequest{
-				Queries:   req.Queries,
-				Alias:     req.Alias,
-				Variables: req.Variables,
-				Proof:     req.Proof,
-				Identity:  int(id),
-			}, nil
-
-		},
-		func(ctx context.Context, w http.ResponseWriter, response interface{}) error {
-			resp := response.(dgraphtools.QueryResponse)
-
-			if resp.Error != nil {
-				http.Error(w, fmt.Sprintf("%v", resp.Error), http.StatusInternalServerError)
-				return nil
-			}
-
-			_, err := w.Write(resp.Response)
-			return err
-		},
-	)
+	queryHandler := transport.NewHTTPHandler(queryEndpoint, transport.ExtractIdentity(identity))
 
 	handler.Handle("/query", queryHandler)
 }
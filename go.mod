@@ -15,6 +15,7 @@ require (
 	github.com/go-kit/kit v0.8.0
 	github.com/go-logfmt/logfmt v0.4.0 // indirect
 	github.com/golang/geo v0.0.0-20181008215305-476085157cff // indirect
+	github.com/golang/protobuf v1.2.0
 	github.com/google/pprof v0.0.0-20190109223431-e84dfd68c163 // indirect
 	github.com/google/uuid v1.1.0 // indirect
 	github.com/gorilla/mux v1.6.2
@@ -31,6 +32,8 @@ require (
 	go.opencensus.io v0.18.0 // indirect
 	golang.org/x/arch v0.0.0-20181203225421-5a4828bb7045 // indirect
 	golang.org/x/crypto v0.0.0-20190103213133-ff983b9c42bc // indirect
-	golang.org/x/net v0.0.0-20190110200230-915654e7eabc // indirect
+	golang.org/x/net v0.0.0-20190110200230-915654e7eabc
+	google.golang.org/genproto v0.0.0-20180831171423-11092d34479b
 	google.golang.org/grpc v1.18.0
+	gopkg.in/yaml.v2 v2.2.4
 )
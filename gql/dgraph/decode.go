@@ -0,0 +1,285 @@
+// Package dgraph converts queries between their data representation
+// and the types of dgraph's gql package.
+package dgraph
+
+import (
+	"mooncamp.com/dgraphtools/gql"
+
+	dgraphgql "github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+)
+
+// DecodeGraphQuery converts a block from dgraph's representation. Empty
+// lists and maps are decoded to nil and nil elements of lists are
+// skipped. IsEmpty is implied by the missing root function.
+func DecodeGraphQuery(source *dgraphgql.GraphQuery) gql.GraphQuery {
+	if source == nil {
+		return gql.GraphQuery{}
+	}
+
+	return gql.GraphQuery{
+		UID:        decodeUIDs(source.UID),
+		Attr:       source.Attr,
+		Langs:      decodeStrings(source.Langs),
+		Alias:      source.Alias,
+		IsCount:    source.IsCount,
+		IsInternal: source.IsInternal,
+		IsGroupby:  source.IsGroupby,
+		Var:        source.Var,
+		NeedsVar:   decodeVarContexts(source.NeedsVar),
+		Func:       decodeFunc(source.Func),
+		Expand:     source.Expand,
+
+		Args:          decodeStringMap(source.Args),
+		Order:         decodeOrders(source.Order),
+		Children:      DecodeGraphQueries(source.Children),
+		Filter:        decodeFilterTree(source.Filter),
+		MathExp:       decodeMathTree(source.MathExp),
+		Normalize:     source.Normalize,
+		Recurse:       source.Recurse,
+		RecurseArgs:   gql.RecurseArgs(source.RecurseArgs),
+		Cascade:       source.Cascade,
+		IgnoreReflex:  source.IgnoreReflex,
+		Facets:        decodeFacetParams(source.Facets),
+		FacetsFilter:  decodeFilterTree(source.FacetsFilter),
+		GroupbyAttrs:  decodeGroupByAttrs(source.GroupbyAttrs),
+		FacetVar:      decodeStringMap(source.FacetVar),
+		FacetOrder:    source.FacetOrder,
+		FacetDesc:     source.FacetDesc,
+		UidCount:      source.UidCount,
+		UidCountAlias: source.UidCountAlias,
+	}
+}
+
+func decodeUIDs(source []uint64) []uint64 {
+	if len(source) == 0 {
+		return nil
+	}
+
+	return source
+}
+
+func decodeStrings(source []string) []string {
+	if len(source) == 0 {
+		return nil
+	}
+
+	return source
+}
+
+func decodeStringMap(source map[string]string) map[string]string {
+	if len(source) == 0 {
+		return nil
+	}
+
+	return source
+}
+
+func decodeGroupByAttrs(source []dgraphgql.GroupByAttr) []gql.GroupByAttr {
+	if len(source) == 0 {
+		return nil
+	}
+
+	attrs := make([]gql.GroupByAttr, 0, len(source))
+	for _, e := range source {
+		attrs = append(attrs, decodeGroupByAttr(e))
+	}
+	return attrs
+}
+
+func decodeGroupByAttr(source dgraphgql.GroupByAttr) gql.GroupByAttr {
+	return gql.GroupByAttr{
+		Attr:  source.Attr,
+		Alias: source.Alias,
+		Langs: decodeStrings(source.Langs),
+	}
+}
+
+func decodeFacetParams(source *pb.FacetParams) *gql.FacetParams {
+	if source == nil {
+		return nil
+	}
+
+	return &gql.FacetParams{
+		AllKeys: source.AllKeys,
+		Param:   decodeFacetParamSlice(source.Param),
+	}
+}
+
+func decodeFacetParamSlice(source []*pb.FacetParam) []gql.FacetParam {
+	var params []gql.FacetParam
+	for _, e := range source {
+		if e == nil {
+			continue
+		}
+
+		params = append(
+			params,
+			gql.FacetParam{
+				Key:   e.Key,
+				Alias: e.Alias,
+			},
+		)
+	}
+	return params
+}
+
+func decodeMathTree(source *dgraphgql.MathTree) *gql.MathTree {
+	if source == nil {
+		return nil
+	}
+
+	return &gql.MathTree{
+		Fn:    source.Fn,
+		Var:   source.Var,
+		Const: decodeVal(source.Const),
+		Val:   decodeVals(source.Val),
+		Child: decodeMathTrees(source.Child),
+	}
+}
+
+func decodeVal(source types.Val) gql.Val {
+	return gql.Val{
+		Tid:   gql.TypeID(source.Tid),
+		Value: source.Value,
+	}
+}
+
+func decodeVals(source map[uint64]types.Val) map[uint64]gql.Val {
+	if len(source) == 0 {
+		return nil
+	}
+
+	vals := make(map[uint64]gql.Val, len(source))
+	for k, v := range source {
+		vals[k] = decodeVal(v)
+	}
+
+	return vals
+}
+
+func decodeMathTrees(source []*dgraphgql.MathTree) []gql.MathTree {
+	var trees []gql.MathTree
+	for _, e := range source {
+		if e == nil {
+			continue
+		}
+		trees = append(trees, *decodeMathTree(e))
+	}
+	return trees
+}
+
+func decodeFilterTree(source *dgraphgql.FilterTree) *gql.FilterTree {
+	if source == nil {
+		return nil
+	}
+
+	return &gql.FilterTree{
+		Op:    source.Op,
+		Child: decodeFilterTrees(source.Child),
+		Func:  decodeFunc(source.Func),
+	}
+}
+
+func decodeFilterTrees(source []*dgraphgql.FilterTree) []gql.FilterTree {
+	var filterTrees []gql.FilterTree
+	for _, e := range source {
+		if e == nil {
+			continue
+		}
+		filterTrees = append(filterTrees, *decodeFilterTree(e))
+	}
+	return filterTrees
+}
+
+// DecodeGraphQueries converts blocks from dgraph's representation, nil
+// blocks are skipped.
+func DecodeGraphQueries(source []*dgraphgql.GraphQuery) []gql.GraphQuery {
+	var graphQueries []gql.GraphQuery
+	for _, e := range source {
+		if e == nil {
+			continue
+		}
+		graphQueries = append(graphQueries, DecodeGraphQuery(e))
+	}
+
+	return graphQueries
+}
+
+func decodeOrders(source []*pb.Order) []gql.Order {
+	var orders []gql.Order
+	for _, e := range source {
+		if e == nil {
+			continue
+		}
+		orders = append(orders, decodeOrder(e))
+	}
+
+	return orders
+}
+
+func decodeOrder(source *pb.Order) gql.Order {
+	return gql.Order{
+		Attr:  source.Attr,
+		Desc:  source.Desc,
+		Langs: decodeStrings(source.Langs),
+	}
+}
+
+func decodeVarContexts(source []dgraphgql.VarContext) []gql.VarContext {
+	if len(source) == 0 {
+		return nil
+	}
+	varContexts := make([]gql.VarContext, 0, len(source))
+	for _, e := range source {
+		varContexts = append(varContexts, decodeVarContext(e))
+	}
+
+	return varContexts
+}
+
+func decodeVarContext(source dgraphgql.VarContext) gql.VarContext {
+	return gql.VarContext{
+		Name: source.Name,
+		Typ:  source.Typ,
+	}
+}
+
+func decodeArg(source dgraphgql.Arg) gql.Arg {
+	return gql.Arg{
+		Value:        source.Value,
+		IsValueVar:   source.IsValueVar,
+		IsGraphQLVar: source.IsGraphQLVar,
+	}
+}
+
+func decodeArgs(source []dgraphgql.Arg) []gql.Arg {
+	if len(source) == 0 {
+		return nil
+	}
+
+	args := make([]gql.Arg, 0, len(source))
+	for _, e := range source {
+		args = append(args, decodeArg(e))
+	}
+
+	return args
+}
+
+func decodeFunc(source *dgraphgql.Function) *gql.Function {
+	if source == nil {
+		return nil
+	}
+
+	return &gql.Function{
+		Attr:       source.Attr,
+		Lang:       source.Lang,
+		Name:       source.Name,
+		Args:       decodeArgs(source.Args),
+		UID:        decodeUIDs(source.UID),
+		NeedsVar:   decodeVarContexts(source.NeedsVar),
+		IsCount:    source.IsCount,
+		IsValueVar: source.IsValueVar,
+	}
+}
@@ -0,0 +1,320 @@
+package dgraph
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"mooncamp.com/dgraphtools/gql"
+
+	dgraphgql "github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/stretchr/testify/require"
+)
+
+// generator creates random queries setting every field dgraph v1.0
+// knows about. Empty lists and maps are always nil.
+type generator struct {
+	*rand.Rand
+}
+
+func (g generator) bool() bool {
+	return g.Intn(2) == 0
+}
+
+func (g generator) string() string {
+	if g.bool() {
+		return ""
+	}
+	return fmt.Sprintf("s%d", g.Intn(100))
+}
+
+func (g generator) n() int {
+	if g.Intn(3) == 0 {
+		return 0
+	}
+	return 1 + g.Intn(3)
+}
+
+func (g generator) strings() []string {
+	var res []string
+	for i := g.n(); i > 0; i-- {
+		res = append(res, g.string())
+	}
+	return res
+}
+
+func (g generator) stringMap() map[string]string {
+	n := g.n()
+	if n == 0 {
+		return nil
+	}
+
+	res := make(map[string]string, n)
+	for ; n > 0; n-- {
+		res[fmt.Sprintf("k%d", g.Intn(100))] = g.string()
+	}
+	return res
+}
+
+func (g generator) uids() []uint64 {
+	var res []uint64
+	for i := g.n(); i > 0; i-- {
+		res = append(res, g.Uint64())
+	}
+	return res
+}
+
+func (g generator) varContexts() []gql.VarContext {
+	var res []gql.VarContext
+	for i := g.n(); i > 0; i-- {
+		res = append(res, gql.VarContext{Name: g.string(), Typ: g.Intn(3)})
+	}
+	return res
+}
+
+func (g generator) function() *gql.Function {
+	if g.bool() {
+		return nil
+	}
+
+	var args []gql.Arg
+	for i := g.n(); i > 0; i-- {
+		args = append(args, gql.Arg{Value: g.string(), IsValueVar: g.bool(), IsGraphQLVar: g.bool()})
+	}
+
+	return &gql.Function{
+		Attr:       g.string(),
+		Lang:       g.string(),
+		Name:       g.string(),
+		Args:       args,
+		UID:        g.uids(),
+		NeedsVar:   g.varContexts(),
+		IsCount:    g.bool(),
+		IsValueVar: g.bool(),
+	}
+}
+
+func (g generator) filterTree(depth int) *gql.FilterTree {
+	if depth == 0 || g.bool() {
+		return nil
+	}
+
+	var children []gql.FilterTree
+	for i := g.n(); i > 0; i-- {
+		if child := g.filterTree(depth - 1); child != nil {
+			children = append(children, *child)
+		}
+	}
+
+	return &gql.FilterTree{Op: g.string(), Child: children, Func: g.function()}
+}
+
+func (g generator) val() gql.Val {
+	if g.bool() {
+		return gql.Val{}
+	}
+	return gql.Val{Tid: gql.TypeID(g.Intn(10)), Value: g.Float64()}
+}
+
+func (g generator) mathTree(depth int) *gql.MathTree {
+	if depth == 0 || g.bool() {
+		return nil
+	}
+
+	var vals map[uint64]gql.Val
+	for i := g.n(); i > 0; i-- {
+		if vals == nil {
+			vals = make(map[uint64]gql.Val)
+		}
+		vals[g.Uint64()] = g.val()
+	}
+
+	var children []gql.MathTree
+	for i := g.n(); i > 0; i-- {
+		if child := g.mathTree(depth - 1); child != nil {
+			children = append(children, *child)
+		}
+	}
+
+	return &gql.MathTree{Fn: g.string(), Var: g.string(), Const: g.val(), Val: vals, Child: children}
+}
+
+func (g generator) orders() []gql.Order {
+	var res []gql.Order
+	for i := g.n(); i > 0; i-- {
+		res = append(res, gql.Order{Attr: g.string(), Desc: g.bool(), Langs: g.strings()})
+	}
+	return res
+}
+
+func (g generator) facetParams() *gql.FacetParams {
+	if g.bool() {
+		return nil
+	}
+
+	var params []gql.FacetParam
+	for i := g.n(); i > 0; i-- {
+		params = append(params, gql.FacetParam{Key: g.string(), Alias: g.string()})
+	}
+	return &gql.FacetParams{AllKeys: g.bool(), Param: params}
+}
+
+func (g generator) groupByAttrs() []gql.GroupByAttr {
+	var res []gql.GroupByAttr
+	for i := g.n(); i > 0; i-- {
+		res = append(res, gql.GroupByAttr{Attr: g.string(), Alias: g.string(), Langs: g.strings()})
+	}
+	return res
+}
+
+func (g generator) graphQuery(depth int) gql.GraphQuery {
+	var children []gql.GraphQuery
+	if depth > 0 {
+		for i := g.n(); i > 0; i-- {
+			children = append(children, g.graphQuery(depth-1))
+		}
+	}
+
+	return gql.GraphQuery{
+		UID:           g.uids(),
+		Attr:          g.string(),
+		Langs:         g.strings(),
+		Alias:         g.string(),
+		IsCount:       g.bool(),
+		IsInternal:    g.bool(),
+		IsGroupby:     g.bool(),
+		Var:           g.string(),
+		NeedsVar:      g.varContexts(),
+		Func:          g.function(),
+		Expand:        g.string(),
+		Args:          g.stringMap(),
+		Order:         g.orders(),
+		Children:      children,
+		Filter:        g.filterTree(3),
+		MathExp:       g.mathTree(3),
+		Normalize:     g.bool(),
+		Recurse:       g.bool(),
+		RecurseArgs:   gql.RecurseArgs{Depth: uint64(g.Intn(5)), AllowLoop: g.bool()},
+		Cascade:       g.bool(),
+		IgnoreReflex:  g.bool(),
+		Facets:        g.facetParams(),
+		FacetsFilter:  g.filterTree(3),
+		GroupbyAttrs:  g.groupByAttrs(),
+		FacetVar:      g.stringMap(),
+		FacetOrder:    g.string(),
+		FacetDesc:     g.bool(),
+		UidCount:      g.bool(),
+		UidCountAlias: g.string(),
+	}
+}
+
+// denormalize replaces nil lists and maps by empty ones, the way dgraph's
+// parser leaves them.
+func denormalize(gq *dgraphgql.GraphQuery) {
+	if gq.UID == nil {
+		gq.UID = []uint64{}
+	}
+	if gq.Langs == nil {
+		gq.Langs = []string{}
+	}
+	if gq.Args == nil {
+		gq.Args = map[string]string{}
+	}
+	if gq.FacetVar == nil {
+		gq.FacetVar = map[string]string{}
+	}
+	if gq.Order == nil {
+		gq.Order = []*pb.Order{}
+	}
+	if gq.Func != nil && gq.Func.Args == nil {
+		gq.Func.Args = []dgraphgql.Arg{}
+	}
+	if gq.Facets != nil && gq.Facets.Param == nil {
+		gq.Facets.Param = []*pb.FacetParam{}
+	}
+	if gq.MathExp != nil && gq.MathExp.Child == nil {
+		gq.MathExp.Child = []*dgraphgql.MathTree{}
+	}
+	if gq.Filter != nil && gq.Filter.Child == nil {
+		gq.Filter.Child = []*dgraphgql.FilterTree{}
+	}
+
+	if gq.Children == nil {
+		gq.Children = []*dgraphgql.GraphQuery{}
+	}
+	for _, e := range gq.Children {
+		denormalize(e)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	g := generator{rand.New(rand.NewSource(1))}
+	for i := 0; i < 1000; i++ {
+		gq := g.graphQuery(3)
+		require.Equal(t, gq, DecodeGraphQuery(EncodeGraphQuery(gq)), "query %d", i)
+
+		encoded := EncodeGraphQuery(gq)
+		denormalized := EncodeGraphQuery(gq)
+		denormalize(denormalized)
+		require.Equal(t, encoded, EncodeGraphQuery(DecodeGraphQuery(denormalized)), "query %d", i)
+	}
+}
+
+func TestRoundTripEmpty(t *testing.T) {
+	gq := gql.GraphQuery{
+		UID:      []uint64{},
+		Args:     map[string]string{},
+		Func:     &gql.Function{Name: "uid", Args: []gql.Arg{}},
+		Filter:   &gql.FilterTree{Op: "and", Child: []gql.FilterTree{}},
+		MathExp:  &gql.MathTree{Fn: "+", Val: map[uint64]gql.Val{}},
+		Facets:   &gql.FacetParams{Param: []gql.FacetParam{}},
+		Children: []gql.GraphQuery{{Attr: "name", Langs: []string{}}},
+	}
+
+	require.True(t, gql.Equal(gq, DecodeGraphQuery(EncodeGraphQuery(gq))))
+}
+
+func TestNilSafe(t *testing.T) {
+	require.NotPanics(t, func() {
+		EncodeGraphQueries([]gql.GraphQuery{{Alias: "me", Children: []gql.GraphQuery{{Attr: "name"}}}})
+	})
+
+	require.Equal(t, gql.GraphQuery{}, DecodeGraphQuery(nil))
+	require.Equal(t, []gql.GraphQuery{{Attr: "name"}}, DecodeGraphQueries([]*dgraphgql.GraphQuery{nil, {Attr: "name"}}))
+	require.Equal(t, &gql.FilterTree{Op: "and"}, decodeFilterTree(&dgraphgql.FilterTree{Op: "and", Child: []*dgraphgql.FilterTree{nil}}))
+}
+
+func TestEncodeIsEmpty(t *testing.T) {
+	table := []struct {
+		name    string
+		gq      gql.GraphQuery
+		isEmpty bool
+	}{
+		{
+			name:    "func",
+			gq:      gql.GraphQuery{Alias: "me", Func: &gql.Function{Name: "uid"}, UID: []uint64{1}},
+			isEmpty: false,
+		},
+		{
+			name:    "args",
+			gq:      gql.GraphQuery{Alias: "shortest", Args: map[string]string{"from": "0x1", "to": "0x2"}},
+			isEmpty: false,
+		},
+		{
+			name:    "aggregate",
+			gq:      gql.GraphQuery{Alias: "me", Children: []gql.GraphQuery{{Attr: "val", NeedsVar: []gql.VarContext{{Name: "a", Typ: 2}}}}},
+			isEmpty: true,
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			encoded := EncodeGraphQuery(e.gq)
+			require.Equal(t, e.isEmpty, encoded.IsEmpty)
+			for _, child := range encoded.Children {
+				require.False(t, child.IsEmpty)
+			}
+		})
+	}
+}
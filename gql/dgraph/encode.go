@@ -0,0 +1,304 @@
+package dgraph
+
+import (
+	"mooncamp.com/dgraphtools/gql"
+
+	dgraphgql "github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+)
+
+// EncodeGraphQuery converts a root block to dgraph's representation.
+// The extension fields and CascadeFields have no counterpart in dgraph
+// v1.0 and are dropped.
+func EncodeGraphQuery(source gql.GraphQuery) *dgraphgql.GraphQuery {
+	res := encodeGraphQuery(source)
+	// Dgraph marks root blocks without a function or arguments as empty,
+	// they only aggregate variables of other blocks.
+	res.IsEmpty = res.Func == nil && len(res.NeedsVar) == 0 && len(res.Args) == 0
+	return res
+}
+
+func encodeGraphQuery(source gql.GraphQuery) *dgraphgql.GraphQuery {
+	return &dgraphgql.GraphQuery{
+		UID:        encodeUIDs(source.UID),
+		Attr:       source.Attr,
+		Langs:      encodeStrings(source.Langs),
+		Alias:      source.Alias,
+		IsCount:    source.IsCount,
+		IsInternal: source.IsInternal,
+		IsGroupby:  source.IsGroupby,
+		Var:        source.Var,
+		NeedsVar:   encodeVarContexts(source.NeedsVar),
+		Func:       encodeFunc(source.Func),
+		Expand:     source.Expand,
+
+		Args:          encodeStringMap(source.Args),
+		Order:         encodeOrders(source.Order),
+		Children:      encodeChildren(source.Children),
+		Filter:        encodeFilterTree(source.Filter),
+		MathExp:       encodeMathTree(source.MathExp),
+		Normalize:     source.Normalize,
+		Recurse:       source.Recurse,
+		RecurseArgs:   dgraphgql.RecurseArgs(source.RecurseArgs),
+		Cascade:       source.Cascade,
+		IgnoreReflex:  source.IgnoreReflex,
+		Facets:        encodeFacetParams(source.Facets),
+		FacetsFilter:  encodeFilterTree(source.FacetsFilter),
+		GroupbyAttrs:  encodeGroupByAttrs(source.GroupbyAttrs),
+		FacetVar:      encodeStringMap(source.FacetVar),
+		FacetOrder:    source.FacetOrder,
+		FacetDesc:     source.FacetDesc,
+		UidCount:      source.UidCount,
+		UidCountAlias: source.UidCountAlias,
+	}
+}
+
+func encodeUIDs(source []uint64) []uint64 {
+	if len(source) == 0 {
+		return nil
+	}
+
+	return source
+}
+
+func encodeStrings(source []string) []string {
+	if len(source) == 0 {
+		return nil
+	}
+
+	return source
+}
+
+func encodeStringMap(source map[string]string) map[string]string {
+	if len(source) == 0 {
+		return nil
+	}
+
+	return source
+}
+
+func encodeGroupByAttrs(source []gql.GroupByAttr) []dgraphgql.GroupByAttr {
+	if len(source) == 0 {
+		return nil
+	}
+
+	attrs := make([]dgraphgql.GroupByAttr, 0, len(source))
+	for _, e := range source {
+		attrs = append(attrs, encodeGroupByAttr(e))
+	}
+	return attrs
+}
+
+func encodeGroupByAttr(source gql.GroupByAttr) dgraphgql.GroupByAttr {
+	return dgraphgql.GroupByAttr{
+		Attr:  source.Attr,
+		Alias: source.Alias,
+		Langs: encodeStrings(source.Langs),
+	}
+}
+
+func encodeFacetParams(source *gql.FacetParams) *pb.FacetParams {
+	if source == nil {
+		return nil
+	}
+
+	return &pb.FacetParams{
+		AllKeys: source.AllKeys,
+		Param:   encodeFacetParamSlice(source.Param),
+	}
+}
+
+func encodeFacetParamSlice(source []gql.FacetParam) []*pb.FacetParam {
+	if len(source) == 0 {
+		return nil
+	}
+
+	params := make([]*pb.FacetParam, 0, len(source))
+	for _, e := range source {
+		params = append(
+			params,
+			&pb.FacetParam{
+				Key:   e.Key,
+				Alias: e.Alias,
+			},
+		)
+	}
+	return params
+}
+
+func encodeMathTree(source *gql.MathTree) *dgraphgql.MathTree {
+	if source == nil {
+		return nil
+	}
+
+	return &dgraphgql.MathTree{
+		Fn:    source.Fn,
+		Var:   source.Var,
+		Const: encodeVal(source.Const),
+		Val:   encodeVals(source.Val),
+		Child: encodeMathTrees(source.Child),
+	}
+}
+
+func encodeVal(source gql.Val) types.Val {
+	return types.Val{
+		Tid:   types.TypeID(source.Tid),
+		Value: source.Value,
+	}
+}
+
+func encodeVals(source map[uint64]gql.Val) map[uint64]types.Val {
+	if len(source) == 0 {
+		return nil
+	}
+
+	vals := make(map[uint64]types.Val, len(source))
+	for k, v := range source {
+		vals[k] = encodeVal(v)
+	}
+
+	return vals
+}
+
+func encodeMathTrees(source []gql.MathTree) []*dgraphgql.MathTree {
+	if len(source) == 0 {
+		return nil
+	}
+
+	trees := make([]*dgraphgql.MathTree, 0, len(source))
+	for i := range source {
+		trees = append(trees, encodeMathTree(&source[i]))
+	}
+	return trees
+}
+
+func encodeFilterTree(source *gql.FilterTree) *dgraphgql.FilterTree {
+	if source == nil {
+		return nil
+	}
+
+	return &dgraphgql.FilterTree{
+		Op:    source.Op,
+		Child: encodeFilterTrees(source.Child),
+		Func:  encodeFunc(source.Func),
+	}
+}
+
+func encodeFilterTrees(source []gql.FilterTree) []*dgraphgql.FilterTree {
+	if len(source) == 0 {
+		return nil
+	}
+
+	filterTrees := make([]*dgraphgql.FilterTree, 0, len(source))
+	for i := range source {
+		filterTrees = append(filterTrees, encodeFilterTree(&source[i]))
+	}
+	return filterTrees
+}
+
+// EncodeGraphQueries converts root blocks to dgraph's representation.
+func EncodeGraphQueries(source []gql.GraphQuery) []*dgraphgql.GraphQuery {
+	if len(source) == 0 {
+		return nil
+	}
+
+	graphQueries := make([]*dgraphgql.GraphQuery, 0, len(source))
+	for _, e := range source {
+		graphQueries = append(graphQueries, EncodeGraphQuery(e))
+	}
+
+	return graphQueries
+}
+
+func encodeChildren(source []gql.GraphQuery) []*dgraphgql.GraphQuery {
+	if len(source) == 0 {
+		return nil
+	}
+
+	children := make([]*dgraphgql.GraphQuery, 0, len(source))
+	for _, e := range source {
+		children = append(children, encodeGraphQuery(e))
+	}
+
+	return children
+}
+
+func encodeOrders(source []gql.Order) []*pb.Order {
+	if len(source) == 0 {
+		return nil
+	}
+
+	orders := make([]*pb.Order, 0, len(source))
+	for _, e := range source {
+		orders = append(orders, encodeOrder(e))
+	}
+
+	return orders
+}
+
+func encodeOrder(source gql.Order) *pb.Order {
+	return &pb.Order{
+		Attr:  source.Attr,
+		Desc:  source.Desc,
+		Langs: encodeStrings(source.Langs),
+	}
+}
+
+func encodeVarContexts(source []gql.VarContext) []dgraphgql.VarContext {
+	if len(source) == 0 {
+		return nil
+	}
+
+	varContexts := make([]dgraphgql.VarContext, 0, len(source))
+	for _, e := range source {
+		varContexts = append(varContexts, encodeVarContext(e))
+	}
+
+	return varContexts
+}
+
+func encodeVarContext(source gql.VarContext) dgraphgql.VarContext {
+	return dgraphgql.VarContext{
+		Name: source.Name,
+		Typ:  source.Typ,
+	}
+}
+
+func encodeArg(source gql.Arg) dgraphgql.Arg {
+	return dgraphgql.Arg{
+		Value:        source.Value,
+		IsValueVar:   source.IsValueVar,
+		IsGraphQLVar: source.IsGraphQLVar,
+	}
+}
+
+func encodeArgs(source []gql.Arg) []dgraphgql.Arg {
+	if len(source) == 0 {
+		return nil
+	}
+
+	args := make([]dgraphgql.Arg, 0, len(source))
+	for _, e := range source {
+		args = append(args, encodeArg(e))
+	}
+
+	return args
+}
+
+func encodeFunc(source *gql.Function) *dgraphgql.Function {
+	if source == nil {
+		return nil
+	}
+
+	return &dgraphgql.Function{
+		Attr:       source.Attr,
+		Lang:       source.Lang,
+		Name:       source.Name,
+		Args:       encodeArgs(source.Args),
+		UID:        encodeUIDs(source.UID),
+		NeedsVar:   encodeVarContexts(source.NeedsVar),
+		IsCount:    source.IsCount,
+		IsValueVar: source.IsValueVar,
+	}
+}
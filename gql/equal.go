@@ -0,0 +1,303 @@
+package gql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+)
+
+// Clone returns a deep copy of gq. Default values, extension settings and
+// constants of math trees are copied by assignment, as their types
+// aren't known.
+func (gq GraphQuery) Clone() GraphQuery {
+	return cloner{}.graphQuery(gq)
+}
+
+// Equal reports whether a and b are semantically equal. Unlike
+// reflect.DeepEqual nil and empty slices or maps are considered equal.
+func Equal(a, b GraphQuery) bool {
+	n := cloner{normalize: true}
+	return reflect.DeepEqual(n.graphQuery(a), n.graphQuery(b))
+}
+
+// EqualQueries reports whether a and b contain equal queries in the same
+// order.
+func EqualQueries(a, b []GraphQuery) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns a stable hash of queries, e.g. to be used as cache key.
+// Semantically equal queries have the same hash. It fails if a default
+// value can't be encoded to JSON.
+func Hash(queries ...GraphQuery) (string, error) {
+	n := cloner{normalize: true, typed: true}
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, e := range queries {
+		// Map keys are sorted by the encoder and empty values are
+		// omitted, which makes the encoding canonical.
+		if err := enc.Encode(n.graphQuery(e)); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cloner deep copies queries. If normalize is set empty slices and maps
+// are replaced by nil. If typed is set values of unknown type are
+// wrapped in a typedValue.
+type cloner struct {
+	normalize bool
+	typed     bool
+}
+
+// typedValue keeps the type of a value in its JSON encoding, as e.g.
+// int64(1), uint64(1) and 1.0 are all encoded as 1.
+type typedValue struct {
+	Type  string      `json:"t"`
+	Value interface{} `json:"v"`
+}
+
+func (c cloner) value(v interface{}) interface{} {
+	if !c.typed || v == nil {
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+	res := typedValue{Type: rv.Type().String(), Value: v}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			break
+		}
+
+		s := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			s = append(s, c.value(rv.Index(i).Interface()))
+		}
+		res.Value = s
+	case reflect.Map:
+		if rv.IsNil() || rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		m := make(map[string]interface{}, rv.Len())
+		for _, k := range rv.MapKeys() {
+			m[k.String()] = c.value(rv.MapIndex(k).Interface())
+		}
+		res.Value = m
+	}
+
+	return res
+}
+
+func (c cloner) graphQuery(gq GraphQuery) GraphQuery {
+	gq.Default = c.value(gq.Default)
+	gq.UID = c.uids(gq.UID)
+	gq.Langs = c.strings(gq.Langs)
+	gq.NeedsVar = c.varContexts(gq.NeedsVar)
+	gq.Func = c.function(gq.Func)
+	gq.Args = c.stringMap(gq.Args)
+	gq.Order = c.orders(gq.Order)
+	gq.Filter = c.filterTree(gq.Filter)
+	gq.MathExp = c.mathTree(gq.MathExp)
+	gq.CascadeFields = c.strings(gq.CascadeFields)
+	gq.Facets = c.facetParams(gq.Facets)
+	gq.FacetsFilter = c.filterTree(gq.FacetsFilter)
+	gq.GroupbyAttrs = c.groupByAttrs(gq.GroupbyAttrs)
+	gq.FacetVar = c.stringMap(gq.FacetVar)
+	gq.Extensions = c.extensions(gq.Extensions)
+
+	if gq.Children == nil || (c.normalize && len(gq.Children) == 0) {
+		gq.Children = nil
+		return gq
+	}
+
+	children := make([]GraphQuery, 0, len(gq.Children))
+	for _, e := range gq.Children {
+		children = append(children, c.graphQuery(e))
+	}
+	gq.Children = children
+
+	return gq
+}
+
+func (c cloner) empty(n int, isNil bool) bool {
+	return isNil || (c.normalize && n == 0)
+}
+
+func (c cloner) uids(s []uint64) []uint64 {
+	if c.empty(len(s), s == nil) {
+		return nil
+	}
+	return append([]uint64{}, s...)
+}
+
+func (c cloner) strings(s []string) []string {
+	if c.empty(len(s), s == nil) {
+		return nil
+	}
+	return append([]string{}, s...)
+}
+
+func (c cloner) stringMap(m map[string]string) map[string]string {
+	if c.empty(len(m), m == nil) {
+		return nil
+	}
+
+	res := make(map[string]string, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
+
+func (c cloner) extensions(m map[string]interface{}) map[string]interface{} {
+	if c.empty(len(m), m == nil) {
+		return nil
+	}
+
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		res[k] = c.value(v)
+	}
+	return res
+}
+
+func (c cloner) varContexts(s []VarContext) []VarContext {
+	if c.empty(len(s), s == nil) {
+		return nil
+	}
+	return append([]VarContext{}, s...)
+}
+
+func (c cloner) orders(s []Order) []Order {
+	if c.empty(len(s), s == nil) {
+		return nil
+	}
+
+	res := make([]Order, 0, len(s))
+	for _, e := range s {
+		e.Langs = c.strings(e.Langs)
+		res = append(res, e)
+	}
+	return res
+}
+
+func (c cloner) groupByAttrs(s []GroupByAttr) []GroupByAttr {
+	if c.empty(len(s), s == nil) {
+		return nil
+	}
+
+	res := make([]GroupByAttr, 0, len(s))
+	for _, e := range s {
+		e.Langs = c.strings(e.Langs)
+		res = append(res, e)
+	}
+	return res
+}
+
+func (c cloner) function(fn *Function) *Function {
+	if fn == nil {
+		return nil
+	}
+
+	res := *fn
+	res.UID = c.uids(fn.UID)
+	res.NeedsVar = c.varContexts(fn.NeedsVar)
+	res.Args = c.args(fn.Args)
+	return &res
+}
+
+func (c cloner) args(s []Arg) []Arg {
+	if c.empty(len(s), s == nil) {
+		return nil
+	}
+	return append([]Arg{}, s...)
+}
+
+func (c cloner) filterTree(ft *FilterTree) *FilterTree {
+	if ft == nil {
+		return nil
+	}
+
+	res := c.filterTreeValue(*ft)
+	return &res
+}
+
+func (c cloner) filterTreeValue(ft FilterTree) FilterTree {
+	ft.Func = c.function(ft.Func)
+	if c.empty(len(ft.Child), ft.Child == nil) {
+		ft.Child = nil
+		return ft
+	}
+
+	children := make([]FilterTree, 0, len(ft.Child))
+	for _, e := range ft.Child {
+		children = append(children, c.filterTreeValue(e))
+	}
+	ft.Child = children
+
+	return ft
+}
+
+func (c cloner) mathTree(mt *MathTree) *MathTree {
+	if mt == nil {
+		return nil
+	}
+
+	res := c.mathTreeValue(*mt)
+	return &res
+}
+
+func (c cloner) mathTreeValue(mt MathTree) MathTree {
+	mt.Const.Value = c.value(mt.Const.Value)
+	if c.empty(len(mt.Val), mt.Val == nil) {
+		mt.Val = nil
+	} else {
+		vals := make(map[uint64]Val, len(mt.Val))
+		for k, v := range mt.Val {
+			v.Value = c.value(v.Value)
+			vals[k] = v
+		}
+		mt.Val = vals
+	}
+
+	if c.empty(len(mt.Child), mt.Child == nil) {
+		mt.Child = nil
+		return mt
+	}
+
+	children := make([]MathTree, 0, len(mt.Child))
+	for _, e := range mt.Child {
+		children = append(children, c.mathTreeValue(e))
+	}
+	mt.Child = children
+
+	return mt
+}
+
+func (c cloner) facetParams(fp *FacetParams) *FacetParams {
+	if fp == nil {
+		return nil
+	}
+
+	res := *fp
+	if c.empty(len(fp.Param), fp.Param == nil) {
+		res.Param = nil
+	} else {
+		res.Param = append([]FacetParam{}, fp.Param...)
+	}
+	return &res
+}
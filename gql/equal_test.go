@@ -0,0 +1,102 @@
+package gql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func equalQuery() GraphQuery {
+	return GraphQuery{
+		Alias: "me",
+		UID:   []uint64{1},
+		Func:  &Function{Name: "uid"},
+		Args:  map[string]string{"first": "10", "offset": "5"},
+		Children: []GraphQuery{
+			{Attr: "name", Langs: []string{"en"}},
+			{
+				Attr:     "friend",
+				Filter:   &FilterTree{Op: "not", Child: []FilterTree{{Func: &Function{Name: "eq", Attr: "name", Args: []Arg{{Value: "Pat"}}}}}},
+				Facets:   &FacetParams{Param: []FacetParam{{Key: "since"}}},
+				Children: []GraphQuery{{Attr: "name"}},
+			},
+			{Attr: "math", MathExp: &MathTree{Fn: "+", Child: []MathTree{{Var: "a"}, {Const: Val{Tid: floatID, Value: 1.0}}}}},
+		},
+	}
+}
+
+func TestClone(t *testing.T) {
+	gq := equalQuery()
+	clone := gq.Clone()
+	require.Equal(t, gq, clone)
+
+	clone.UID[0] = 2
+	clone.Args["first"] = "1"
+	clone.Children[0].Langs[0] = "de"
+	clone.Children[1].Filter.Child[0].Func.Args[0].Value = "Sam"
+	clone.Children[1].Facets.Param[0].Key = "until"
+	clone.Children[2].MathExp.Child[0].Var = "b"
+
+	require.Equal(t, equalQuery(), gq)
+}
+
+func TestEqual(t *testing.T) {
+	a := equalQuery()
+
+	b := equalQuery()
+	b.Langs = []string{}
+	b.Children[0].NeedsVar = []VarContext{}
+	b.Children[1].FacetVar = map[string]string{}
+	b.Children[1].Children[0].Children = []GraphQuery{}
+	require.True(t, Equal(a, b))
+
+	c := equalQuery()
+	c.Children[1].Filter.Child[0].Func.Args[0].Value = "Sam"
+	require.False(t, Equal(a, c))
+
+	require.True(t, EqualQueries([]GraphQuery{a}, []GraphQuery{b}))
+	require.False(t, EqualQueries([]GraphQuery{a}, []GraphQuery{a, b}))
+}
+
+func TestHash(t *testing.T) {
+	a := equalQuery()
+	hash, err := Hash(a)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		b := equalQuery()
+		b.Args = map[string]string{"offset": "5", "first": "10"}
+		b.Children[0].NeedsVar = []VarContext{}
+
+		h, err := Hash(b)
+		require.NoError(t, err)
+		require.Equal(t, hash, h)
+	}
+
+	c := equalQuery()
+	c.Children[1].Filter.Child[0].Func.Args[0].Value = "Sam"
+	h, err := Hash(c)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, h)
+
+	h, err = Hash(a, a)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, h)
+
+	typed := func(v interface{}) string {
+		q := equalQuery()
+		q.Default = map[string]interface{}{"age": v}
+		h, err := Hash(q)
+		require.NoError(t, err)
+		return h
+	}
+	require.Equal(t, typed(int64(1)), typed(int64(1)))
+	require.NotEqual(t, typed(int64(1)), typed(uint64(1)))
+	require.NotEqual(t, typed(1), typed(1.0))
+	require.NotEqual(t, typed([]interface{}{1}), typed([]interface{}{1.0}))
+
+	d := equalQuery()
+	d.Default = func() {}
+	_, err = Hash(d)
+	require.Error(t, err)
+}
@@ -0,0 +1,34 @@
+package gql
+
+import "reflect"
+
+// extensionFields are the indexes of the GraphQuery fields tagged as
+// extension.
+var extensionFields = func() []int {
+	var res []int
+	t := reflect.TypeOf(GraphQuery{})
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("extension") == "true" {
+			res = append(res, i)
+		}
+	}
+	return res
+}()
+
+// StripExtensions returns a copy of queries without the fields tagged as
+// extension, i.e. the queries the way Dgraph sees them.
+func StripExtensions(queries []GraphQuery) []GraphQuery {
+	res, _ := Rewrite(queries, func(c *Cursor) error {
+		gq, ok := c.Node().(*GraphQuery)
+		if !ok {
+			return SkipChildren
+		}
+
+		v := reflect.ValueOf(gq).Elem()
+		for _, i := range extensionFields {
+			v.Field(i).Set(reflect.Zero(v.Field(i).Type()))
+		}
+		return nil
+	})
+	return res
+}
@@ -0,0 +1,78 @@
+package extension
+
+import (
+	"fmt"
+
+	"mooncamp.com/dgraphtools/gql"
+)
+
+// CardinalityError reports a response that doesn't match the declared
+// cardinality. Path points into the response, e.g. `persons[1].spouse`.
+type CardinalityError struct {
+	Path        string
+	Cardinality gql.Cardinality
+	Count       int
+}
+
+func (e CardinalityError) Error() string {
+	return fmt.Sprintf("%s: expected %s node, got %d", e.Path, e.Cardinality, e.Count)
+}
+
+// Cardinality replaces the single element lists of blocks declared as
+// one or optional one by the element itself. It fails with a
+// CardinalityError if the response has more or less nodes than
+// declared, unless Warn is set. Warn is called instead and the
+// offending values are left as they are.
+type Cardinality struct {
+	Warn func(err CardinalityError)
+}
+
+func (Cardinality) Applies(gq gql.GraphQuery) bool {
+	return gq.Cardinality == gql.CardinalityOne || gq.Cardinality == gql.CardinalityOptionalOne
+}
+
+func (c Cardinality) Apply(n *Node) error {
+	list, isList := n.Value.([]interface{})
+	if n.Present && !isList {
+		// Scalar values and defaults are single values already.
+		return nil
+	}
+
+	switch {
+	case len(list) == 1:
+		n.Value = list[0]
+		n.Present = true
+		return nil
+	case len(list) == 0 && n.Query.Cardinality == gql.CardinalityOptionalOne:
+		n.Value = nil
+		n.Present = false
+		return nil
+	}
+
+	err := CardinalityError{Path: n.Path, Cardinality: n.Query.Cardinality, Count: len(list)}
+	if c.Warn == nil {
+		return err
+	}
+
+	c.Warn(err)
+	return nil
+}
+
+type CardinalityOption func(c *Cardinality)
+
+// WarnCardinality reports violations to warn instead of failing.
+func WarnCardinality(warn func(err CardinalityError)) CardinalityOption {
+	return func(c *Cardinality) {
+		c.Warn = warn
+	}
+}
+
+// ApplyCardinality applies the Cardinality extension to resp.
+func ApplyCardinality(gqs []gql.GraphQuery, resp map[string]interface{}, opts ...CardinalityOption) (map[string]interface{}, error) {
+	c := Cardinality{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return Chain{c}.Apply(gqs, resp)
+}
@@ -0,0 +1,131 @@
+package extension
+
+import (
+	"testing"
+
+	"mooncamp.com/dgraphtools/gql"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyCardinality(t *testing.T) {
+	person := func(cardinality gql.Cardinality) []gql.GraphQuery {
+		return []gql.GraphQuery{
+			{
+				Alias: "persons",
+				Func:  &gql.Function{Name: "has", Attr: "name"},
+				Children: []gql.GraphQuery{
+					{Attr: "name"},
+					{Attr: "spouse", Cardinality: cardinality, Children: []gql.GraphQuery{{Attr: "name"}}},
+				},
+			},
+		}
+	}
+
+	node := func(name string, spouses ...string) map[string]interface{} {
+		res := map[string]interface{}{"name": name}
+		if spouses == nil {
+			return res
+		}
+
+		list := []interface{}{}
+		for _, e := range spouses {
+			list = append(list, map[string]interface{}{"name": e})
+		}
+		res["spouse"] = list
+		return res
+	}
+
+	table := []struct {
+		name     string
+		queries  []gql.GraphQuery
+		resp     map[string]interface{}
+		expected map[string]interface{}
+		err      error
+	}{
+		{
+			name:     "one",
+			queries:  person(gql.CardinalityOne),
+			resp:     map[string]interface{}{"persons": []interface{}{node("harry", "anna")}},
+			expected: map[string]interface{}{"persons": []interface{}{map[string]interface{}{"name": "harry", "spouse": map[string]interface{}{"name": "anna"}}}},
+		},
+		{
+			name:    "one missing",
+			queries: person(gql.CardinalityOne),
+			resp:    map[string]interface{}{"persons": []interface{}{node("harry", "anna"), node("peter")}},
+			err:     Error{Path: "queries[0].children[1]", Err: CardinalityError{Path: "persons[1].spouse", Cardinality: gql.CardinalityOne, Count: 0}},
+		},
+		{
+			name:    "one too many",
+			queries: person(gql.CardinalityOne),
+			resp:    map[string]interface{}{"persons": []interface{}{node("harry", "anna", "sue")}},
+			err:     Error{Path: "queries[0].children[1]", Err: CardinalityError{Path: "persons[0].spouse", Cardinality: gql.CardinalityOne, Count: 2}},
+		},
+		{
+			name:    "optional one",
+			queries: person(gql.CardinalityOptionalOne),
+			resp:    map[string]interface{}{"persons": []interface{}{node("harry", "anna"), node("peter"), node("sue", []string{}...)}},
+			expected: map[string]interface{}{"persons": []interface{}{
+				map[string]interface{}{"name": "harry", "spouse": map[string]interface{}{"name": "anna"}},
+				map[string]interface{}{"name": "peter"},
+				map[string]interface{}{"name": "sue"},
+			}},
+		},
+		{
+			name:     "many",
+			queries:  person(gql.CardinalityMany),
+			resp:     map[string]interface{}{"persons": []interface{}{node("harry", "anna", "sue")}},
+			expected: map[string]interface{}{"persons": []interface{}{node("harry", "anna", "sue")}},
+		},
+		{
+			name: "root",
+			queries: []gql.GraphQuery{
+				{Alias: "me", UID: []uint64{1}, Func: &gql.Function{Name: "uid"}, Cardinality: gql.CardinalityOne, Children: []gql.GraphQuery{{Attr: "name"}}},
+				{Alias: "others", Func: &gql.Function{Name: "has", Attr: "name"}, Children: []gql.GraphQuery{{Attr: "name"}}},
+			},
+			resp: map[string]interface{}{
+				"me":     []interface{}{node("harry")},
+				"others": []interface{}{node("anna"), node("sue")},
+			},
+			expected: map[string]interface{}{
+				"me":     node("harry"),
+				"others": []interface{}{node("anna"), node("sue")},
+			},
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			actual, err := ApplyCardinality(e.queries, e.resp)
+			if e.err != nil {
+				require.Equal(t, e.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, e.expected, actual)
+		})
+	}
+}
+
+func TestWarnCardinality(t *testing.T) {
+	queries := []gql.GraphQuery{
+		{
+			Alias:    "me",
+			UID:      []uint64{1},
+			Func:     &gql.Function{Name: "uid"},
+			Children: []gql.GraphQuery{{Attr: "spouse", Cardinality: gql.CardinalityOne, Children: []gql.GraphQuery{{Attr: "name"}}}},
+		},
+	}
+
+	spouses := []interface{}{map[string]interface{}{"name": "anna"}, map[string]interface{}{"name": "sue"}}
+	resp := map[string]interface{}{"me": []interface{}{map[string]interface{}{"spouse": spouses}}}
+
+	var warnings []CardinalityError
+	actual, err := ApplyCardinality(queries, resp, WarnCardinality(func(err CardinalityError) {
+		warnings = append(warnings, err)
+	}))
+	require.NoError(t, err)
+	require.Equal(t, resp, actual)
+	require.Equal(t, []CardinalityError{{Path: "me[0].spouse", Cardinality: gql.CardinalityOne, Count: 2}}, warnings)
+}
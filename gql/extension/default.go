@@ -1,56 +1,170 @@
 package extension
 
-import "mooncamp.com/dgraphtools/gql"
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
-func ApplyDefaults(gqs []gql.GraphQuery, resp map[string]interface{}) interface{} {
-	res := make(map[string]interface{})
-	for _, e := range gqs {
-		res[e.Alias] = setDefault(e, resp[e.Alias])
+	"mooncamp.com/dgraphtools/gql"
+)
+
+// Defaults sets GraphQuery.Default for blocks missing in the response,
+// or without any nodes, which is how Dgraph answers root blocks without
+// results. Typed defaults are converted to their JSON form. Objects and
+// lists of objects in a default are filled with the defaults of the
+// child blocks, so `map[string]interface{}{}` builds the default from
+// the children alone.
+type Defaults struct{}
+
+func (Defaults) Applies(gq gql.GraphQuery) bool {
+	return gq.Default != nil
+}
+
+func (Defaults) Apply(n *Node) error {
+	if list, ok := n.Value.([]interface{}); n.Present && (!ok || len(list) > 0) {
+		return nil
 	}
 
-	return res
+	v, err := jsonValue(n.Query.Default)
+	if err != nil {
+		return fmt.Errorf("%s: default: %v", n.Path, err)
+	}
 
+	n.Value = v
+	n.Present = true
+	return nil
 }
 
-func getNodeName(gq gql.GraphQuery) string {
-	if gq.Alias != "" {
-		return gq.Alias
+// jsonValue converts v to the types encoding/json decodes to.
+func jsonValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var res interface{}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
 	}
+	return res, nil
+}
 
-	return gq.Attr
+// ApplyDefaults runs only the Defaults extension over resp.
+func ApplyDefaults(gqs []gql.GraphQuery, resp map[string]interface{}) (map[string]interface{}, error) {
+	return Chain{Defaults{}}.Apply(gqs, resp)
 }
 
-func setDefault(gq gql.GraphQuery, node interface{}) interface{} {
-	// 1. if at []interface, apply gq to all entities
-	// 2. if at map[string]interface{}, check for all nodes if defaults are set if empty
+// Schema maps predicates to their type in Dgraph's schema, e.g.
+// "string", "[int]" or "uid".
+type Schema map[string]string
 
-	switch t := node.(type) {
-	case []interface{}:
-		res := make([]interface{}, 0, len(t))
-		for _, e := range t {
-			res = append(res, setDefault(gq, e))
+// ValidateDefaults checks that the defaults of queries match the types
+// of their predicates. Predicates missing in schema aren't checked.
+func ValidateDefaults(queries []gql.GraphQuery, schema Schema) gql.ValidationErrors {
+	var errs gql.ValidationErrors
+	_ = gql.Walk(queries, func(c *gql.Cursor) error {
+		gq, ok := c.Node().(*gql.GraphQuery)
+		if !ok {
+			return gql.SkipChildren
 		}
 
-		return res
+		if gq.Default == nil {
+			return nil
+		}
 
-	case map[string]interface{}:
-		res := make(map[string]interface{})
+		typ := defaultType(*gq, schema)
+		if typ == "" {
+			return nil
+		}
 
-		for _, e := range gq.Children {
-			_, ok := t[getNodeName(e)]
-			if ok {
-				res[getNodeName(e)] = setDefault(e, t[getNodeName(e)])
-				continue
-			}
+		if !matchesType(typ, gq.Cardinality, reflect.ValueOf(gq.Default)) {
+			errs = append(errs, gql.ValidationError{
+				Path: c.Path(),
+				Msg:  fmt.Sprintf("default of type %T doesn't match %s", gq.Default, typ),
+			})
+		}
+		return nil
+	})
 
-			if e.Default != nil {
-				res[getNodeName(e)] = e.Default
+	return errs
+}
+
+func defaultType(gq gql.GraphQuery, schema Schema) string {
+	switch {
+	case gq.IsCount:
+		return "int"
+	case gq.Attr == "val" && gq.Func != nil:
+		return "float"
+	case gq.Attr == "val":
+		return ""
+	case gq.Func != nil && gq.Func.Name == "checkpwd":
+		return "bool"
+	}
 
+	return schema[gq.Attr]
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func matchesType(typ string, cardinality gql.Cardinality, v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+
+	list := strings.HasPrefix(typ, "[") && strings.HasSuffix(typ, "]")
+	typ = strings.Trim(typ, "[]")
+	if typ == "uid" {
+		// Dgraph returns lists for every edge, unless a cardinality of
+		// one is declared.
+		list = cardinality != gql.CardinalityOne && cardinality != gql.CardinalityOptionalOne
+	}
+
+	if list {
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return false
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if !matchesType(typ, gql.CardinalityOne, v.Index(i)) {
+				return false
 			}
 		}
+		return true
+	}
 
-		return res
-	default:
-		return node
+	switch typ {
+	case "uid", "geo":
+		return v.Kind() == reflect.Map || v.Kind() == reflect.Struct
+	case "int":
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return true
+		case reflect.Float32, reflect.Float64:
+			// Numbers decoded from JSON are floats.
+			return v.Float() == float64(int64(v.Float()))
+		}
+		return false
+	case "float":
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return true
+		}
+		return false
+	case "bool":
+		return v.Kind() == reflect.Bool
+	case "datetime":
+		return v.Kind() == reflect.String || v.Type() == timeType
+	case "string", "password":
+		return v.Kind() == reflect.String
 	}
+
+	return true
 }
@@ -87,10 +87,253 @@ func Test_defaults_are_set(t *testing.T) {
 	queryResp := make(map[string]interface{})
 	_ = json.Unmarshal(resp.GetJson(), &queryResp)
 
-	defaulted := ApplyDefaults([]gql.GraphQuery{graphQuery}, queryResp)
-	for _, e := range defaulted.(map[string]interface{})[graphQuery.Alias].([]interface{}) {
+	defaulted, err := ApplyDefaults([]gql.GraphQuery{graphQuery}, queryResp)
+	require.NoError(t, err)
+	for _, e := range defaulted[graphQuery.Alias].([]interface{}) {
 		m := e.(map[string]interface{})
 		_, ok := m["friends"]
 		require.True(t, ok, fmt.Sprintf("%s's friends undefined", m["name"]))
 	}
 }
+
+func Test_defaults_only_touch_blocks_with_defaults(t *testing.T) {
+	queries := []gql.GraphQuery{
+		{
+			Alias: "persons",
+			Func:  &gql.Function{Name: "uid"},
+			Children: []gql.GraphQuery{
+				{Attr: "name"},
+				{Attr: "friends", Default: []interface{}{}, Children: []gql.GraphQuery{{Attr: "name"}}},
+			},
+		},
+		{
+			Alias:    "companies",
+			Func:     &gql.Function{Name: "has", Attr: "company.name"},
+			Children: []gql.GraphQuery{{Attr: "company.name"}},
+		},
+	}
+
+	resp := map[string]interface{}{
+		"persons": []interface{}{
+			map[string]interface{}{"name": "harry", "friends": []interface{}{map[string]interface{}{"name": "peter"}}},
+			map[string]interface{}{"name": "anna"},
+		},
+		"companies": []interface{}{
+			map[string]interface{}{"company.name": "mooncamp", "uid": "0x1"},
+		},
+	}
+
+	expected := map[string]interface{}{
+		"persons": []interface{}{
+			map[string]interface{}{"name": "harry", "friends": []interface{}{map[string]interface{}{"name": "peter"}}},
+			map[string]interface{}{"name": "anna", "friends": []interface{}{}},
+		},
+		"companies": []interface{}{
+			map[string]interface{}{"company.name": "mooncamp", "uid": "0x1"},
+		},
+	}
+
+	res, err := ApplyDefaults(queries, resp)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+}
+
+func Test_nested_and_typed_defaults(t *testing.T) {
+	queries := []gql.GraphQuery{
+		{
+			Alias: "persons",
+			Func:  &gql.Function{Name: "has", Attr: "name"},
+			Children: []gql.GraphQuery{
+				{Attr: "name", Langs: []string{"en"}, Default: "unknown"},
+				{Attr: "friends", IsCount: true, Default: 0},
+				{
+					Attr:    "spouse",
+					Default: map[string]interface{}{},
+					Children: []gql.GraphQuery{
+						{Attr: "name", Default: "nobody"},
+						{Attr: "friends", Default: []person{}, Children: []gql.GraphQuery{{Attr: "name"}}},
+					},
+				},
+			},
+		},
+		{
+			Alias:    "admins",
+			Func:     &gql.Function{Name: "has", Attr: "admin"},
+			Default:  []person{{Name: "root"}},
+			Children: []gql.GraphQuery{{Attr: "name"}},
+		},
+	}
+
+	resp := map[string]interface{}{
+		"persons": []interface{}{
+			map[string]interface{}{"name@en": "harry", "count(friends)": 2.0, "spouse": []interface{}{map[string]interface{}{"name": "anna"}}},
+			map[string]interface{}{},
+		},
+		"admins": []interface{}{},
+	}
+
+	expected := map[string]interface{}{
+		"persons": []interface{}{
+			map[string]interface{}{
+				"name@en":        "harry",
+				"count(friends)": 2.0,
+				"spouse":         []interface{}{map[string]interface{}{"name": "anna", "friends": []interface{}{}}},
+			},
+			map[string]interface{}{
+				"name@en":        "unknown",
+				"count(friends)": 0.0,
+				"spouse":         map[string]interface{}{"name": "nobody", "friends": []interface{}{}},
+			},
+		},
+		"admins": []interface{}{map[string]interface{}{"name": "root"}},
+	}
+
+	res, err := ApplyDefaults(queries, resp)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+}
+
+func Test_defaults_failing_to_convert(t *testing.T) {
+	queries := []gql.GraphQuery{
+		{Alias: "me", Children: []gql.GraphQuery{{Attr: "name", Default: func() {}}}},
+	}
+	resp := map[string]interface{}{"me": []interface{}{map[string]interface{}{}}}
+
+	res, err := ApplyDefaults(queries, resp)
+	require.Nil(t, res)
+	require.IsType(t, Error{}, err)
+	require.Equal(t, "queries[0].children[0]", err.(Error).Path)
+}
+
+func TestValidateDefaults(t *testing.T) {
+	schema := Schema{"name": "string", "age": "int", "nicknames": "[string]", "friends": "[uid]", "spouse": "uid"}
+
+	table := []struct {
+		name     string
+		gq       gql.GraphQuery
+		expected gql.ValidationErrors
+	}{
+		{name: "string", gq: gql.GraphQuery{Attr: "name", Default: ""}},
+		{name: "int", gq: gql.GraphQuery{Attr: "age", Default: 0}},
+		{name: "list", gq: gql.GraphQuery{Attr: "nicknames", Default: []string{}}},
+		{name: "edges", gq: gql.GraphQuery{Attr: "friends", Default: []person{}}},
+		{name: "one edge", gq: gql.GraphQuery{Attr: "spouse", Cardinality: gql.CardinalityOne, Default: person{}}},
+		{name: "count", gq: gql.GraphQuery{Attr: "friends", IsCount: true, Default: 0}},
+		{name: "unknown predicate", gq: gql.GraphQuery{Attr: "height", Default: true}},
+		{
+			name:     "wrong scalar",
+			gq:       gql.GraphQuery{Attr: "age", Default: "0"},
+			expected: gql.ValidationErrors{{Path: "queries[0].children[0]", Msg: "default of type string doesn't match int"}},
+		},
+		{
+			name:     "wrong list element",
+			gq:       gql.GraphQuery{Attr: "nicknames", Default: []interface{}{"a", 1}},
+			expected: gql.ValidationErrors{{Path: "queries[0].children[0]", Msg: "default of type []interface {} doesn't match [string]"}},
+		},
+		{
+			name:     "object for edges",
+			gq:       gql.GraphQuery{Attr: "spouse", Default: person{}},
+			expected: gql.ValidationErrors{{Path: "queries[0].children[0]", Msg: "default of type extension.person doesn't match uid"}},
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			queries := []gql.GraphQuery{{Alias: "me", UID: []uint64{1}, Func: &gql.Function{Name: "uid"}, Children: []gql.GraphQuery{e.gq}}}
+			require.Equal(t, e.expected, ValidateDefaults(queries, schema))
+		})
+	}
+}
+
+func Test_defaults_preserve_response_shapes(t *testing.T) {
+	type obj = map[string]interface{}
+	type list = []interface{}
+
+	me := func(gq gql.GraphQuery) []gql.GraphQuery {
+		gq.Alias = "me"
+		gq.Func = &gql.Function{Name: "uid"}
+		gq.UID = []uint64{1}
+		return []gql.GraphQuery{gq}
+	}
+
+	table := []struct {
+		name     string
+		queries  []gql.GraphQuery
+		resp     obj
+		expected obj
+	}{
+		{
+			name: "unknown keys",
+			queries: me(gql.GraphQuery{Children: []gql.GraphQuery{
+				{Attr: "expand", Expand: "_all_", IsInternal: true},
+				{Attr: "friends", Facets: &gql.FacetParams{Param: []gql.FacetParam{{Key: "since"}}}, Children: []gql.GraphQuery{
+					{Attr: "name", Default: "unknown"},
+				}},
+			}}),
+			resp: obj{"me": list{obj{"uid": "0x1", "age": 3.0, "friends": list{
+				obj{"uid": "0x2", "friends|since": "2019"},
+				obj{"name": "anna", "@facets": obj{"_": obj{"since": "2018"}}},
+			}}}},
+			expected: obj{"me": list{obj{"uid": "0x1", "age": 3.0, "friends": list{
+				obj{"uid": "0x2", "friends|since": "2019", "name": "unknown"},
+				obj{"name": "anna", "@facets": obj{"_": obj{"since": "2018"}}},
+			}}}},
+		},
+		{
+			name: "groupby",
+			queries: me(gql.GraphQuery{Children: []gql.GraphQuery{
+				{Attr: "friends", IsGroupby: true, GroupbyAttrs: []gql.GroupByAttr{{Attr: "age"}}, Children: []gql.GraphQuery{
+					{Attr: "uid", IsCount: true, Default: 0},
+					{Attr: "val", Alias: "oldest", Default: 0, Func: &gql.Function{Name: "max"}, NeedsVar: []gql.VarContext{{Name: "a", Typ: 2}}},
+				}},
+			}}),
+			resp: obj{"me": list{obj{"friends": list{obj{"@groupby": list{
+				obj{"age": 20.0, "count": 2.0},
+				obj{"age": 30.0, "oldest": 31.0},
+			}}}}}},
+			expected: obj{"me": list{obj{"friends": list{obj{"@groupby": list{
+				obj{"age": 20.0, "count": 2.0, "oldest": 0.0},
+				obj{"age": 30.0, "count": 0.0, "oldest": 31.0},
+			}}}}}},
+		},
+		{
+			name: "normalize",
+			queries: me(gql.GraphQuery{Normalize: true, Children: []gql.GraphQuery{
+				{Attr: "name", Alias: "n"},
+				{Attr: "location"},
+				{Attr: "friends", Children: []gql.GraphQuery{
+					{Attr: "name", Alias: "friend", Default: "nobody"},
+				}},
+			}}),
+			resp: obj{"me": list{
+				obj{"n": "harry", "friend": "anna"},
+				obj{"n": "peter"},
+			}},
+			expected: obj{"me": list{
+				obj{"n": "harry", "friend": "anna"},
+				obj{"n": "peter", "friend": "nobody"},
+			}},
+		},
+		{
+			name: "recurse",
+			queries: me(gql.GraphQuery{Recurse: true, RecurseArgs: gql.RecurseArgs{Depth: 3}, Children: []gql.GraphQuery{
+				{Attr: "name", Default: "unknown"},
+				{Attr: "friends"},
+			}}),
+			resp: obj{"me": list{obj{"name": "harry", "friends": list{
+				obj{"uid": "0x2", "friends": list{obj{"name": "sue"}}},
+			}}}},
+			expected: obj{"me": list{obj{"name": "harry", "friends": list{
+				obj{"uid": "0x2", "name": "unknown", "friends": list{obj{"name": "sue"}}},
+			}}}},
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			res, err := ApplyDefaults(e.queries, e.resp)
+			require.NoError(t, err)
+			require.Equal(t, e.expected, res)
+		})
+	}
+}
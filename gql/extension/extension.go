@@ -0,0 +1,270 @@
+// Package extension post-processes the responses of Dgraph based on the
+// extension fields of the queries, like GraphQuery.Default.
+package extension
+
+import (
+	"fmt"
+	"strings"
+
+	"mooncamp.com/dgraphtools/gql"
+)
+
+// Node is the response to a block of the query.
+type Node struct {
+	// Query is the block the response belongs to.
+	Query gql.GraphQuery
+	// Name is the key of the response in the enclosing object. Changing
+	// it renames the key.
+	Name string
+	// Path points into the response, e.g. `persons[1].spouse`.
+	Path string
+	// Value is the response, nil if it isn't present.
+	Value interface{}
+	// Present reports whether the response contains the block. Setting
+	// it to false removes the block from the response.
+	Present bool
+}
+
+// Extension transforms the responses of the blocks it applies to.
+type Extension interface {
+	// Applies reports whether the extension transforms the response of
+	// gq. Responses to blocks without a block below the extension
+	// applies to are left as they are.
+	Applies(gq gql.GraphQuery) bool
+	// Apply transforms the response of a block before the responses of
+	// its children are visited.
+	Apply(n *Node) error
+}
+
+// Error is returned by Chain.Apply if an extension fails. Path points
+// to the block whose response it failed on, e.g.
+// `queries[0].children[1]`.
+type Error struct {
+	Path string
+	Err  error
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("extension failed at %s: %v", e.Path, e.Err)
+}
+
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// Chain applies extensions one after another.
+type Chain []Extension
+
+// Standard returns the extensions of this package, defaults followed by
+// cardinality.
+func Standard() Chain {
+	return Chain{Defaults{}, Cardinality{}}
+}
+
+// Apply runs every extension over the response to gqs. The response
+// itself is left untouched. Extensions failing stop the chain with an
+// Error.
+func (c Chain) Apply(gqs []gql.GraphQuery, resp map[string]interface{}) (map[string]interface{}, error) {
+	for _, ext := range c {
+		var err error
+		if resp, err = apply(ext, gqs, resp); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+type applier struct {
+	ext   Extension
+	paths map[string]bool
+}
+
+func apply(ext Extension, gqs []gql.GraphQuery, resp map[string]interface{}) (map[string]interface{}, error) {
+	a := applier{ext: ext, paths: markedPaths(gqs, ext.Applies)}
+
+	res := make(map[string]interface{}, len(resp))
+	for k, v := range resp {
+		res[k] = v
+	}
+
+	for i, e := range gqs {
+		path := fmt.Sprintf("queries[%d]", i)
+		if !a.paths[path] {
+			continue
+		}
+
+		v, ok := resp[e.Alias]
+		n := Node{Query: e, Name: e.Alias, Path: e.Alias, Value: v, Present: ok}
+		if err := a.node(path, &n); err != nil {
+			return nil, err
+		}
+		set(res, e.Alias, n)
+	}
+
+	return res, nil
+}
+
+// markedPaths returns the paths of the blocks that are marked
+// themselves or somewhere below. Responses to the other blocks are left
+// as they are.
+func markedPaths(gqs []gql.GraphQuery, marked func(gq gql.GraphQuery) bool) map[string]bool {
+	paths := map[string]bool{}
+	_ = gql.Walk(gqs, func(c *gql.Cursor) error {
+		gq, ok := c.Node().(*gql.GraphQuery)
+		if !ok {
+			return gql.SkipChildren
+		}
+
+		if !marked(*gq) {
+			return nil
+		}
+
+		for e := c; e != nil && !paths[e.Path()]; e = e.Parent() {
+			paths[e.Path()] = true
+		}
+		return nil
+	})
+
+	return paths
+}
+
+// responseKey returns the key Dgraph uses for the response to gq, e.g.
+// `count(friends)` or `name@en`.
+func responseKey(gq gql.GraphQuery) string {
+	switch {
+	case gq.Alias != "":
+		return gq.Alias
+	case gq.IsCount && gq.Attr == "uid":
+		// count(uid) within @groupby.
+		return "count"
+	case gq.IsCount:
+		return fmt.Sprintf("count(%s)", gq.Attr)
+	case gq.Attr == "val" && len(gq.NeedsVar) > 0:
+		key := fmt.Sprintf("val(%s)", gq.NeedsVar[0].Name)
+		if gq.Func != nil {
+			// Aggregations like min(val(a)).
+			return fmt.Sprintf("%s(%s)", gq.Func.Name, key)
+		}
+		return key
+	case gq.Func != nil && gq.Func.Name == "checkpwd":
+		return fmt.Sprintf("checkpwd(%s)", gq.Attr)
+	case len(gq.Langs) > 0:
+		return fmt.Sprintf("%s@%s", gq.Attr, strings.Join(gq.Langs, ":"))
+	}
+
+	return gq.Attr
+}
+
+// set stores the response of n, which was found under name, in obj.
+func set(obj map[string]interface{}, name string, n Node) {
+	delete(obj, name)
+	if n.Present {
+		obj[n.Name] = n.Value
+	}
+}
+
+func (a applier) node(path string, n *Node) error {
+	if a.ext.Applies(n.Query) {
+		if err := a.ext.Apply(n); err != nil {
+			return Error{Path: path, Err: err}
+		}
+	}
+
+	if !n.Present {
+		return nil
+	}
+
+	v, err := a.children(n.Query, path, n.Path, n.Value)
+	if err != nil {
+		return err
+	}
+
+	n.Value = v
+	return nil
+}
+
+type block struct {
+	gq   gql.GraphQuery
+	path string
+}
+
+// blocks returns the blocks below gq whose responses are part of the
+// response object of gq. Unlike the other blocks, @normalize flattens
+// the response to the aliased predicates of the whole tree and @recurse
+// repeats its children on every level.
+func blocks(gq gql.GraphQuery, path string) []block {
+	var res []block
+	for i, e := range gq.Children {
+		childPath := fmt.Sprintf("%s.children[%d]", path, i)
+		switch {
+		case gq.Normalize && len(e.Children) > 0:
+			e.Normalize = true
+			res = append(res, blocks(e, childPath)...)
+		case gq.Normalize && e.Alias == "":
+		case gq.Recurse:
+			e.Recurse = true
+			e.Children = gq.Children
+			res = append(res, block{gq: e, path: path})
+		default:
+			res = append(res, block{gq: e, path: childPath})
+		}
+	}
+
+	return res
+}
+
+// children applies the extension to the responses of the children of
+// gq. A list holds the responses of several nodes.
+func (a applier) children(gq gql.GraphQuery, path, respPath string, value interface{}) (interface{}, error) {
+	switch t := value.(type) {
+	case []interface{}:
+		res := make([]interface{}, 0, len(t))
+		for i, e := range t {
+			v, err := a.children(gq, path, fmt.Sprintf("%s[%d]", respPath, i), e)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, v)
+		}
+
+		return res, nil
+
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			res[k] = v
+		}
+
+		if groups, ok := t["@groupby"]; ok && gq.IsGroupby {
+			// The children of @groupby are the aggregations of each group.
+			gq.IsGroupby = false
+			v, err := a.children(gq, path, respPath+".@groupby", groups)
+			if err != nil {
+				return nil, err
+			}
+
+			res["@groupby"] = v
+			return res, nil
+		}
+
+		for _, e := range blocks(gq, path) {
+			if !a.paths[e.path] {
+				continue
+			}
+
+			name := responseKey(e.gq)
+			v, ok := t[name]
+			n := Node{Query: e.gq, Name: name, Path: respPath + "." + name, Value: v, Present: ok}
+			if err := a.node(e.path, &n); err != nil {
+				return nil, err
+			}
+			set(res, name, n)
+		}
+
+		return res, nil
+
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,84 @@
+package extension
+
+import (
+	"testing"
+
+	"mooncamp.com/dgraphtools/gql"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rename moves the response of a block to the key set in its
+// extensions.
+type rename struct{}
+
+func (rename) Applies(gq gql.GraphQuery) bool {
+	_, ok := gq.Extensions["rename"]
+	return ok
+}
+
+func (rename) Apply(n *Node) error {
+	n.Name = n.Query.Extensions["rename"].(string)
+	return nil
+}
+
+// redact removes the response of a block.
+type redact struct{}
+
+func (redact) Applies(gq gql.GraphQuery) bool {
+	return gq.Extensions["redact"] == true
+}
+
+func (redact) Apply(n *Node) error {
+	n.Present = false
+	return nil
+}
+
+func TestChain(t *testing.T) {
+	queries := []gql.GraphQuery{
+		{
+			Alias: "me",
+			UID:   []uint64{1},
+			Func:  &gql.Function{Name: "uid"},
+			Children: []gql.GraphQuery{
+				{Attr: "name", Extensions: map[string]interface{}{"rename": "fullName"}},
+				{Attr: "password", Extensions: map[string]interface{}{"redact": true}},
+				{
+					Attr:        "spouse",
+					Cardinality: gql.CardinalityOne,
+					Default:     map[string]interface{}{"name": "nobody"},
+					Children:    []gql.GraphQuery{{Attr: "name"}},
+				},
+			},
+		},
+	}
+
+	resp := map[string]interface{}{
+		"me": []interface{}{
+			map[string]interface{}{"name": "harry", "password": "secret", "uid": "0x1"},
+		},
+	}
+
+	expected := map[string]interface{}{
+		"me": []interface{}{
+			map[string]interface{}{"fullName": "harry", "spouse": map[string]interface{}{"name": "nobody"}, "uid": "0x1"},
+		},
+	}
+
+	actual, err := append(Standard(), rename{}, redact{}).Apply(queries, resp)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	require.Equal(t, "secret", resp["me"].([]interface{})[0].(map[string]interface{})["password"])
+}
+
+func TestChainStopsAtError(t *testing.T) {
+	queries := []gql.GraphQuery{
+		{Alias: "me", Func: &gql.Function{Name: "has", Attr: "name"}, Cardinality: gql.CardinalityOne, Children: []gql.GraphQuery{{Attr: "name"}}},
+	}
+
+	resp := map[string]interface{}{"me": []interface{}{}}
+
+	_, err := Standard().Apply(queries, resp)
+	require.Equal(t, Error{Path: "queries[0]", Err: CardinalityError{Path: "me", Cardinality: gql.CardinalityOne}}, err)
+}
@@ -0,0 +1,34 @@
+package gql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripExtensions(t *testing.T) {
+	queries := []GraphQuery{
+		{
+			Alias:       "me",
+			UID:         []uint64{1},
+			Func:        &Function{Name: "uid"},
+			Cardinality: CardinalityOne,
+			Children: []GraphQuery{
+				{Attr: "name", Default: "unknown", Extensions: map[string]interface{}{"rename": "fullName"}},
+			},
+		},
+	}
+
+	expected := []GraphQuery{
+		{
+			Alias:    "me",
+			UID:      []uint64{1},
+			Func:     &Function{Name: "uid"},
+			Children: []GraphQuery{{Attr: "name"}},
+		},
+	}
+
+	require.Equal(t, expected, StripExtensions(queries))
+	require.Equal(t, CardinalityOne, queries[0].Cardinality)
+	require.Equal(t, "unknown", queries[0].Children[0].Default)
+}
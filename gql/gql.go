@@ -7,7 +7,7 @@ type GraphQuery struct {
 	Attr       string       `yaml:"attr,omitempty" json:"attr,omitempty"`
 	Langs      []string     `yaml:"langs,omitempty" json:"langs,omitempty"`
 	Alias      string       `yaml:"alias,omitempty" json:"alias,omitempty"`
-	Default    interface{}  `yaml:"default,omitempty" json:"default,omitempty"`
+	Default    interface{}  `yaml:"default,omitempty" json:"default,omitempty" extension:"true"`
 	IsCount    bool         `yaml:"isCount,omitempty" json:"isCount,omitempty"`
 	IsInternal bool         `yaml:"isInternal,omitempty" json:"isInternal,omitempty"`
 	IsGroupby  bool         `yaml:"isGroupby,omitempty" json:"isGroupby,omitempty"`
@@ -18,20 +18,52 @@ type GraphQuery struct {
 
 	Args map[string]string `yaml:"args,omitempty" json:"args,omitempty"`
 	// Query can have multiple sort parameters.
-	Order        []Order           `yaml:"order,omitempty" json:"order,omitempty"`
-	Children     []GraphQuery      `yaml:"children,omitempty" json:"children,omitempty"`
-	Filter       *FilterTree       `yaml:"filter,omitempty" json:"filter,omitempty"`
-	MathExp      *MathTree         `yaml:"mathExp,omitempty" json:"mathExp,omitempty"`
-	Normalize    bool              `yaml:"normalize,omitempty" json:"normalize,omitempty"`
-	Recurse      bool              `yaml:"recurse,omitempty" json:"recurse,omitempty"`
-	Cascade      bool              `yaml:"cascade,omitempty" json:"cascade,omitempty"`
-	IgnoreReflex bool              `yaml:"ignoreReflex,omitempty" json:"ignoreReflex,omitempty"`
-	Facets       *FacetParams      `yaml:"facets,omitempty" json:"facets,omitempty"`
-	FacetsFilter *FilterTree       `yaml:"facetsFilter,omitempty" json:"facetsFilter,omitempty"`
-	GroupbyAttrs []GroupByAttr     `yaml:"groupbyAttrs,omitempty" json:"groupbyAttrs,omitempty"`
-	FacetVar     map[string]string `yaml:"facetVar,omitempty" json:"facetVar,omitempty"`
-	FacetOrder   string            `yaml:"facetOrder,omitempty" json:"facetOrder,omitempty"`
-	FacetDesc    bool              `yaml:"facetDesc,omitempty" json:"facetDesc,omitempty"`
+	Order       []Order      `yaml:"order,omitempty" json:"order,omitempty"`
+	Children    []GraphQuery `yaml:"children,omitempty" json:"children,omitempty"`
+	Filter      *FilterTree  `yaml:"filter,omitempty" json:"filter,omitempty"`
+	MathExp     *MathTree    `yaml:"mathExp,omitempty" json:"mathExp,omitempty"`
+	Normalize   bool         `yaml:"normalize,omitempty" json:"normalize,omitempty"`
+	Recurse     bool         `yaml:"recurse,omitempty" json:"recurse,omitempty"`
+	RecurseArgs RecurseArgs  `yaml:"recurseArgs,omitempty" json:"recurseArgs,omitempty"`
+	Cascade     bool         `yaml:"cascade,omitempty" json:"cascade,omitempty"`
+	// Only the given predicates have to be present, e.g. @cascade(name).
+	CascadeFields []string          `yaml:"cascadeFields,omitempty" json:"cascadeFields,omitempty"`
+	IgnoreReflex  bool              `yaml:"ignoreReflex,omitempty" json:"ignoreReflex,omitempty"`
+	Facets        *FacetParams      `yaml:"facets,omitempty" json:"facets,omitempty"`
+	FacetsFilter  *FilterTree       `yaml:"facetsFilter,omitempty" json:"facetsFilter,omitempty"`
+	GroupbyAttrs  []GroupByAttr     `yaml:"groupbyAttrs,omitempty" json:"groupbyAttrs,omitempty"`
+	FacetVar      map[string]string `yaml:"facetVar,omitempty" json:"facetVar,omitempty"`
+	FacetOrder    string            `yaml:"facetOrder,omitempty" json:"facetOrder,omitempty"`
+	FacetDesc     bool              `yaml:"facetDesc,omitempty" json:"facetDesc,omitempty"`
+	// The number of child nodes is requested by count(uid).
+	UidCount      bool   `yaml:"uidCount,omitempty" json:"uidCount,omitempty"`
+	UidCountAlias string `yaml:"uidCountAlias,omitempty" json:"uidCountAlias,omitempty"`
+
+	// Fields tagged as extension are only read by the extensions applied
+	// to the response, they aren't part of the rendered query. Extensions
+	// holds the settings of custom extensions by their name.
+	Cardinality Cardinality            `yaml:"cardinality,omitempty" json:"cardinality,omitempty" extension:"true"`
+	Extensions  map[string]interface{} `yaml:"extensions,omitempty" json:"extensions,omitempty" extension:"true"`
+}
+
+// Cardinality declares how many nodes a block expects in the response.
+type Cardinality string
+
+const (
+	// CardinalityMany leaves the list of nodes returned by Dgraph as it
+	// is. It is the same as not declaring a cardinality.
+	CardinalityMany Cardinality = "many"
+	// CardinalityOne expects exactly one node, which is returned
+	// without the enclosing list.
+	CardinalityOne Cardinality = "one"
+	// CardinalityOptionalOne is like CardinalityOne, but the node may be
+	// missing.
+	CardinalityOptionalOne Cardinality = "optionalOne"
+)
+
+type RecurseArgs struct {
+	Depth     uint64 `yaml:"depth,omitempty" json:"depth,omitempty"`
+	AllowLoop bool   `yaml:"allowLoop,omitempty" json:"allowLoop,omitempty"`
 }
 
 type FacetParams struct {
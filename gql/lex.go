@@ -0,0 +1,457 @@
+// Copyright 2015-2018 Dgraph Labs, Inc. and Contributors
+// Modifications copyright 2019 mooncamp.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Adapted from the query parser of Dgraph (github.com/dgraph-io/dgraph/gql).
+
+package gql
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+const eof = -1
+
+type itemType int
+
+const (
+	itemEOF itemType = iota
+	itemError
+	itemText
+	itemLeftCurl
+	itemRightCurl
+	itemEqual
+	itemName
+	itemOpType
+	itemLeftRound
+	itemRightRound
+	itemColon
+	itemAt
+	itemPeriod
+	itemDollar
+	itemRegex
+	itemLeftSquare
+	itemRightSquare
+	itemComma
+	itemMathOp
+)
+
+type item struct {
+	typ itemType
+	val string
+	pos int
+}
+
+func (i item) String() string {
+	if i.typ == itemEOF {
+		return "EOF"
+	}
+	return fmt.Sprintf("%q", i.val)
+}
+
+type stateFn func(*lexer) stateFn
+
+// lexer splits a GraphQL+- query into items. It follows the lexer of
+// dgraph v1.0, including its quirks, so that both agree on every query.
+type lexer struct {
+	input    string
+	start    int
+	pos      int
+	width    int
+	items    []item
+	depth    int // nesting of {}
+	argDepth int // nesting of ()
+	mode     stateFn
+}
+
+func lex(input string) []item {
+	l := &lexer{input: input}
+	for state := stateFn(lexTopLevel); state != nil; {
+		state = state(l)
+	}
+	return l.items
+}
+
+func (l *lexer) errorf(format string, args ...interface{}) stateFn {
+	l.items = append(l.items, item{typ: itemError, val: fmt.Sprintf(format, args...), pos: l.start})
+	return nil
+}
+
+func (l *lexer) emit(t itemType) {
+	if t != itemEOF && l.pos < l.start {
+		return
+	}
+	l.items = append(l.items, item{typ: t, val: l.input[l.start:l.pos], pos: l.start})
+	l.start = l.pos
+}
+
+func (l *lexer) next() rune {
+	if l.pos >= len(l.input) {
+		l.width = 0
+		return eof
+	}
+	r, w := utf8.DecodeRuneInString(l.input[l.pos:])
+	l.width = w
+	l.pos += w
+	return r
+}
+
+func (l *lexer) backup() {
+	l.pos -= l.width
+}
+
+func (l *lexer) peek() rune {
+	r := l.next()
+	l.backup()
+	return r
+}
+
+func (l *lexer) ignore() {
+	l.start = l.pos
+}
+
+func (l *lexer) acceptRun(valid func(rune) bool) {
+	for {
+		r := l.next()
+		if r == eof || !valid(r) {
+			break
+		}
+	}
+	l.backup()
+}
+
+func (l *lexer) lexQuotedString() error {
+	for {
+		r := l.next()
+		if r == eof {
+			return fmt.Errorf("unexpected end of input in string")
+		}
+
+		if r == '\\' {
+			if r := l.next(); !isEscChar(r) {
+				return fmt.Errorf("invalid escape character %q", r)
+			}
+			continue
+		}
+
+		if r == '"' {
+			return nil
+		}
+	}
+}
+
+func lexTopLevel(l *lexer) stateFn {
+	l.mode = lexTopLevel
+	for {
+		switch r := l.next(); {
+		case r == '{':
+			l.depth++
+			l.emit(itemLeftCurl)
+			return lexQuery
+		case r == '}':
+			return l.errorf("too many right curly brackets")
+		case r == eof:
+			if l.pos > l.start {
+				l.emit(itemText)
+			}
+			l.emit(itemEOF)
+			return nil
+		case r == '#':
+			return lexComment
+		case r == '(':
+			l.backup()
+			l.emit(itemText)
+			l.next()
+			l.emit(itemLeftRound)
+			l.argDepth++
+			return lexQuery
+		case isSpace(r) || isEndOfLine(r):
+			l.ignore()
+		case isNameBegin(r):
+			l.backup()
+			return lexOperationType
+		}
+	}
+}
+
+func lexQuery(l *lexer) stateFn {
+	l.mode = lexQuery
+	for {
+		switch r := l.next(); {
+		case r == '.':
+			l.emit(itemPeriod)
+		case r == '}':
+			l.depth--
+			l.emit(itemRightCurl)
+			if l.depth == 0 {
+				return lexTopLevel
+			}
+		case r == '{':
+			l.depth++
+			l.emit(itemLeftCurl)
+		case r == eof:
+			return l.errorf("unclosed block")
+		case isSpace(r) || isEndOfLine(r):
+			l.ignore()
+		case r == ',':
+			l.emit(itemComma)
+		case isNameBegin(r):
+			return lexName
+		case r == '#':
+			return lexComment
+		case r == '-':
+			l.emit(itemMathOp)
+		case r == '(':
+			l.emit(itemLeftRound)
+			l.acceptRun(isSpace)
+			l.ignore()
+			l.argDepth++
+			return lexFuncOrArg
+		case r == ':':
+			l.emit(itemColon)
+		case r == '@':
+			l.emit(itemAt)
+			return lexDirectiveOrLangList
+		case r == '<':
+			return lexIRIRef
+		default:
+			return l.errorf("unrecognized character %#U", r)
+		}
+	}
+}
+
+func lexFuncOrArg(l *lexer) stateFn {
+	l.mode = lexFuncOrArg
+	empty := false
+	for {
+		switch r := l.next(); {
+		case r == '@':
+			l.emit(itemAt)
+			return lexDirectiveOrLangList
+		case isNameBegin(r) || isNumber(r):
+			return lexName
+		case r == '/' && empty:
+			return lexRegex
+		case isMathOp(r):
+			l.emit(itemMathOp)
+		case isInequalityOp(r):
+			if r == '=' && !isInequalityOp(l.peek()) {
+				l.emit(itemEqual)
+				continue
+			}
+
+			if r == '<' && !isSpace(l.peek()) && l.peek() != '=' {
+				return lexIRIRef
+			}
+
+			if isInequalityOp(l.peek()) {
+				l.next()
+			}
+			l.emit(itemMathOp)
+		case r == '(':
+			l.emit(itemLeftRound)
+			l.argDepth++
+		case r == ')':
+			if l.argDepth == 0 {
+				return l.errorf("unexpected right round bracket")
+			}
+			l.argDepth--
+			l.emit(itemRightRound)
+			if empty {
+				return l.errorf("empty argument")
+			}
+			if l.argDepth == 0 {
+				return lexQuery
+			}
+		case r == eof:
+			return l.errorf("unclosed round brackets")
+		case isSpace(r) || isEndOfLine(r):
+			l.ignore()
+		case r == ',':
+			if empty {
+				return l.errorf("consecutive commas are not allowed")
+			}
+			empty = true
+			l.emit(itemComma)
+		case r == '$':
+			l.emit(itemDollar)
+		case r == ':':
+			l.emit(itemColon)
+		case r == '"':
+			empty = false
+			if err := l.lexQuotedString(); err != nil {
+				return l.errorf("%s", err)
+			}
+			l.emit(itemName)
+		case r == '[':
+			l.emit(itemLeftSquare)
+		case r == ']':
+			l.emit(itemRightSquare)
+		case r == '#':
+			return lexComment
+		case r == '.':
+			l.emit(itemPeriod)
+		default:
+			return l.errorf("unrecognized character %#U inside a function", r)
+		}
+	}
+}
+
+func lexIRIRef(l *lexer) stateFn {
+	l.ignore()
+	for {
+		r := l.next()
+		if r == eof {
+			return l.errorf("unexpected end of IRI")
+		}
+
+		if r == '>' {
+			l.backup()
+			break
+		}
+
+		if r <= ' ' || r == '<' || r == '"' || r == '{' || r == '}' || r == '|' || r == '^' || r == '`' || r == '\\' {
+			return l.errorf("unexpected character %q in IRI", r)
+		}
+	}
+	l.emit(itemName)
+	l.next()
+	l.ignore()
+	return l.mode
+}
+
+// lexDirectiveOrLangList is called right after an @.
+func lexDirectiveOrLangList(l *lexer) stateFn {
+	r := l.next()
+	if !isNameBegin(r) && r != '.' {
+		return l.errorf("unrecognized character %#U after @", r)
+	}
+	l.backup()
+
+	for {
+		r := l.next()
+		if r == '.' {
+			l.emit(itemName)
+			return l.mode
+		}
+
+		if !isNameBegin(r) && !isNumber(r) && r != '-' {
+			l.backup()
+			l.emit(itemName)
+			return l.mode
+		}
+	}
+}
+
+func lexName(l *lexer) stateFn {
+	l.acceptRun(isNameSuffix)
+	l.emit(itemName)
+	return l.mode
+}
+
+func lexComment(l *lexer) stateFn {
+	for {
+		r := l.next()
+		if isEndOfLine(r) {
+			l.ignore()
+			return l.mode
+		}
+
+		if r == eof {
+			l.ignore()
+			l.emit(itemEOF)
+			return l.mode
+		}
+	}
+}
+
+func lexRegex(l *lexer) stateFn {
+	for {
+		switch l.next() {
+		case eof:
+			return l.errorf("unclosed regexp")
+		case '\\':
+			l.next()
+		case '/':
+			l.acceptRun(isRegexFlag)
+			l.emit(itemRegex)
+			return l.mode
+		}
+	}
+}
+
+// lexOperationType lexes the operation type of a top level block. Only
+// queries and fragments are lexed any further.
+func lexOperationType(l *lexer) stateFn {
+	l.acceptRun(isNameSuffix)
+	switch word := l.input[l.start:l.pos]; word {
+	case "query", "fragment":
+		l.emit(itemOpType)
+		return lexQuery
+	case "mutation", "schema":
+		l.emit(itemOpType)
+		return nil
+	default:
+		return l.errorf("invalid operation type %s", word)
+	}
+}
+
+func isEscChar(r rune) bool {
+	switch r {
+	case 'v', 't', 'b', 'n', 'r', 'f', '"', '\'', '\\':
+		return true
+	}
+	return false
+}
+
+func isSpace(r rune) bool {
+	return r == '\t' || r == ' '
+}
+
+func isEndOfLine(r rune) bool {
+	return r == '\n' || r == '\r'
+}
+
+func isNameBegin(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_' || r == '~'
+}
+
+func isNumber(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isNameSuffix(r rune) bool {
+	return isNameBegin(r) || isNumber(r) || r == '.'
+}
+
+func isMathOp(r rune) bool {
+	switch r {
+	case '+', '-', '*', '/', '%':
+		return true
+	}
+	return false
+}
+
+func isInequalityOp(r rune) bool {
+	switch r {
+	case '<', '>', '=', '!':
+		return true
+	}
+	return false
+}
+
+func isRegexFlag(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,45 @@
+package gql
+
+// Star matches every predicate or every value of a predicate in
+// delete mutations, e.g. `<0x1> * * .` or `<0x1> <name> * .`.
+const Star = "*"
+
+// Mutation stores the N-Quads to set and to delete in a single
+// mutation.
+type Mutation struct {
+	Set    []NQuad `yaml:"set,omitempty" json:"set,omitempty"`
+	Delete []NQuad `yaml:"delete,omitempty" json:"delete,omitempty"`
+}
+
+// NQuad is a single triple. Subject and ObjectID are uids (`0x1`),
+// blank nodes (`_:alice`), Star or, in upserts, references to query
+// variables (`uid(v)`, and `val(v)` for ObjectID). An NQuad either
+// points to another node through ObjectID or stores a literal in
+// ObjectValue.
+type NQuad struct {
+	Subject     string  `yaml:"subject,omitempty" json:"subject,omitempty"`
+	Predicate   string  `yaml:"predicate,omitempty" json:"predicate,omitempty"`
+	ObjectID    string  `yaml:"objectId,omitempty" json:"objectId,omitempty"`
+	ObjectValue string  `yaml:"objectValue,omitempty" json:"objectValue,omitempty"`
+	ObjectType  string  `yaml:"objectType,omitempty" json:"objectType,omitempty"` // rdf type of the value, e.g. xs:int
+	Lang        string  `yaml:"lang,omitempty" json:"lang,omitempty"`
+	Facets      []Facet `yaml:"facets,omitempty" json:"facets,omitempty"`
+}
+
+type FacetType int32
+
+const (
+	FacetString FacetType = iota
+	FacetInt
+	FacetFloat
+	FacetBool
+	FacetDateTime
+)
+
+// Facet is a key value pair stored on an edge. Value holds the
+// lexical form of the value, e.g. `2006-01-02T15:04:05` for datetimes.
+type Facet struct {
+	Key     string    `yaml:"key,omitempty" json:"key,omitempty"`
+	Value   string    `yaml:"value,omitempty" json:"value,omitempty"`
+	ValType FacetType `yaml:"valType,omitempty" json:"valType,omitempty"`
+}
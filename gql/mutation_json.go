@@ -0,0 +1,186 @@
+package gql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseJSONMutation converts a mutation in dgraph's JSON format into
+// N-Quads. Nested objects become edges, `pred|key` fields become facets
+// and null values or objects only holding a uid become star deletes.
+// Objects without a uid are given blank nodes.
+func ParseJSONMutation(data []byte) ([]NQuad, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	p := jsonMutationParser{}
+	switch v := v.(type) {
+	case []interface{}:
+		for _, e := range v {
+			obj, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected object, got %T", e)
+			}
+
+			if _, err := p.object(obj, true); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		if _, err := p.object(v, true); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("expected object or list, got %T", v)
+	}
+
+	return p.res, nil
+}
+
+type jsonMutationParser struct {
+	res    []NQuad
+	blanks int
+}
+
+func (p *jsonMutationParser) object(obj map[string]interface{}, top bool) (string, error) {
+	subject, ok := obj["uid"].(string)
+	if !ok {
+		if _, exists := obj["uid"]; exists {
+			return "", fmt.Errorf("uid must be a string, got %T", obj["uid"])
+		}
+		p.blanks++
+		subject = fmt.Sprintf("_:blank-%d", p.blanks)
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		if k != "uid" && !strings.Contains(k, "|") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	if top && len(keys) == 0 {
+		// Without a uid this would delete all edges of a new blank node,
+		// or set nothing.
+		if _, exists := obj["uid"]; !exists {
+			return "", fmt.Errorf("empty object")
+		}
+		p.res = append(p.res, NQuad{Subject: subject, Predicate: Star, ObjectID: Star})
+		return subject, nil
+	}
+
+	for _, k := range keys {
+		pred, lang := k, ""
+		if i := strings.LastIndex(k, "@"); i > 0 {
+			pred, lang = k[:i], k[i+1:]
+		}
+
+		values, ok := obj[k].([]interface{})
+		if !ok {
+			values = []interface{}{obj[k]}
+		}
+
+		for _, v := range values {
+			nq := NQuad{Subject: subject, Predicate: pred, Lang: lang}
+			switch v := v.(type) {
+			case nil:
+				nq.ObjectID = Star
+				p.res = append(p.res, nq)
+			case map[string]interface{}:
+				facets, err := jsonFacets(v, k)
+				if err != nil {
+					return "", err
+				}
+				nq.Facets = facets
+
+				i := len(p.res)
+				p.res = append(p.res, nq)
+				id, err := p.object(v, false)
+				if err != nil {
+					return "", err
+				}
+				p.res[i].ObjectID = id
+			default:
+				if s, ok := v.(string); ok && strings.HasPrefix(s, "val(") && strings.HasSuffix(s, ")") {
+					nq.ObjectID = s
+					p.res = append(p.res, nq)
+					continue
+				}
+
+				value, typ, err := jsonValue(v)
+				if err != nil {
+					return "", fmt.Errorf("%s: %s", k, err)
+				}
+				nq.ObjectValue, nq.ObjectType = value, typ
+
+				facets, err := jsonFacets(obj, k)
+				if err != nil {
+					return "", err
+				}
+				nq.Facets = facets
+				p.res = append(p.res, nq)
+			}
+		}
+	}
+
+	return subject, nil
+}
+
+func jsonValue(v interface{}) (string, string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, "", nil
+	case bool:
+		return strconv.FormatBool(v), "xs:boolean", nil
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return v.String(), "xs:int", nil
+		}
+		return v.String(), "xs:float", nil
+	}
+
+	return "", "", fmt.Errorf("unsupported value %v", v)
+}
+
+// jsonFacets collects the facets stored as `pred|key` fields of obj.
+func jsonFacets(obj map[string]interface{}, pred string) ([]Facet, error) {
+	prefix := pred + "|"
+	keys := []string{}
+	for k := range obj {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var res []Facet
+	for _, k := range keys {
+		f := Facet{Key: strings.TrimPrefix(k, prefix)}
+		switch v := obj[k].(type) {
+		case string:
+			f.Value = v
+		case bool:
+			f.Value, f.ValType = strconv.FormatBool(v), FacetBool
+		case json.Number:
+			f.Value, f.ValType = v.String(), FacetFloat
+			if _, err := v.Int64(); err == nil {
+				f.ValType = FacetInt
+			}
+		default:
+			return nil, fmt.Errorf("%s: unsupported facet value %v", k, v)
+		}
+		res = append(res, f)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,273 @@
+package gql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseMutation parses the set and delete N-Quads of a mutation.
+func ParseMutation(set, del string) (Mutation, error) {
+	s, err := ParseNQuads(set)
+	if err != nil {
+		return Mutation{}, err
+	}
+
+	d, err := ParseNQuads(del)
+	if err != nil {
+		return Mutation{}, err
+	}
+
+	return Mutation{Set: s, Delete: d}, nil
+}
+
+// ParseNQuads parses one N-Quad per line. Empty lines and comments are
+// skipped.
+func ParseNQuads(s string) ([]NQuad, error) {
+	var res []NQuad
+	for i, line := range strings.Split(s, "\n") {
+		p := nquadParser{in: line}
+		p.skipSpace()
+		if p.done() || p.peek() == '#' {
+			continue
+		}
+
+		nq, err := p.parse()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		res = append(res, nq)
+	}
+
+	return res, nil
+}
+
+type nquadParser struct {
+	in  string
+	pos int
+}
+
+func (p *nquadParser) done() bool {
+	return p.pos >= len(p.in)
+}
+
+func (p *nquadParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.in[p.pos]
+}
+
+func (p *nquadParser) skipSpace() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\r') {
+		p.pos++
+	}
+}
+
+func (p *nquadParser) expect(c byte) error {
+	p.skipSpace()
+	if p.peek() != c {
+		return fmt.Errorf("expected %q at position %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *nquadParser) until(stop string) string {
+	start := p.pos
+	for !p.done() && !strings.ContainsRune(stop, rune(p.peek())) {
+		p.pos++
+	}
+	return p.in[start:p.pos]
+}
+
+func (p *nquadParser) parse() (NQuad, error) {
+	var nq NQuad
+	var err error
+
+	if nq.Subject, err = p.node(); err != nil {
+		return nq, err
+	}
+
+	if nq.Predicate, err = p.predicate(); err != nil {
+		return nq, err
+	}
+
+	if err := p.object(&nq); err != nil {
+		return nq, err
+	}
+
+	p.skipSpace()
+	if p.peek() == '(' {
+		if nq.Facets, err = p.facets(); err != nil {
+			return nq, err
+		}
+	}
+
+	if err := p.expect('.'); err != nil {
+		return nq, err
+	}
+
+	p.skipSpace()
+	if !p.done() && p.peek() != '#' {
+		return nq, fmt.Errorf("unexpected %q after end of N-Quad", p.in[p.pos:])
+	}
+
+	return nq, nil
+}
+
+func (p *nquadParser) iri() (string, error) {
+	if err := p.expect('<'); err != nil {
+		return "", err
+	}
+
+	iri := p.until(">")
+	if err := p.expect('>'); err != nil {
+		return "", err
+	}
+	return iri, nil
+}
+
+func (p *nquadParser) node() (string, error) {
+	p.skipSpace()
+	switch {
+	case p.peek() == '*':
+		p.pos++
+		return Star, nil
+	case strings.HasPrefix(p.in[p.pos:], "_:"):
+		return p.until(" \t"), nil
+	case strings.HasPrefix(p.in[p.pos:], "uid("), strings.HasPrefix(p.in[p.pos:], "val("):
+		ref := p.until(")")
+		if err := p.expect(')'); err != nil {
+			return "", err
+		}
+		return ref + ")", nil
+	default:
+		return p.iri()
+	}
+}
+
+func (p *nquadParser) predicate() (string, error) {
+	p.skipSpace()
+	if p.peek() == '*' {
+		p.pos++
+		return Star, nil
+	}
+	return p.iri()
+}
+
+func (p *nquadParser) object(nq *NQuad) error {
+	p.skipSpace()
+	if p.peek() != '"' {
+		id, err := p.node()
+		nq.ObjectID = id
+		return err
+	}
+
+	lit, err := p.literal()
+	if err != nil {
+		return err
+	}
+	nq.ObjectValue = lit
+
+	switch {
+	case p.peek() == '@':
+		p.pos++
+		nq.Lang = p.until(" \t(.")
+		if nq.Lang == "" {
+			return fmt.Errorf("empty language at position %d", p.pos)
+		}
+	case strings.HasPrefix(p.in[p.pos:], "^^"):
+		p.pos += 2
+		if nq.ObjectType, err = p.iri(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// literal reads a double quoted string starting at the current position.
+func (p *nquadParser) literal() (string, error) {
+	start := p.pos
+	p.pos++
+	for !p.done() {
+		switch p.peek() {
+		case '\\':
+			p.pos += 2
+			continue
+		case '"':
+			p.pos++
+			return strconv.Unquote(p.in[start:p.pos])
+		}
+		p.pos++
+	}
+
+	return "", fmt.Errorf("unterminated literal %s", p.in[start:])
+}
+
+func (p *nquadParser) facets() ([]Facet, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+
+	var res []Facet
+	for {
+		p.skipSpace()
+		key := strings.TrimSpace(p.until("=,)"))
+		if key == "" {
+			return nil, fmt.Errorf("empty facet key at position %d", p.pos)
+		}
+
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		f := Facet{Key: key}
+		if p.peek() == '"' {
+			v, err := p.literal()
+			if err != nil {
+				return nil, err
+			}
+			f.Value = v
+		} else {
+			f.Value = strings.TrimSpace(p.until(",)"))
+			f.ValType = facetType(f.Value)
+		}
+		res = append(res, f)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return res, nil
+		default:
+			return nil, fmt.Errorf("expected ',' or ')' at position %d", p.pos)
+		}
+	}
+}
+
+// facetType infers the type of an unquoted facet value the same way
+// dgraph does.
+func facetType(v string) FacetType {
+	if v == "" {
+		return FacetString
+	}
+
+	if _, err := strconv.ParseInt(v, 0, 64); err == nil {
+		return FacetInt
+	}
+
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return FacetFloat
+	}
+
+	if v == "true" || v == "false" {
+		return FacetBool
+	}
+
+	return FacetDateTime
+}
@@ -0,0 +1,1787 @@
+// Copyright 2015-2018 Dgraph Labs, Inc. and Contributors
+// Modifications copyright 2019 mooncamp.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Adapted from the query parser of Dgraph (github.com/dgraph-io/dgraph/gql).
+
+package gql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	uidFunc = "uid"
+	valFunc = "val"
+
+	uidVar   = 1
+	valueVar = 2
+	listVar  = 3
+
+	floatID TypeID = 3
+)
+
+// Request is a GraphQL+- query and the values of its GraphQL variables,
+// e.g. {"$name": "Alice"}.
+type Request struct {
+	Str       string
+	Variables map[string]string
+}
+
+// SyntaxError is returned by Parse for malformed queries. Line and
+// Column are 1-based and point at the offending token.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e SyntaxError) Error() string {
+	return fmt.Sprintf("line %d column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// Parse parses a GraphQL+- query into its data representation. It
+// accepts the syntax of dgraph v1.0 and returns the same queries as
+// parsing with dgraph and decoding the result.
+func Parse(r Request) ([]GraphQuery, error) {
+	vmap := make(varMap, len(r.Variables))
+	for k, v := range r.Variables {
+		vmap[k] = varInfo{value: v}
+	}
+
+	it := &itemIterator{input: r.Str, items: lex(r.Str), idx: -1}
+	fragments := map[string]*fragmentNode{}
+	var roots []*queryNode
+	for it.next() {
+		item := it.item()
+		switch item.typ {
+		case itemError:
+			return nil, it.errorf("%s", item.val)
+		case itemOpType:
+			switch item.val {
+			case "mutation", "schema":
+				return nil, it.errorf("%s blocks are not supported", item.val)
+			case "fragment":
+				fn, err := getFragment(it)
+				if err != nil {
+					return nil, err
+				}
+				fragments[fn.name] = fn
+			case "query":
+				q, err := getVariablesAndQuery(it, vmap)
+				if err != nil {
+					return nil, err
+				}
+				roots = append(roots, q)
+			}
+		case itemLeftCurl:
+			q, err := getQuery(it)
+			if err != nil {
+				return nil, err
+			}
+			roots = append(roots, q)
+		case itemName:
+			it.prev()
+			q, err := getQuery(it)
+			if err != nil {
+				return nil, err
+			}
+			roots = append(roots, q)
+		}
+	}
+
+	var needs, defines []string
+	for _, e := range roots {
+		if err := e.expandFragments(fragments); err != nil {
+			return nil, err
+		}
+
+		if err := substituteVariables(e, vmap); err != nil {
+			return nil, err
+		}
+
+		e.collectVars(&needs, &defines)
+	}
+
+	if err := checkDependency(needs, defines); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, e := range roots {
+		if e.Alias == "var" || e.Alias == "shortest" {
+			continue
+		}
+
+		if seen[e.Alias] {
+			return nil, fmt.Errorf("duplicate alias %s", e.Alias)
+		}
+		seen[e.Alias] = true
+	}
+
+	var res []GraphQuery
+	for _, e := range roots {
+		res = append(res, e.build())
+	}
+	return res, nil
+}
+
+// queryNode is a GraphQuery while it is being parsed. Children are
+// referenced by pointer so they can still be modified after they've
+// been added to their parent.
+type queryNode struct {
+	GraphQuery
+	children []*queryNode
+	fragment string // name of the fragment if this is a fragment spread
+	isEmpty  bool   // root without a function, only aggregating variables
+}
+
+func (n *queryNode) setArg(k, v string) {
+	if n.Args == nil {
+		n.Args = map[string]string{}
+	}
+	n.Args[k] = v
+}
+
+func (n *queryNode) build() GraphQuery {
+	gq := n.GraphQuery
+	if len(gq.Args) == 0 {
+		gq.Args = nil
+	}
+
+	for _, e := range n.children {
+		gq.Children = append(gq.Children, e.build())
+	}
+
+	return gq
+}
+
+type fragmentNode struct {
+	name    string
+	node    *queryNode
+	entered bool
+	exited  bool
+}
+
+func (fn *fragmentNode) expand(fragments map[string]*fragmentNode) error {
+	if fn.exited {
+		return nil
+	}
+
+	if fn.entered {
+		return fmt.Errorf("cycle in fragment %s", fn.name)
+	}
+
+	fn.entered = true
+	if err := fn.node.expandFragments(fragments); err != nil {
+		return err
+	}
+	fn.exited = true
+
+	return nil
+}
+
+func (n *queryNode) expandFragments(fragments map[string]*fragmentNode) error {
+	var children []*queryNode
+	for _, e := range n.children {
+		if e.fragment == "" {
+			if err := e.expandFragments(fragments); err != nil {
+				return err
+			}
+			children = append(children, e)
+			continue
+		}
+
+		fn, ok := fragments[e.fragment]
+		if !ok {
+			return fmt.Errorf("missing fragment %s", e.fragment)
+		}
+
+		if err := fn.expand(fragments); err != nil {
+			return err
+		}
+		children = append(children, fn.node.children...)
+	}
+
+	n.children = children
+	return nil
+}
+
+func (n *queryNode) collectVars(needs, defines *[]string) {
+	if n.Var != "" {
+		*defines = append(*defines, n.Var)
+	}
+
+	for _, e := range n.FacetVar {
+		*defines = append(*defines, e)
+	}
+
+	for _, e := range n.NeedsVar {
+		*needs = append(*needs, e.Name)
+	}
+
+	for _, e := range n.children {
+		e.collectVars(needs, defines)
+	}
+
+	if n.Filter != nil {
+		collectFilterVars(*n.Filter, needs)
+	}
+
+	if n.MathExp != nil {
+		collectMathVars(*n.MathExp, needs)
+	}
+}
+
+func collectFilterVars(ft FilterTree, needs *[]string) {
+	if ft.Func != nil {
+		for _, e := range ft.Func.NeedsVar {
+			*needs = append(*needs, e.Name)
+		}
+	}
+
+	for _, e := range ft.Child {
+		collectFilterVars(e, needs)
+	}
+}
+
+func collectMathVars(mt MathTree, needs *[]string) {
+	if mt.Var != "" {
+		*needs = append(*needs, mt.Var)
+		return
+	}
+
+	for _, e := range mt.Child {
+		collectMathVars(e, needs)
+	}
+}
+
+func removeDuplicates(s []string) []string {
+	sort.Strings(s)
+	out := s[:0]
+	for i := range s {
+		if i > 0 && s[i] == s[i-1] {
+			continue
+		}
+		out = append(out, s[i])
+	}
+	return out
+}
+
+// checkDependency makes sure every variable is defined exactly once and
+// is used.
+func checkDependency(needs, defines []string) error {
+	needs = removeDuplicates(needs)
+	n := len(defines)
+	defines = removeDuplicates(defines)
+
+	if len(defines) != n {
+		return fmt.Errorf("some variables are defined multiple times")
+	}
+
+	if len(defines) > len(needs) {
+		return fmt.Errorf("some variables are defined but not used: defined %v, used %v", defines, needs)
+	}
+
+	if len(defines) < len(needs) {
+		return fmt.Errorf("some variables are used but not defined: defined %v, used %v", defines, needs)
+	}
+
+	for i := range defines {
+		if defines[i] != needs[i] {
+			return fmt.Errorf("variables are not used properly: defined %v, used %v", defines, needs)
+		}
+	}
+
+	return nil
+}
+
+type itemIterator struct {
+	input string
+	items []item
+	idx   int
+}
+
+func (it *itemIterator) next() bool {
+	it.idx++
+	return it.idx < len(it.items)
+}
+
+func (it *itemIterator) item() item {
+	if it.idx < 0 || it.idx >= len(it.items) {
+		return item{typ: itemEOF, pos: len(it.input)}
+	}
+	return it.items[it.idx]
+}
+
+func (it *itemIterator) prev() {
+	if it.idx > 0 {
+		it.idx--
+	}
+}
+
+func (it *itemIterator) peek(n int) ([]item, bool) {
+	if it.idx+n+1 > len(it.items) {
+		return nil, false
+	}
+	return it.items[it.idx+1 : it.idx+n+1], true
+}
+
+func (it *itemIterator) peekOne() (item, bool) {
+	items, ok := it.peek(1)
+	if !ok {
+		return item{}, false
+	}
+	return items[0], true
+}
+
+// errorf returns a SyntaxError pointing at the current item.
+func (it *itemIterator) errorf(format string, args ...interface{}) error {
+	return it.errorAt(it.item(), format, args...)
+}
+
+func (it *itemIterator) errorAt(i item, format string, args ...interface{}) error {
+	pos := i.pos
+	if pos > len(it.input) {
+		pos = len(it.input)
+	}
+
+	line := strings.Count(it.input[:pos], "\n") + 1
+	column := utf8.RuneCountInString(it.input[strings.LastIndex(it.input[:pos], "\n")+1:pos]) + 1
+
+	return SyntaxError{Line: line, Column: column, Msg: fmt.Sprintf(format, args...)}
+}
+
+func tryParseItemType(it *itemIterator, typ itemType) (item, bool) {
+	i, ok := it.peekOne()
+	if !ok || i.typ != typ {
+		return item{}, false
+	}
+	it.next()
+	return i, true
+}
+
+func trySkipItemVal(it *itemIterator, val string) bool {
+	i, ok := it.peekOne()
+	if !ok || i.val != val {
+		return false
+	}
+	it.next()
+	return true
+}
+
+// collectName joins names separated by dashes, which are lexed as
+// separate items.
+func collectName(it *itemIterator, val string) string {
+	expectName := false
+	for {
+		i, ok := it.peekOne()
+		if !ok || !((i.typ == itemName && expectName) || (i.val == "-" && !expectName)) {
+			return val
+		}
+
+		it.next()
+		val += i.val
+		expectName = !expectName
+	}
+}
+
+type varInfo struct {
+	value string
+	typ   string
+}
+
+// varMap stores GraphQL variables by name, including the $.
+type varMap map[string]varInfo
+
+// getVariablesAndQuery parses a named query with its optional variable
+// declarations.
+func getVariablesAndQuery(it *itemIterator, vmap varMap) (*queryNode, error) {
+	name := ""
+	for it.next() {
+		item := it.item()
+		switch item.typ {
+		case itemError:
+			return nil, it.errorf("%s", item.val)
+		case itemName:
+			if name != "" {
+				return nil, it.errorf("query names can't have multiple words")
+			}
+			name = item.val
+		case itemLeftRound:
+			if name == "" {
+				return nil, it.errorf("variables can only be declared in named queries")
+			}
+
+			if err := parseGqlVariables(it, vmap); err != nil {
+				return nil, err
+			}
+
+			if err := checkValueType(vmap); err != nil {
+				return nil, err
+			}
+		case itemLeftCurl:
+			return getQuery(it)
+		}
+	}
+
+	return nil, it.errorf("expected { after query")
+}
+
+func parseGqlVariables(it *itemIterator, vmap varMap) error {
+	expectArg := true
+	for it.next() {
+		item := it.item()
+		switch {
+		case item.typ == itemDollar:
+			if !expectArg {
+				return it.errorf("missing comma in variable declaration")
+			}
+		case item.typ == itemRightRound:
+			if expectArg {
+				return it.errorf("invalid comma in variable declaration")
+			}
+			return nil
+		case item.typ == itemComma:
+			if expectArg {
+				return it.errorf("invalid comma in variable declaration")
+			}
+			expectArg = true
+			continue
+		default:
+			return it.errorf("expected a variable, got %s", item)
+		}
+
+		it.next()
+		if it.item().typ != itemName {
+			return it.errorf("expected a variable name, got %s", it.item())
+		}
+		name := "$" + it.item().val
+
+		it.next()
+		if it.item().typ != itemColon {
+			return it.errorf("expected a colon, got %s", it.item())
+		}
+
+		it.next()
+		if it.item().typ != itemName || it.item().val == "" {
+			return it.errorf("expected a variable type, got %s", it.item())
+		}
+		typ := it.item().val
+
+		it.next()
+		if it.item().typ == itemMathOp && it.item().val == "!" {
+			typ += "!"
+			it.next()
+		}
+		vmap[name] = varInfo{value: vmap[name].value, typ: typ}
+
+		switch it.item().typ {
+		case itemEqual:
+			it.next()
+			if it.item().typ != itemName {
+				return it.errorf("expected a default value, got %s", it.item())
+			}
+
+			if strings.HasSuffix(typ, "!") {
+				return it.errorf("required variable %s can't have a default value", name)
+			}
+
+			if vmap[name].value == "" {
+				v, err := unquoteIfQuoted(it.item().val)
+				if err != nil {
+					return it.errorf("%s", err)
+				}
+				vmap[name] = varInfo{value: v, typ: typ}
+			}
+		case itemRightRound:
+			return nil
+		default:
+			it.prev()
+		}
+		expectArg = false
+	}
+
+	return it.errorf("unclosed variable declaration")
+}
+
+func checkValueType(vmap varMap) error {
+	for k, v := range vmap {
+		typ := v.typ
+		if typ == "" {
+			return fmt.Errorf("type of variable %s not specified", k)
+		}
+
+		if strings.HasSuffix(typ, "!") {
+			if v.value == "" {
+				return fmt.Errorf("variable %s should be initialised", k)
+			}
+			typ = typ[:len(typ)-1]
+		}
+
+		if v.value == "" {
+			continue
+		}
+
+		var err error
+		switch typ {
+		case "int":
+			_, err = strconv.ParseInt(v.value, 0, 64)
+		case "float":
+			_, err = strconv.ParseFloat(v.value, 64)
+		case "bool":
+			_, err = strconv.ParseBool(v.value)
+		case "string":
+		default:
+			return fmt.Errorf("type %s of variable %s not supported", typ, k)
+		}
+
+		if err != nil {
+			return fmt.Errorf("expected %s %s but got %s", typ, k, v.value)
+		}
+	}
+
+	return nil
+}
+
+func substituteVar(f string, res *string, vmap varMap) error {
+	if len(f) > 0 && f[0] == '$' {
+		v, ok := vmap[f]
+		if !ok || v.typ == "" {
+			return fmt.Errorf("variable %s not defined", f)
+		}
+		*res = v.value
+	}
+	return nil
+}
+
+func substituteVariables(n *queryNode, vmap varMap) error {
+	for k, v := range n.Args {
+		val := v
+		if err := substituteVar(v, &val, vmap); err != nil {
+			return err
+		}
+		n.Args[k] = val
+	}
+
+	if id, ok := n.Args["id"]; ok && len(n.UID) == 0 {
+		if id == "" {
+			return fmt.Errorf("id can't be empty")
+		}
+
+		uids, err := parseID(id)
+		if err != nil {
+			return err
+		}
+		n.UID = append(n.UID, uids...)
+		delete(n.Args, "id")
+	}
+
+	if fn := n.Func; fn != nil {
+		if err := substituteVar(fn.Attr, &fn.Attr, vmap); err != nil {
+			return err
+		}
+
+		for i, e := range fn.Args {
+			if !e.IsGraphQLVar {
+				continue
+			}
+
+			if err := substituteVar(e.Value, &fn.Args[i].Value, vmap); err != nil {
+				return err
+			}
+
+			if fn.Name == "regexp" {
+				expr, flags, err := parseRegexArgs(fn.Args[i].Value)
+				if err != nil {
+					return err
+				}
+				fn.Args[i].Value = expr
+				fn.Args = append(fn.Args, Arg{Value: flags})
+			}
+		}
+	}
+
+	for _, e := range n.children {
+		if err := substituteVariables(e, vmap); err != nil {
+			return err
+		}
+	}
+
+	if n.Filter != nil {
+		return substituteFilterVariables(n.Filter, vmap)
+	}
+
+	return nil
+}
+
+func substituteFilterVariables(ft *FilterTree, vmap varMap) error {
+	if fn := ft.Func; fn != nil {
+		if err := substituteVar(fn.Attr, &fn.Attr, vmap); err != nil {
+			return err
+		}
+
+		for i, e := range fn.Args {
+			if fn.Name != uidFunc {
+				if err := substituteVar(e.Value, &fn.Args[i].Value, vmap); err != nil {
+					return err
+				}
+				continue
+			}
+
+			id, ok := vmap[e.Value]
+			if !ok {
+				return fmt.Errorf("variable %s not defined", e.Value)
+			}
+
+			if id.value == "" {
+				return fmt.Errorf("id can't be empty")
+			}
+
+			uids, err := parseID(id.value)
+			if err != nil {
+				return err
+			}
+			fn.UID = append(fn.UID, uids...)
+		}
+	}
+
+	for i := range ft.Child {
+		if err := substituteFilterVariables(&ft.Child[i], vmap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseID parses a single uid or a list of uids, e.g. `[0x1, 0x2]`,
+// given as the value of a GraphQL variable.
+func parseID(val string) ([]uint64, error) {
+	val = strings.NewReplacer(" ", "", "\t", "").Replace(val)
+	if val == "" {
+		return nil, fmt.Errorf("id can't be empty")
+	}
+
+	if val[0] != '[' {
+		uid, err := strconv.ParseUint(val, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		return []uint64{uid}, nil
+	}
+
+	if val[len(val)-1] != ']' {
+		return nil, fmt.Errorf("invalid id list %s", val)
+	}
+
+	var uids []uint64
+	for _, e := range strings.Split(val[1:len(val)-1], ",") {
+		if e == "" {
+			continue
+		}
+
+		if strings.ContainsAny(e, "[)") {
+			return nil, fmt.Errorf("invalid id list %s", val)
+		}
+
+		uid, err := strconv.ParseUint(e, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		uids = append(uids, uid)
+	}
+
+	return uids, nil
+}
+
+func unquoteIfQuoted(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s, nil
+	}
+	return strconv.Unquote(s)
+}
+
+// getQuery parses a query block: the root, its directives and its
+// children.
+func getQuery(it *itemIterator) (*queryNode, error) {
+	gq, err := getRoot(it)
+	if err != nil {
+		return nil, err
+	}
+
+	seenFilter := false
+	for {
+		if !it.next() {
+			return nil, it.errorf("invalid query")
+		}
+
+		item := it.item()
+		switch item.typ {
+		case itemLeftCurl:
+			return gq, godeep(it, gq)
+		case itemRightCurl:
+			return gq, nil
+		case itemName:
+			it.prev()
+			return gq, nil
+		case itemAt:
+			it.next()
+			item := it.item()
+			if item.typ != itemName {
+				return gq, nil
+			}
+
+			switch strings.ToLower(item.val) {
+			case "filter":
+				if seenFilter {
+					return nil, it.errorf("repeated filter at root")
+				}
+				seenFilter = true
+
+				filter, err := parseFilter(it)
+				if err != nil {
+					return nil, err
+				}
+				gq.Filter = filter
+			case "normalize":
+				gq.Normalize = true
+			case "cascade":
+				if err := parseCascade(it, gq); err != nil {
+					return nil, err
+				}
+			case "groupby":
+				gq.IsGroupby = true
+				if err := parseGroupby(it, gq); err != nil {
+					return nil, err
+				}
+			case "ignorereflex":
+				gq.IgnoreReflex = true
+			case "recurse":
+				gq.Recurse = true
+				if err := parseRecurseArgs(it, gq); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, it.errorf("unknown directive %s", item.val)
+			}
+		default:
+			return nil, it.errorf("expected {, got %s", item)
+		}
+	}
+}
+
+// parseCascade parses @cascade and its optional list of fields, e.g.
+// @cascade(name, age).
+func parseCascade(it *itemIterator, gq *queryNode) error {
+	if gq.Cascade {
+		return it.errorf("only one @cascade allowed")
+	}
+	gq.Cascade = true
+
+	if _, ok := tryParseItemType(it, itemLeftRound); !ok {
+		return nil
+	}
+
+	for it.next() {
+		if it.item().typ != itemName {
+			return it.errorf("expected a field inside @cascade, got %s", it.item())
+		}
+		field := collectName(it, it.item().val)
+		gq.CascadeFields = append(gq.CascadeFields, field)
+
+		if _, ok := tryParseItemType(it, itemRightRound); ok {
+			return nil
+		}
+
+		if _, ok := tryParseItemType(it, itemComma); !ok {
+			return it.errorf("expected a comma after %s inside @cascade", field)
+		}
+	}
+
+	return it.errorf("unclosed @cascade")
+}
+
+func parseRecurseArgs(it *itemIterator, gq *queryNode) error {
+	if _, ok := tryParseItemType(it, itemLeftRound); !ok {
+		return nil
+	}
+
+	for it.next() {
+		if it.item().typ != itemName {
+			return it.errorf("expected a key inside @recurse")
+		}
+		key := strings.ToLower(it.item().val)
+
+		if _, ok := tryParseItemType(it, itemColon); !ok {
+			return it.errorf("expected a colon after %s", key)
+		}
+
+		val, ok := tryParseItemType(it, itemName)
+		if !ok {
+			return it.errorf("expected a value for %s inside @recurse", key)
+		}
+
+		var err error
+		switch key {
+		case "depth":
+			gq.RecurseArgs.Depth, err = strconv.ParseUint(val.val, 0, 64)
+		case "loop":
+			gq.RecurseArgs.AllowLoop, err = strconv.ParseBool(val.val)
+		default:
+			return it.errorf("unexpected key %s inside @recurse", key)
+		}
+
+		if err != nil {
+			return it.errorf("invalid value %s for %s", val.val, key)
+		}
+
+		if _, ok := tryParseItemType(it, itemRightRound); ok {
+			return nil
+		}
+
+		if _, ok := tryParseItemType(it, itemComma); !ok {
+			return it.errorf("expected a comma after %s inside @recurse", val.val)
+		}
+	}
+
+	return nil
+}
+
+func getFragment(it *itemIterator) (*fragmentNode, error) {
+	name := ""
+	for it.next() {
+		item := it.item()
+		if item.typ == itemLeftCurl {
+			break
+		}
+
+		if item.typ != itemName {
+			return nil, it.errorf("unexpected %s in fragment", item)
+		}
+
+		if v := strings.TrimSpace(item.val); v != "" && name == "" {
+			name = v
+		}
+	}
+
+	if name == "" {
+		return nil, it.errorf("empty fragment name")
+	}
+
+	gq := &queryNode{}
+	if err := godeep(it, gq); err != nil {
+		return nil, err
+	}
+
+	return &fragmentNode{name: name, node: gq}, nil
+}
+
+var rootKeys = map[string]bool{
+	"func": true, "orderasc": true, "orderdesc": true, "first": true, "offset": true, "after": true,
+	"from": true, "to": true, "numpaths": true, "depth": true,
+}
+
+var childKeys = map[string]bool{
+	"orderasc": true, "orderdesc": true, "first": true, "offset": true, "after": true,
+}
+
+func isSortKey(k string) bool {
+	return k == "orderasc" || k == "orderdesc"
+}
+
+func attrAndLang(s string) (string, []string) {
+	i := strings.Index(s, "@")
+	if i < 0 {
+		return s, nil
+	}
+	return s[:i], strings.Split(s[i+1:], ":")
+}
+
+// getRoot parses the name and the arguments of a query block.
+func getRoot(it *itemIterator) (*queryNode, error) {
+	gq := &queryNode{}
+	if !it.next() {
+		return nil, it.errorf("invalid query")
+	}
+
+	item := it.item()
+	if item.typ != itemName {
+		return nil, it.errorf("expected a name, got %s", item)
+	}
+
+	next, ok := it.peekOne()
+	if !ok {
+		return nil, it.errorf("invalid query")
+	}
+
+	if next.typ == itemName && strings.ToLower(next.val) == "as" {
+		gq.Var = item.val
+		it.next()
+		it.next()
+		item = it.item()
+	}
+	gq.Alias = item.val
+
+	if !it.next() || it.item().typ != itemLeftRound {
+		return nil, it.errorf("expected (, got %s", it.item())
+	}
+
+	expectArg := true
+	order := map[string]bool{}
+	for it.next() {
+		item := it.item()
+		switch item.typ {
+		case itemName:
+			if !expectArg {
+				return nil, it.errorf("expected a comma, got %s", item)
+			}
+			expectArg = false
+		case itemRightRound:
+			if gq.Func == nil && len(gq.NeedsVar) == 0 && len(gq.Args) == 0 {
+				gq.isEmpty = true
+			}
+			return gq, nil
+		case itemComma:
+			if expectArg {
+				return nil, it.errorf("expected an argument, got a comma")
+			}
+			expectArg = true
+			continue
+		default:
+			return nil, it.errorf("expected an argument, got %s", item)
+		}
+
+		key := item.val
+		if !rootKeys[key] {
+			return nil, it.errorf("invalid argument %s at root", key)
+		}
+
+		if !it.next() || it.item().typ != itemColon {
+			return nil, it.errorf("expected a colon, got %s", it.item())
+		}
+
+		if key == "func" {
+			if gq.Func != nil {
+				return nil, it.errorf("only one function allowed at root")
+			}
+
+			name, _ := it.peekOne()
+			fn, err := parseFunction(it, gq)
+			if err != nil {
+				return nil, err
+			}
+
+			if !validFuncName(fn.Name) {
+				return nil, it.errorAt(name, "invalid function %s", fn.Name)
+			}
+			gq.Func = fn
+			gq.NeedsVar = append(gq.NeedsVar, fn.NeedsVar...)
+			continue
+		}
+
+		if !it.next() {
+			return nil, it.errorf("invalid query")
+		}
+		item = it.item()
+
+		val := ""
+		if item.typ == itemDollar {
+			it.next()
+			if it.item().typ != itemName {
+				return nil, it.errorf("expected a variable name, got %s", it.item())
+			}
+
+			if _, ok := gq.Args[key]; ok {
+				return nil, it.errorf("repeated argument %s at root", key)
+			}
+			gq.setArg(key, "$"+it.item().val)
+			continue
+		}
+
+		if item.typ == itemMathOp {
+			if item.val != "+" && item.val != "-" {
+				return nil, it.errorf("only + and - are allowed as unary operators, got %s", item.val)
+			}
+			val = item.val
+			it.next()
+			item = it.item()
+		}
+
+		if val == "" && item.val == valFunc {
+			count, err := parseVarList(it, gq)
+			if err != nil {
+				return nil, err
+			}
+
+			if count != 1 {
+				return nil, it.errorf("expected one variable, got %d", count)
+			}
+			gq.NeedsVar[len(gq.NeedsVar)-1].Typ = valueVar
+		} else {
+			val = collectName(it, val+item.val)
+			next, ok := it.peekOne()
+			if ok && next.typ == itemLeftRound && isSortKey(key) && val != valFunc {
+				return nil, it.errorf("expected val(), got %s() in order", val)
+			}
+
+			if ok && next.typ == itemAt {
+				it.next()
+				it.next()
+				langs, err := parseLanguageList(it)
+				if err != nil {
+					return nil, err
+				}
+				val = val + "@" + strings.Join(langs, ":")
+			}
+		}
+
+		if val == "" {
+			val = gq.NeedsVar[len(gq.NeedsVar)-1].Name
+			if len(gq.Order) > 0 && isSortKey(key) {
+				return nil, it.errorf("multiple sorting only allowed by predicates, got %s", val)
+			}
+		}
+
+		if isSortKey(key) {
+			if order[val] {
+				return nil, it.errorf("sorting by %s can only be done once", val)
+			}
+
+			attr, langs := attrAndLang(val)
+			gq.Order = append(gq.Order, Order{Attr: attr, Desc: key == "orderdesc", Langs: langs})
+			order[val] = true
+			continue
+		}
+
+		if _, ok := gq.Args[key]; ok {
+			return nil, it.errorf("repeated argument %s at root", key)
+		}
+		gq.setArg(key, val)
+	}
+
+	return nil, it.errorf("unclosed arguments")
+}
+
+type pair struct {
+	key string
+	val string
+}
+
+// parseArguments parses the arguments of a predicate.
+func parseArguments(it *itemIterator, gq *queryNode) ([]pair, error) {
+	var res []pair
+	expectArg := true
+	orderCount := 0
+	for it.next() {
+		var p pair
+		item := it.item()
+		switch item.typ {
+		case itemName:
+			if !expectArg {
+				return nil, it.errorf("expected a comma, got %s", item)
+			}
+
+			p.key = collectName(it, item.val)
+			if isSortKey(p.key) {
+				orderCount++
+			}
+			expectArg = false
+		case itemRightRound:
+			if expectArg {
+				return nil, it.errorf("expected an argument, got )")
+			}
+			return res, nil
+		case itemComma:
+			if expectArg {
+				return nil, it.errorf("expected an argument, got a comma")
+			}
+			expectArg = true
+			continue
+		default:
+			return nil, it.errorf("expected an argument, got %s", item)
+		}
+
+		it.next()
+		if it.item().typ != itemColon {
+			return nil, it.errorf("expected a colon, got %s", it.item())
+		}
+
+		it.next()
+		item = it.item()
+		if item.val == valFunc {
+			count, err := parseVarList(it, gq)
+			if err != nil {
+				return nil, err
+			}
+
+			if count != 1 {
+				return nil, it.errorf("expected one variable, got %d", count)
+			}
+			gq.NeedsVar[len(gq.NeedsVar)-1].Typ = valueVar
+			p.val = gq.NeedsVar[len(gq.NeedsVar)-1].Name
+			res = append(res, p)
+
+			if isSortKey(p.key) && orderCount > 1 {
+				return nil, it.errorf("multiple sorting only allowed by predicates, got %s", p.val)
+			}
+			continue
+		}
+
+		val := ""
+		switch item.typ {
+		case itemDollar:
+			val = "$"
+			it.next()
+			item = it.item()
+			if item.typ != itemName {
+				return nil, it.errorf("expected an argument value, got %s", item)
+			}
+		case itemMathOp:
+			if item.val != "+" && item.val != "-" {
+				return nil, it.errorf("only + and - are allowed as unary operators, got %s", item.val)
+			}
+			val = item.val
+			it.next()
+			item = it.item()
+		case itemName:
+		default:
+			return nil, it.errorf("expected an argument value, got %s", item)
+		}
+
+		p.val = collectName(it, val+item.val)
+		if next, ok := it.peekOne(); ok && next.typ == itemAt {
+			it.next()
+			it.next()
+			langs, err := parseLanguageList(it)
+			if err != nil {
+				return nil, err
+			}
+			p.val = p.val + "@" + strings.Join(langs, ":")
+		}
+
+		res = append(res, p)
+	}
+
+	return res, nil
+}
+
+func parseVarList(it *itemIterator, gq *queryNode) (int, error) {
+	it.next()
+	if it.item().typ != itemLeftRound {
+		return 0, it.errorf("expected ( after %s", valFunc)
+	}
+
+	count := 0
+	expectArg := true
+	for it.next() {
+		item := it.item()
+		if item.typ == itemRightRound {
+			break
+		}
+
+		switch item.typ {
+		case itemComma:
+			if expectArg {
+				return count, it.errorf("expected a variable, got a comma")
+			}
+			expectArg = true
+		case itemName:
+			if !expectArg {
+				return count, it.errorf("expected a comma, got %s", item)
+			}
+			count++
+			gq.NeedsVar = append(gq.NeedsVar, VarContext{Name: item.val, Typ: uidVar})
+			expectArg = false
+		}
+	}
+
+	if expectArg {
+		return count, it.errorf("expected a variable")
+	}
+
+	return count, nil
+}
+
+func parseLanguageList(it *itemIterator) ([]string, error) {
+	var langs []string
+	for item := it.item(); item.typ == itemName || item.typ == itemPeriod; item = it.item() {
+		langs = append(langs, item.val)
+		it.next()
+		if it.item().typ != itemColon {
+			break
+		}
+		it.next()
+	}
+
+	if it.item().typ == itemPeriod {
+		if next, ok := it.peekOne(); ok && next.typ == itemPeriod {
+			return nil, it.errorf("expected only one . in language list")
+		}
+	}
+	it.prev()
+
+	return langs, nil
+}
+
+func parseDirective(it *itemIterator, curp *queryNode) error {
+	it.prev()
+	valid := it.item().typ != itemLeftCurl
+	it.next()
+	if !valid || curp == nil || curp.IsInternal {
+		return it.errorf("invalid use of directive")
+	}
+
+	it.next()
+	item := it.item()
+	next, ok := it.peekOne()
+	if !ok || item.typ != itemName {
+		return it.errorf("expected directive or language list")
+	}
+
+	switch {
+	case item.val == "cascade":
+		return parseCascade(it, curp)
+	case item.val == "facets":
+		res, err := parseFacets(it)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case res.params != nil:
+			if curp.Facets != nil {
+				return it.errorf("only one @facets allowed")
+			}
+			if len(res.vars) > 0 {
+				curp.FacetVar = res.vars
+			}
+			curp.FacetOrder = res.order
+			curp.FacetDesc = res.desc
+			curp.Facets = res.params
+		case res.filter != nil:
+			if curp.FacetsFilter != nil {
+				return it.errorf("only one facets filter allowed")
+			}
+
+			var needs []string
+			collectFilterVars(*res.filter, &needs)
+			if len(needs) != 0 {
+				return it.errorf("variables are not allowed in facets filter")
+			}
+			curp.FacetsFilter = res.filter
+		default:
+			return it.errorf("invalid @facets")
+		}
+	case next.typ == itemLeftRound:
+		switch item.val {
+		case "filter":
+			if curp.Filter != nil {
+				return it.errorf("use and, or and round brackets instead of multiple filters")
+			}
+
+			filter, err := parseFilter(it)
+			if err != nil {
+				return err
+			}
+			curp.Filter = filter
+		case "groupby":
+			if curp.IsGroupby {
+				return it.errorf("only one @groupby allowed")
+			}
+			curp.IsGroupby = true
+			return parseGroupby(it, curp)
+		default:
+			return it.errorf("unknown directive %s", item.val)
+		}
+	case curp.Attr != "" && len(curp.Langs) == 0:
+		langs, err := parseLanguageList(it)
+		if err != nil {
+			return err
+		}
+
+		if len(langs) == 0 {
+			return it.errorf("expected at least one language for %s", curp.Attr)
+		}
+		curp.Langs = langs
+	default:
+		return it.errorf("expected directive or language list, got @%s", item.val)
+	}
+
+	return nil
+}
+
+func parseGroupby(it *itemIterator, gq *queryNode) error {
+	it.next()
+	if it.item().typ != itemLeftRound {
+		return it.errorf("expected ( after groupby")
+	}
+
+	count := 0
+	expectArg := true
+	alias := ""
+	for it.next() {
+		item := it.item()
+		if item.typ == itemRightRound {
+			break
+		}
+
+		if item.typ == itemComma {
+			if expectArg {
+				return it.errorf("expected a predicate, got a comma")
+			}
+			expectArg = true
+			continue
+		}
+
+		if item.typ != itemName {
+			continue
+		}
+
+		if !expectArg {
+			return it.errorf("expected a comma, got %s", item)
+		}
+
+		val := collectName(it, item.val)
+		next, ok := it.peekOne()
+		if !ok {
+			return it.errorf("unclosed @groupby")
+		}
+
+		if next.typ == itemColon {
+			if alias != "" {
+				return it.errorf("expected a predicate after %s:", alias)
+			}
+			alias = val
+			it.next()
+			continue
+		}
+
+		var langs []string
+		if next.typ == itemAt {
+			it.next()
+			it.next()
+			var err error
+			if langs, err = parseLanguageList(it); err != nil {
+				return err
+			}
+		}
+
+		gq.GroupbyAttrs = append(gq.GroupbyAttrs, GroupByAttr{Attr: val, Alias: alias, Langs: langs})
+		alias = ""
+		count++
+		expectArg = false
+	}
+
+	if expectArg {
+		return it.errorf("unnecessary comma in @groupby")
+	}
+
+	if count == 0 {
+		return it.errorf("expected at least one predicate in @groupby")
+	}
+
+	return nil
+}
+
+type countState int
+
+const (
+	countNotSeen countState = iota
+	countSeen
+	countSeenWithPred
+)
+
+func isAggregator(name string) bool {
+	return name == "min" || name == "max" || name == "sum" || name == "avg"
+}
+
+func validateEmptyBlockItem(it *itemIterator, val string) error {
+	saved := it.idx
+	defer func() { it.idx = saved }()
+
+	name := val
+	if _, ok := tryParseItemType(it, itemColon); ok {
+		item, ok := tryParseItemType(it, itemName)
+		if !ok {
+			return it.errorf("expected a name")
+		}
+		name = item.val
+	}
+
+	if _, ok := tryParseItemType(it, itemLeftRound); !ok || (name != "math" && !isAggregator(name)) {
+		return it.errorf("only aggregation and math functions are allowed in blocks without a function, got %s", name)
+	}
+
+	return nil
+}
+
+// godeep parses the children of gq up to the closing curly bracket.
+func godeep(it *itemIterator, gq *queryNode) error {
+	count := countNotSeen
+	alias, varName := "", ""
+	curp := gq
+
+	addChild := func(val string) error {
+		if count == countSeenWithPred {
+			return it.errorf("multiple predicates are not allowed in a single count")
+		}
+
+		if gq.IsCount {
+			return it.errorf("count can't have children")
+		}
+
+		child := &queryNode{GraphQuery: GraphQuery{Attr: val, IsCount: count == countSeen, Var: varName, Alias: alias}}
+		gq.children = append(gq.children, child)
+		varName, alias = "", ""
+		curp = child
+		if count == countSeen {
+			count = countSeenWithPred
+		}
+		return nil
+	}
+
+	for it.next() {
+		item := it.item()
+		switch item.typ {
+		case itemError:
+			return it.errorf("%s", item.val)
+		case itemEOF, itemRightCurl:
+			return nil
+		case itemPeriod:
+			dots := 1
+			for i := 0; i < 2; i++ {
+				if it.next() && it.item().typ == itemPeriod {
+					dots++
+				}
+			}
+
+			if dots != 3 {
+				return it.errorf("expected ..., got %d periods", dots)
+			}
+
+			it.next()
+			if it.item().typ == itemName {
+				gq.children = append(gq.children, &queryNode{fragment: it.item().val})
+			}
+		case itemName:
+			next, ok := it.peekOne()
+			if !ok {
+				return it.errorf("invalid query")
+			}
+
+			if next.typ == itemName && strings.ToLower(next.val) == "as" {
+				varName = item.val
+				it.next()
+				continue
+			}
+
+			val := collectName(it, item.val)
+			valLower := strings.ToLower(val)
+
+			if next, ok = it.peekOne(); !ok {
+				return it.errorf("invalid query")
+			}
+
+			if next.typ == itemColon {
+				alias = val
+				it.next()
+				continue
+			}
+
+			if gq.IsGroupby && !isAggregator(val) && val != "count" && count != countSeen {
+				return it.errorf("only aggregation and count are allowed inside @groupby, got %s", val)
+			}
+
+			if gq.isEmpty {
+				if err := validateEmptyBlockItem(it, valLower); err != nil {
+					return err
+				}
+			}
+
+			switch {
+			case valLower == "checkpwd":
+				child := &queryNode{GraphQuery: GraphQuery{Var: varName, Alias: alias}}
+				varName, alias = "", ""
+				it.prev()
+
+				fn, err := parseFunction(it, gq)
+				if err != nil {
+					return err
+				}
+				fn.Args = append(fn.Args, Arg{Value: fn.Attr})
+				child.Func = fn
+				child.Attr = fn.Attr
+				gq.children = append(gq.children, child)
+				curp = nil
+			case isAggregator(valLower):
+				child := &queryNode{GraphQuery: GraphQuery{Attr: valFunc, Var: varName, IsInternal: true, Alias: alias}}
+				varName, alias = "", ""
+
+				it.next()
+				if it.item().typ != itemLeftRound {
+					it.prev()
+					if err := addChild(val); err != nil {
+						return err
+					}
+					continue
+				}
+
+				it.next()
+				if gq.IsGroupby {
+					child.Attr = collectName(it, it.item().val)
+					child.IsInternal = false
+					if next, ok := it.peekOne(); ok && next.typ == itemAt {
+						it.next()
+						it.next()
+						langs, err := parseLanguageList(it)
+						if err != nil {
+							return err
+						}
+						child.Langs = langs
+					}
+				} else {
+					if it.item().val != valFunc {
+						return it.errorf("only variables are allowed in aggregations, got %s", it.item().val)
+					}
+
+					if _, err := parseVarList(it, child); err != nil {
+						return err
+					}
+					child.NeedsVar[len(child.NeedsVar)-1].Typ = valueVar
+				}
+
+				child.Func = &Function{Name: valLower, NeedsVar: child.NeedsVar}
+				it.next()
+				gq.children = append(gq.children, child)
+				curp = nil
+			case valLower == "math":
+				if varName == "" && alias == "" {
+					return it.errorf("math needs a variable or an alias")
+				}
+
+				tree, again, err := parseMathFunc(it, false)
+				if err != nil {
+					return err
+				}
+
+				if again {
+					return it.errorf("unexpected comma in math")
+				}
+
+				child := &queryNode{GraphQuery: GraphQuery{Attr: val, Alias: alias, Var: varName, MathExp: tree, IsInternal: true}}
+				varName, alias = "", ""
+				gq.children = append(gq.children, child)
+				curp = nil
+			case valLower == "expand":
+				if varName != "" {
+					return it.errorf("expand can't be assigned to a variable")
+				}
+
+				if alias != "" {
+					return it.errorf("expand can't have an alias")
+				}
+
+				it.next()
+				if it.item().typ != itemLeftRound {
+					return it.errorf("expected ( after expand")
+				}
+
+				it.next()
+				child := &queryNode{GraphQuery: GraphQuery{Attr: val, IsInternal: true}}
+				switch it.item().val {
+				case valFunc:
+					count, err := parseVarList(it, child)
+					if err != nil {
+						return err
+					}
+
+					if count != 1 {
+						return it.errorf("expand takes exactly one variable")
+					}
+					child.NeedsVar[len(child.NeedsVar)-1].Typ = listVar
+					child.Expand = child.NeedsVar[len(child.NeedsVar)-1].Name
+				default:
+					if it.item().typ != itemName {
+						return it.errorf("invalid argument %s to expand", it.item().val)
+					}
+					child.Expand = it.item().val
+				}
+
+				it.next()
+				gq.children = append(gq.children, child)
+				curp = child
+			case valLower == "count":
+				if count != countNotSeen {
+					return it.errorf("invalid use of count")
+				}
+				count = countSeen
+
+				it.next()
+				if it.item().typ != itemLeftRound {
+					it.prev()
+					count = countNotSeen
+					if err := addChild(val); err != nil {
+						return err
+					}
+					continue
+				}
+
+				next, ok := it.peek(2)
+				if !ok {
+					return it.errorf("unclosed count")
+				}
+
+				if next[0].typ == itemRightRound {
+					return it.errorf("count() is not allowed, use count(uid)")
+				}
+
+				if next[0].val == uidFunc && next[1].typ == itemRightRound {
+					if gq.IsGroupby {
+						it.next()
+						it.next()
+						if err := addChild(uidFunc); err != nil {
+							return err
+						}
+						continue
+					}
+
+					if varName != "" {
+						return it.errorf("count can't be assigned to a variable")
+					}
+					count = countNotSeen
+					gq.UidCount = true
+					gq.UidCountAlias = alias
+					alias = ""
+					it.next()
+					it.next()
+				}
+			case valLower == valFunc:
+				if varName != "" {
+					return it.errorf("val can't be assigned to a variable")
+				}
+
+				if count == countSeen {
+					return it.errorf("count of a variable is not allowed")
+				}
+
+				if next, ok := it.peekOne(); !ok || next.typ != itemLeftRound {
+					if err := addChild(val); err != nil {
+						return err
+					}
+					continue
+				}
+
+				child := &queryNode{GraphQuery: GraphQuery{Attr: val, IsInternal: true, Alias: alias}}
+				alias = ""
+
+				count, err := parseVarList(it, child)
+				if err != nil {
+					return err
+				}
+
+				if count != 1 {
+					return it.errorf("val takes exactly one variable")
+				}
+				child.NeedsVar[len(child.NeedsVar)-1].Typ = valueVar
+				gq.children = append(gq.children, child)
+				curp = nil
+			case valLower == uidFunc:
+				if count == countSeen {
+					return it.errorf("count of a variable is not allowed")
+				}
+
+				if next, ok := it.peekOne(); ok && next.typ == itemLeftRound {
+					return it.errorf("uid of a variable is not allowed")
+				}
+
+				if err := addChild(val); err != nil {
+					return err
+				}
+			default:
+				if err := addChild(val); err != nil {
+					return err
+				}
+			}
+		case itemLeftCurl:
+			if curp == nil {
+				return it.errorf("unexpected {")
+			}
+
+			if len(curp.Langs) > 0 {
+				return it.errorf("%s with languages can't have children", curp.Attr)
+			}
+
+			if err := godeep(it, curp); err != nil {
+				return err
+			}
+		case itemLeftRound:
+			if curp == nil {
+				return it.errorf("unexpected (")
+			}
+
+			if curp.Attr == "" {
+				return it.errorf("predicate name can't be empty")
+			}
+
+			args, err := parseArguments(it, curp)
+			if err != nil {
+				return err
+			}
+
+			order := map[string]bool{}
+			for _, p := range args {
+				if !childKeys[p.key] {
+					return it.errorf("invalid argument %s", p.key)
+				}
+
+				if _, ok := curp.Args[p.key]; ok {
+					return it.errorf("repeated argument %s of %s", p.key, curp.Attr)
+				}
+
+				if p.val == "" {
+					return it.errorf("empty argument %s", p.key)
+				}
+
+				if isSortKey(p.key) {
+					if order[p.val] {
+						return it.errorf("sorting by %s can only be done once", p.val)
+					}
+
+					attr, langs := attrAndLang(p.val)
+					curp.Order = append(curp.Order, Order{Attr: attr, Desc: p.key == "orderdesc", Langs: langs})
+					order[p.val] = true
+					continue
+				}
+
+				curp.setArg(p.key, p.val)
+			}
+		case itemAt:
+			if err := parseDirective(it, curp); err != nil {
+				return err
+			}
+		case itemRightRound:
+			if count != countSeenWithPred {
+				return it.errorf("unexpected )")
+			}
+			count = countNotSeen
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,574 @@
+// Copyright 2015-2018 Dgraph Labs, Inc. and Contributors
+// Modifications copyright 2019 mooncamp.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Adapted from the query parser of Dgraph (github.com/dgraph-io/dgraph/gql).
+
+package gql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func isGeoFunc(name string) bool {
+	return name == "near" || name == "contains" || name == "within" || name == "intersects"
+}
+
+func isInequalityFunc(name string) bool {
+	switch name {
+	case "eq", "le", "ge", "gt", "lt":
+		return true
+	}
+	return false
+}
+
+func validFuncName(name string) bool {
+	if isGeoFunc(name) || isInequalityFunc(name) {
+		return true
+	}
+
+	switch name {
+	case "regexp", "anyofterms", "allofterms", "alloftext", "anyoftext", "has", "uid", "uid_in", "anyof", "allof",
+		"type", "between":
+		return true
+	}
+	return false
+}
+
+// parseRegexArgs splits `/expr/flags` into the expression and its
+// flags.
+func parseRegexArgs(val string) (string, string, error) {
+	end := strings.LastIndex(val, "/")
+	if end <= 0 {
+		return "", "", fmt.Errorf("invalid regular expression %s", val)
+	}
+
+	return strings.Replace(val[1:end], `\/`, "/", -1), val[end+1:], nil
+}
+
+// parseGeoArgs reassembles the items of a geo argument, e.g.
+// [[1, 2], [3, 4]], into a single argument.
+func parseGeoArgs(it *itemIterator, fn *Function) error {
+	var b strings.Builder
+	b.WriteString("[")
+
+	depth := 1
+	for depth > 0 {
+		if !it.next() {
+			return it.errorf("unclosed geo argument")
+		}
+
+		item := it.item()
+		switch item.typ {
+		case itemLeftSquare:
+			depth++
+		case itemRightSquare:
+			depth--
+		case itemMathOp, itemComma, itemName:
+		default:
+			return it.errorf("unexpected %s in geo argument", item)
+		}
+		b.WriteString(item.val)
+
+		if depth > 4 {
+			return it.errorf("geo argument nested too deeply")
+		}
+	}
+	fn.Args = append(fn.Args, Arg{Value: b.String()})
+
+	next, ok := it.peekOne()
+	if !ok || (next.typ != itemRightRound && next.typ != itemComma) {
+		return it.errorf("expected ) or a comma after geo argument")
+	}
+
+	return nil
+}
+
+// parseFunction parses a function like eq(name@en, "Alice"). Uids and
+// GraphQL variables given to uid() at the root are stored in gq, which is
+// nil inside filters.
+func parseFunction(it *itemIterator, gq *queryNode) (*Function, error) {
+	if !it.next() {
+		return nil, it.errorf("expected a function")
+	}
+
+	item := it.item()
+	if item.typ != itemName {
+		return nil, it.errorf("expected a function, got %s", item)
+	}
+
+	fn := &Function{Name: strings.ToLower(collectName(it, item.val))}
+	if _, ok := tryParseItemType(it, itemLeftRound); !ok {
+		return nil, it.errorf("expected ( after %s", fn.Name)
+	}
+
+	var seenFuncArg, expectLang, isDollar bool
+	expectArg := true
+	attrItemsAgo := -1
+	for it.next() {
+		item := it.item()
+		if attrItemsAgo >= 0 {
+			attrItemsAgo++
+		}
+
+		val := ""
+		switch item.typ {
+		case itemRightRound:
+			return fn, finishFunction(it, fn)
+		case itemComma:
+			if expectArg {
+				return nil, it.errorf("unexpected comma")
+			}
+
+			if isDollar {
+				return nil, it.errorf("unexpected comma after $")
+			}
+			expectArg = true
+			continue
+		case itemLeftRound:
+			if seenFuncArg {
+				return nil, it.errorf("only one function is allowed as argument")
+			}
+			it.prev()
+			it.prev()
+
+			nested, err := parseFunction(it, nil)
+			if err != nil {
+				return nil, err
+			}
+			seenFuncArg = true
+
+			switch nested.Name {
+			case uidFunc:
+				if fn.Name != "uid_in" || fn.Attr == "" || len(fn.Args) > 0 {
+					return nil, it.errorf("uid is only allowed as second argument of uid_in")
+				}
+
+				if len(nested.UID) > 0 || len(nested.NeedsVar) == 0 {
+					return nil, it.errorf("only uid variables are allowed in uid_in")
+				}
+				fn.NeedsVar = append(fn.NeedsVar, nested.NeedsVar...)
+			case valFunc:
+				if len(nested.NeedsVar) > 1 {
+					return nil, it.errorf("only one variable is allowed in a function")
+				}
+
+				if fn.Attr == "" {
+					fn.Attr = nested.NeedsVar[0].Name
+					fn.IsValueVar = true
+				} else {
+					fn.Args = append(fn.Args, Arg{Value: nested.NeedsVar[0].Name, IsValueVar: true})
+				}
+				fn.NeedsVar = append(fn.NeedsVar, nested.NeedsVar...)
+				fn.NeedsVar[0].Typ = valueVar
+			case "count":
+				fn.Attr = nested.Attr
+				fn.IsCount = true
+			default:
+				return nil, it.errorf("only val and count are allowed inside a function, got %s", nested.Name)
+			}
+			expectArg = false
+			continue
+		case itemAt:
+			if attrItemsAgo != 1 {
+				return nil, it.errorf("@ must follow the attribute immediately")
+			}
+			expectLang = true
+			continue
+		case itemMathOp:
+			val = item.val
+			it.next()
+			item = it.item()
+		case itemDollar:
+			if isDollar {
+				return nil, it.errorf("unexpected $")
+			}
+			isDollar = true
+			continue
+		case itemRegex:
+			expr, flags, err := parseRegexArgs(item.val)
+			if err != nil {
+				return nil, it.errorf("%s", err)
+			}
+			fn.Args = append(fn.Args, Arg{Value: expr}, Arg{Value: flags})
+			expectArg = false
+			continue
+		case itemLeftSquare:
+			if isGeoFunc(fn.Name) {
+				if err := parseGeoArgs(it, fn); err != nil {
+					return nil, err
+				}
+				expectArg = false
+				continue
+			}
+
+			if !isInequalityFunc(fn.Name) && fn.Name != "uid_in" {
+				return nil, it.errorf("unexpected [ in %s", fn.Name)
+			}
+
+			if !it.next() {
+				return nil, it.errorf("unclosed [")
+			}
+			item = it.item()
+		case itemRightSquare:
+			if _, ok := it.peekOne(); !ok {
+				return nil, it.errorf("unclosed function %s", fn.Name)
+			}
+			expectArg = false
+			continue
+		case itemName:
+		default:
+			return nil, it.errorf("expected an argument of %s, got %s", fn.Name, item)
+		}
+
+		if next, ok := it.peekOne(); ok && next.typ == itemLeftRound {
+			continue
+		}
+
+		if !expectArg && !expectLang {
+			return nil, it.errorf("expected a comma or language, got %s", item)
+		}
+
+		v, err := unquoteIfQuoted(strings.Trim(collectName(it, item.val), " \t"))
+		if err != nil {
+			return nil, it.errorf("%s", err)
+		}
+		val += v
+
+		if val == "" && fn.Name != "eq" {
+			return nil, it.errorf("empty argument")
+		}
+
+		if val == uidFunc {
+			return nil, it.errorf("argument can't be uid")
+		}
+
+		if isDollar {
+			val = "$" + val
+			isDollar = false
+			if fn.Name == uidFunc && gq != nil {
+				if gq.Args["id"] != "" {
+					return nil, it.errorf("only one GraphQL variable is allowed in uid")
+				}
+				gq.setArg("id", val)
+			} else {
+				fn.Args = append(fn.Args, Arg{Value: val, IsGraphQLVar: true})
+			}
+			expectArg = false
+			continue
+		}
+
+		switch {
+		case fn.Attr == "" && fn.Name != uidFunc:
+			if strings.ContainsRune(item.val, '"') {
+				return nil, it.errorf("attribute %s must not be quoted", item.val)
+			}
+			fn.Attr = val
+			attrItemsAgo = 0
+		case expectLang:
+			fn.Lang = val
+			expectLang = false
+		case fn.Name != uidFunc:
+			fn.Args = append(fn.Args, Arg{Value: val})
+		}
+
+		if fn.Name == "var" {
+			return nil, it.errorf("unexpected var(), use uid() instead")
+		}
+		expectArg = false
+
+		switch fn.Name {
+		case valFunc:
+			fn.NeedsVar = append(fn.NeedsVar, VarContext{Name: val, Typ: valueVar})
+		case uidFunc:
+			uid, err := strconv.ParseUint(val, 0, 64)
+			if err == nil {
+				if gq != nil {
+					gq.UID = append(gq.UID, uid)
+				} else {
+					fn.UID = append(fn.UID, uid)
+				}
+				continue
+			}
+
+			if e, ok := err.(*strconv.NumError); ok && e.Err == strconv.ErrRange {
+				return nil, it.errorf("uid %s is too large", val)
+			}
+			fn.NeedsVar = append(fn.NeedsVar, VarContext{Name: val, Typ: uidVar})
+		}
+	}
+
+	return fn, finishFunction(it, fn)
+}
+
+// finishFunction checks the parsed function. The type name given to
+// type() isn't an attribute, it is stored as the only argument.
+func finishFunction(it *itemIterator, fn *Function) error {
+	if fn.Name == "type" {
+		if fn.Attr == "" || len(fn.Args) > 0 || fn.Lang != "" {
+			return it.errorf("type expects exactly one type name")
+		}
+		fn.Args = []Arg{{Value: fn.Attr}}
+		fn.Attr = ""
+		return nil
+	}
+
+	if fn.Name != uidFunc && fn.Attr == "" {
+		return it.errorf("function %s has no attribute", fn.Name)
+	}
+	return nil
+}
+
+var filterOpPrecedence = map[string]int{"not": 3, "and": 2, "or": 1}
+
+type filterStack []*FilterTree
+
+func (s *filterStack) push(t *FilterTree) { *s = append(*s, t) }
+
+func (s *filterStack) peek() *FilterTree { return (*s)[len(*s)-1] }
+
+func (s *filterStack) pop() (*FilterTree, bool) {
+	if len(*s) == 0 {
+		return nil, false
+	}
+	t := (*s)[len(*s)-1]
+	*s = (*s)[:len(*s)-1]
+	return t, true
+}
+
+func evalFilterStack(it *itemIterator, ops, values *filterStack) error {
+	op, ok := ops.pop()
+	if !ok {
+		return it.errorf("invalid filter")
+	}
+
+	if op.Op == "not" {
+		v, ok := values.pop()
+		if !ok {
+			return it.errorf("not needs an operand")
+		}
+		op.Child = []FilterTree{*v}
+	} else {
+		if len(*values) < 2 {
+			return it.errorf("%s needs two operands", op.Op)
+		}
+		v1, _ := values.pop()
+		v2, _ := values.pop()
+		op.Child = []FilterTree{*v2, *v1}
+	}
+
+	values.push(op)
+	return nil
+}
+
+// parseFilter parses the arguments of @filter using the shunting-yard
+// algorithm. An empty filter results in nil.
+func parseFilter(it *itemIterator) (*FilterTree, error) {
+	it.next()
+	if it.item().typ != itemLeftRound {
+		return nil, it.errorf("expected ( after filter")
+	}
+
+	ops := &filterStack{{Op: "("}}
+	values := &filterStack{}
+	for it.next() {
+		item := it.item()
+		lval := strings.ToLower(item.val)
+		switch {
+		case lval == "and" || lval == "or" || lval == "not":
+			for len(*ops) > 0 && filterOpPrecedence[ops.peek().Op] >= filterOpPrecedence[lval] {
+				if err := evalFilterStack(it, ops, values); err != nil {
+					return nil, err
+				}
+			}
+			ops.push(&FilterTree{Op: lval})
+		case item.typ == itemName:
+			it.prev()
+			fn, err := parseFunction(it, nil)
+			if err != nil {
+				return nil, err
+			}
+			values.push(&FilterTree{Func: fn})
+		case item.typ == itemLeftRound:
+			ops.push(&FilterTree{Op: "("})
+		case item.typ == itemRightRound:
+			for len(*ops) > 0 && ops.peek().Op != "(" {
+				if err := evalFilterStack(it, ops, values); err != nil {
+					return nil, err
+				}
+			}
+
+			if _, ok := ops.pop(); !ok {
+				return nil, it.errorf("unbalanced round brackets in filter")
+			}
+		default:
+			return nil, it.errorf("unexpected %s in filter", item)
+		}
+
+		if len(*ops) == 0 {
+			break
+		}
+	}
+
+	if len(*ops) != 0 {
+		return nil, it.errorf("unbalanced round brackets in filter")
+	}
+
+	if len(*values) == 0 {
+		return nil, nil
+	}
+
+	if len(*values) != 1 {
+		return nil, it.errorf("expected one expression in filter, got %d", len(*values))
+	}
+
+	return values.peek(), nil
+}
+
+type facetResult struct {
+	params *FacetParams
+	filter *FilterTree
+	vars   map[string]string
+	order  string
+	desc   bool
+}
+
+// parseFacets parses the arguments of @facets, which are either a list of
+// facets or a filter.
+func parseFacets(it *itemIterator) (facetResult, error) {
+	res, ok, err := tryParseFacetList(it)
+	if err != nil || ok {
+		return res, err
+	}
+
+	filter, err := parseFilter(it)
+	res.filter = filter
+	return res, err
+}
+
+type facetItem struct {
+	name    string
+	alias   string
+	varName string
+	ordered bool
+	desc    bool
+}
+
+// tryParseFacetItem parses `[orderasc|orderdesc|alias:] [v as] name`. If
+// it fails without an error nothing has been consumed.
+func tryParseFacetItem(it *itemIterator) (facetItem, bool, error) {
+	saved := it.idx
+	var res facetItem
+
+	item, ok := tryParseItemType(it, itemName)
+	if !ok {
+		it.idx = saved
+		return res, false, nil
+	}
+
+	if _, ok := tryParseItemType(it, itemColon); ok {
+		if item.val == "orderasc" || item.val == "orderdesc" {
+			res.ordered = true
+			res.desc = item.val == "orderdesc"
+		} else {
+			res.alias = item.val
+		}
+
+		if item, ok = tryParseItemType(it, itemName); !ok {
+			return res, false, it.errorf("expected a name after colon")
+		}
+	}
+
+	name := item.val
+	if !trySkipItemVal(it, "as") {
+		res.name = collectName(it, name)
+		return res, true, nil
+	}
+
+	if item, ok = tryParseItemType(it, itemName); !ok {
+		return res, false, it.errorf("expected a name in facet list")
+	}
+
+	res.name = collectName(it, item.val)
+	res.varName = name
+	return res, true, nil
+}
+
+// tryParseFacetList parses a list of facets. If it fails without an
+// error nothing has been consumed and the arguments may be a filter.
+func tryParseFacetList(it *itemIterator) (facetResult, bool, error) {
+	saved := it.idx
+	if _, ok := tryParseItemType(it, itemLeftRound); !ok {
+		return facetResult{params: &FacetParams{AllKeys: true}, vars: map[string]string{}}, true, nil
+	}
+
+	res := facetResult{params: &FacetParams{}, vars: map[string]string{}}
+	if _, ok := tryParseItemType(it, itemRightRound); ok {
+		return res, true, nil
+	}
+
+	for {
+		item, ok, err := tryParseFacetItem(it)
+		if err != nil {
+			return res, false, err
+		}
+
+		if !ok {
+			it.idx = saved
+			return facetResult{}, false, nil
+		}
+
+		if item.varName != "" {
+			if _, ok := res.vars[item.name]; ok {
+				return res, false, it.errorf("facet %s is assigned to multiple variables", item.name)
+			}
+			res.vars[item.name] = item.varName
+		}
+		res.params.Param = append(res.params.Param, FacetParam{Key: item.name, Alias: item.alias})
+
+		if item.ordered {
+			if res.order != "" {
+				return res, false, it.errorf("only one facet can be ordered")
+			}
+			res.order, res.desc = item.name, item.desc
+		}
+
+		if _, ok := tryParseItemType(it, itemRightRound); ok {
+			params := res.params.Param
+			sort.SliceStable(params, func(i, j int) bool { return params[i].Key < params[j].Key })
+
+			out := params[:0]
+			for i := range params {
+				if i+1 < len(params) && params[i].Key == params[i+1].Key {
+					continue
+				}
+				out = append(out, params[i])
+			}
+			res.params.Param = out
+
+			return res, true, nil
+		}
+
+		if _, ok := tryParseItemType(it, itemComma); !ok {
+			if len(res.params.Param) < 2 {
+				it.idx = saved
+				return facetResult{}, false, nil
+			}
+			return res, false, it.errorf("expected a comma or ) in facet list")
+		}
+	}
+}
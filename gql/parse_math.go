@@ -0,0 +1,262 @@
+// Copyright 2015-2018 Dgraph Labs, Inc. and Contributors
+// Modifications copyright 2019 mooncamp.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Adapted from the query parser of Dgraph (github.com/dgraph-io/dgraph/gql).
+
+package gql
+
+import (
+	"strconv"
+	"strings"
+)
+
+var mathOpPrecedence = map[string]int{
+	"u-":      500,
+	"floor":   105,
+	"ceil":    104,
+	"since":   103,
+	"exp":     100,
+	"ln":      99,
+	"sqrt":    98,
+	"cond":    90,
+	"pow":     89,
+	"logbase": 88,
+	"max":     85,
+	"min":     84,
+
+	"/": 50,
+	"*": 49,
+	"%": 48,
+	"-": 47,
+	"+": 46,
+
+	"<":  10,
+	">":  9,
+	"<=": 8,
+	">=": 7,
+	"==": 6,
+	"!=": 5,
+}
+
+func isMathFunc(f string) bool {
+	_, ok := mathOpPrecedence[f]
+	return ok && f != "u-"
+}
+
+func isUnaryMath(f string) bool {
+	switch f {
+	case "exp", "ln", "u-", "sqrt", "floor", "ceil", "since":
+		return true
+	}
+	return false
+}
+
+func isBinaryMath(f string) bool {
+	switch f {
+	case "*", "+", "-", "/", "%":
+		return true
+	}
+	return false
+}
+
+// isZero reports whether v makes f undefined, e.g. a division by zero.
+func isZero(f string, v Val) bool {
+	g, ok := v.Value.(float64)
+	if v.Tid != floatID || !ok {
+		return false
+	}
+
+	switch f {
+	case "floor":
+		return g >= 0 && g < 1
+	case "/", "%", "ceil", "sqrt", "u-":
+		return g == 0
+	case "ln":
+		return g == 1
+	}
+	return false
+}
+
+type mathStack []*MathTree
+
+func (s *mathStack) push(t *MathTree) { *s = append(*s, t) }
+
+func (s *mathStack) peek() *MathTree { return (*s)[len(*s)-1] }
+
+func (s *mathStack) pop() (*MathTree, bool) {
+	if len(*s) == 0 {
+		return nil, false
+	}
+	t := (*s)[len(*s)-1]
+	*s = (*s)[:len(*s)-1]
+	return t, true
+}
+
+func evalMathStack(it *itemIterator, ops, values *mathStack) error {
+	op, ok := ops.pop()
+	if !ok {
+		return it.errorf("invalid math expression")
+	}
+
+	switch {
+	case isUnaryMath(op.Fn):
+		v, ok := values.pop()
+		if !ok {
+			return it.errorf("%s needs one operand", op.Fn)
+		}
+
+		if len(*ops) > 1 {
+			if f := ops.peek().Fn; (f == "/" || f == "%") && isZero(op.Fn, v.Const) {
+				return it.errorf("division by zero")
+			}
+		}
+		op.Child = []MathTree{*v}
+	case op.Fn == "cond":
+		if len(*values) < 3 {
+			return it.errorf("cond needs three operands")
+		}
+		v1, _ := values.pop()
+		v2, _ := values.pop()
+		v3, _ := values.pop()
+		op.Child = []MathTree{*v3, *v2, *v1}
+	default:
+		if len(*values) < 2 {
+			return it.errorf("%s needs two operands", op.Fn)
+		}
+
+		if isZero(op.Fn, values.peek().Const) {
+			return it.errorf("division by zero")
+		}
+		v1, _ := values.pop()
+		v2, _ := values.pop()
+		op.Child = []MathTree{*v2, *v1}
+	}
+
+	values.push(op)
+	return nil
+}
+
+// parseMathFunc parses the arguments of math(). Arguments of functions
+// with several operands are parsed one at a time, again reports whether
+// another one follows.
+func parseMathFunc(it *itemIterator, again bool) (*MathTree, bool, error) {
+	if !again {
+		it.next()
+		if it.item().typ != itemLeftRound {
+			return nil, false, it.errorf("expected ( after math")
+		}
+	}
+
+	ops := &mathStack{{Fn: "("}}
+	values := &mathStack{}
+	parseArgs := func() error {
+		again := false
+		for {
+			child, next, err := parseMathFunc(it, again)
+			if err != nil {
+				return err
+			}
+			values.push(child)
+
+			if again = next; !again {
+				return nil
+			}
+		}
+	}
+
+	for it.next() {
+		item := it.item()
+		lval := strings.ToLower(item.val)
+		switch {
+		case isMathFunc(lval):
+			op := lval
+			it.prev()
+			last := it.item()
+			it.next()
+			if op == "-" && (last.val == "(" || last.val == "," || isBinaryMath(last.val)) {
+				op = "u-"
+			}
+
+			for len(*ops) > 0 && mathOpPrecedence[ops.peek().Fn] >= mathOpPrecedence[op] {
+				if err := evalMathStack(it, ops, values); err != nil {
+					return nil, false, err
+				}
+			}
+			ops.push(&MathTree{Fn: op})
+
+			next, ok := it.peekOne()
+			if !ok {
+				return nil, false, it.errorf("unclosed math expression")
+			}
+
+			if next.typ == itemLeftRound {
+				if err := parseArgs(); err != nil {
+					return nil, false, err
+				}
+			}
+		case item.typ == itemName:
+			next, ok := it.peekOne()
+			if !ok {
+				return nil, false, it.errorf("unclosed math expression")
+			}
+
+			if next.typ == itemLeftRound {
+				return nil, false, it.errorf("unknown math function %s", item.val)
+			}
+
+			child := &MathTree{}
+			if v, err := strconv.ParseFloat(item.val, 64); err == nil {
+				child.Const = Val{Tid: floatID, Value: v}
+			} else {
+				child.Var = item.val
+			}
+			values.push(child)
+		case item.typ == itemLeftRound:
+			ops.push(&MathTree{Fn: "("})
+		case item.typ == itemComma || item.typ == itemRightRound:
+			for len(*ops) > 0 && ops.peek().Fn != "(" {
+				if err := evalMathStack(it, ops, values); err != nil {
+					return nil, false, err
+				}
+			}
+
+			if _, ok := ops.pop(); !ok {
+				return nil, false, it.errorf("unbalanced round brackets in math expression")
+			}
+
+			if item.typ == itemRightRound && len(*ops) > 0 {
+				continue
+			}
+
+			if len(*ops) != 0 {
+				return nil, false, it.errorf("unexpected comma in math expression")
+			}
+
+			if len(*values) == 0 {
+				return nil, false, it.errorf("empty math expression")
+			}
+
+			if len(*values) != 1 {
+				return nil, false, it.errorf("expected one expression in math, got %d", len(*values))
+			}
+
+			return values.peek(), item.typ == itemComma, nil
+		default:
+			return nil, false, it.errorf("unexpected %s in math expression", item)
+		}
+	}
+
+	return nil, false, it.errorf("unclosed math expression")
+}
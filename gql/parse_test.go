@@ -0,0 +1,213 @@
+package gql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	table := []struct {
+		name      string
+		query     string
+		variables map[string]string
+		expected  []GraphQuery
+	}{
+		{
+			name:  "uid root",
+			query: `{ me(func: uid(0x1)) { name@en friends(first: 10) { name } } }`,
+			expected: []GraphQuery{
+				{
+					Alias: "me",
+					UID:   []uint64{1},
+					Func:  &Function{Name: "uid"},
+					Children: []GraphQuery{
+						{Attr: "name", Langs: []string{"en"}},
+						{Attr: "friends", Args: map[string]string{"first": "10"}, Children: []GraphQuery{{Attr: "name"}}},
+					},
+				},
+			},
+		},
+		{
+			name:      "variables",
+			query:     `query q($id: int, $name: string = "Alice") { me(func: uid($id)) @filter(eq(name, $name)) { name } }`,
+			variables: map[string]string{"$id": "0x2"},
+			expected: []GraphQuery{
+				{
+					Alias: "me",
+					UID:   []uint64{2},
+					Func:  &Function{Name: "uid"},
+					Filter: &FilterTree{
+						Func: &Function{Name: "eq", Attr: "name", Args: []Arg{{Value: "Alice", IsGraphQLVar: true}}},
+					},
+					Children: []GraphQuery{{Attr: "name"}},
+				},
+			},
+		},
+		{
+			name:  "math",
+			query: `{ me(func: uid(1)) { a as age  double: math(a * 2) } }`,
+			expected: []GraphQuery{
+				{
+					Alias: "me",
+					UID:   []uint64{1},
+					Func:  &Function{Name: "uid"},
+					Children: []GraphQuery{
+						{Attr: "age", Var: "a"},
+						{
+							Attr:       "math",
+							Alias:      "double",
+							IsInternal: true,
+							MathExp: &MathTree{Fn: "*", Child: []MathTree{
+								{Var: "a"},
+								{Const: Val{Tid: floatID, Value: 2.0}},
+							}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "type",
+			query: `{ me(func: type(Person)) @filter(type(Animal)) { expand(Person) } }`,
+			expected: []GraphQuery{
+				{
+					Alias:    "me",
+					Func:     &Function{Name: "type", Args: []Arg{{Value: "Person"}}},
+					Filter:   &FilterTree{Func: &Function{Name: "type", Args: []Arg{{Value: "Animal"}}}},
+					Children: []GraphQuery{{Attr: "expand", Expand: "Person", IsInternal: true}},
+				},
+			},
+		},
+		{
+			name:  "cascade",
+			query: `{ me(func: uid(1)) @cascade(name, age) { name age friend @cascade { name } } }`,
+			expected: []GraphQuery{
+				{
+					Alias:         "me",
+					UID:           []uint64{1},
+					Func:          &Function{Name: "uid"},
+					Cascade:       true,
+					CascadeFields: []string{"name", "age"},
+					Children: []GraphQuery{
+						{Attr: "name"},
+						{Attr: "age"},
+						{Attr: "friend", Cascade: true, Children: []GraphQuery{{Attr: "name"}}},
+					},
+				},
+			},
+		},
+		{
+			name:  "between and uid_in",
+			query: `{ s as var(func: has(school)) me(func: between(age, 18, 30)) @filter(uid_in(friend, [0x1, 0x2]) or uid_in(school, uid(s))) { name } }`,
+			expected: []GraphQuery{
+				{
+					Alias: "var",
+					Var:   "s",
+					Func:  &Function{Name: "has", Attr: "school"},
+				},
+				{
+					Alias: "me",
+					Func:  &Function{Name: "between", Attr: "age", Args: []Arg{{Value: "18"}, {Value: "30"}}},
+					Filter: &FilterTree{Op: "or", Child: []FilterTree{
+						{Func: &Function{Name: "uid_in", Attr: "friend", Args: []Arg{{Value: "0x1"}, {Value: "0x2"}}}},
+						{Func: &Function{Name: "uid_in", Attr: "school", NeedsVar: []VarContext{{Name: "s", Typ: uidVar}}}},
+					}},
+					Children: []GraphQuery{{Attr: "name"}},
+				},
+			},
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			res, err := Parse(Request{Str: e.query, Variables: e.variables})
+			require.NoError(t, err)
+			require.Equal(t, e.expected, res)
+		})
+	}
+}
+
+func TestParseSyntaxErrors(t *testing.T) {
+	table := []struct {
+		name     string
+		query    string
+		expected SyntaxError
+	}{
+		{
+			name:     "unclosed block",
+			query:    "{\n  me(func: uid(1)) {\n    name\n",
+			expected: SyntaxError{Line: 4, Column: 1, Msg: "unclosed block"},
+		},
+		{
+			name:     "invalid root argument",
+			query:    "{\n  me(func: uid(1), limit: 2) {\n    name\n  }\n}",
+			expected: SyntaxError{Line: 2, Column: 20, Msg: "invalid argument limit at root"},
+		},
+		{
+			name:     "unknown directive",
+			query:    "{ me(func: uid(1)) @sorted { name } }",
+			expected: SyntaxError{Line: 1, Column: 21, Msg: "unknown directive sorted"},
+		},
+		{
+			name:     "invalid function",
+			query:    "{\n\tme(func: foo(name, 1)) { name }\n}",
+			expected: SyntaxError{Line: 2, Column: 11, Msg: "invalid function foo"},
+		},
+		{
+			name:     "unexpected character",
+			query:    "{ me(func: uid(1)) { na;me } }",
+			expected: SyntaxError{Line: 1, Column: 24, Msg: "unrecognized character U+003B ';'"},
+		},
+		{
+			name:     "mutation",
+			query:    "mutation { set { <0x1> <name> \"A\" . } }",
+			expected: SyntaxError{Line: 1, Column: 1, Msg: "mutation blocks are not supported"},
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			_, err := Parse(Request{Str: e.query})
+			require.Equal(t, e.expected, err)
+		})
+	}
+}
+
+func TestParseSemanticErrors(t *testing.T) {
+	table := []struct {
+		name      string
+		query     string
+		variables map[string]string
+		expected  string
+	}{
+		{
+			name:     "undefined variable",
+			query:    `{ me(func: uid(a)) { name } }`,
+			expected: "some variables are used but not defined: defined [], used [a]",
+		},
+		{
+			name:     "unused variable",
+			query:    `{ me(func: uid(1)) { a as name } }`,
+			expected: "some variables are defined but not used: defined [a], used []",
+		},
+		{
+			name:     "duplicate alias",
+			query:    `{ me(func: uid(1)) { name } me(func: uid(2)) { name } }`,
+			expected: "duplicate alias me",
+		},
+		{
+			name:      "invalid variable value",
+			query:     `query q($n: int) { me(func: uid(1)) { friends(first: $n) { name } } }`,
+			variables: map[string]string{"$n": "ten"},
+			expected:  "expected int $n but got ten",
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			_, err := Parse(Request{Str: e.query, Variables: e.variables})
+			require.EqualError(t, err, e.expected)
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package gql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError is a structural problem of a query. Path points to
+// the offending part, e.g. `queries[0].children[2].filter.child[1]`.
+type ValidationError struct {
+	Path string
+	Msg  string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+}
+
+// ValidationErrors is the list of problems found by Validate.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	res := make([]string, 0, len(e))
+	for _, err := range e {
+		res = append(res, err.Error())
+	}
+	return strings.Join(res, "; ")
+}
+
+type varRef struct {
+	name string
+	path string
+}
+
+type validator struct {
+	errs    ValidationErrors
+	defines []varRef
+	needs   []varRef
+}
+
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, ValidationError{Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks the structure of queries without rendering them. It
+// returns every problem found, or nil if the queries are valid.
+func Validate(queries []GraphQuery) ValidationErrors {
+	v := &validator{}
+	aliases := map[string]string{}
+	for i, e := range queries {
+		if e.Alias == "" || e.Alias == "var" {
+			continue
+		}
+
+		path := fmt.Sprintf("queries[%d]", i)
+		if prev, ok := aliases[e.Alias]; ok {
+			v.errorf(path, "duplicate alias %s, already used by %s", e.Alias, prev)
+			continue
+		}
+		aliases[e.Alias] = path
+	}
+
+	_ = Walk(queries, func(c *Cursor) error {
+		switch n := c.Node().(type) {
+		case *GraphQuery:
+			if c.Parent() == nil {
+				v.root(c.Path(), *n)
+			}
+			v.block(c.Path(), *n)
+		case *Function:
+			v.function(c.Path(), *n)
+		case *FilterTree:
+			v.filter(c.Path(), *n)
+		case *MathTree:
+			v.math(c.Path(), *n)
+		}
+		return nil
+	})
+
+	v.variables()
+	return v.errs
+}
+
+func (v *validator) root(path string, gq GraphQuery) {
+	switch {
+	case gq.Func != nil, isEmptyBlock(gq):
+	case gq.Alias == "shortest":
+		if gq.Args["from"] == "" || gq.Args["to"] == "" {
+			v.errorf(path, "shortest needs from and to")
+		}
+	default:
+		v.errorf(path, "root block needs a function")
+	}
+
+	if gq.Func != nil && gq.Func.Name == "uid" && len(gq.UID) == 0 && len(gq.NeedsVar) == 0 && len(gq.Func.NeedsVar) == 0 {
+		v.errorf(path+".func", "uid needs at least one uid or variable")
+	}
+
+	if gq.Facets != nil || gq.FacetsFilter != nil || gq.FacetOrder != "" || len(gq.FacetVar) > 0 {
+		v.errorf(path, "facets are not allowed at root")
+	}
+}
+
+// isEmptyBlock reports whether gq is a block without a function, e.g.
+// `me() { max(val(a)) }`, which may only aggregate variables.
+func isEmptyBlock(gq GraphQuery) bool {
+	if len(gq.Children) == 0 || len(gq.UID) > 0 {
+		return false
+	}
+
+	for _, e := range gq.Children {
+		aggregate := e.Attr == "val" && e.Func != nil && isAggregator(e.Func.Name)
+		if e.MathExp == nil && !aggregate {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *validator) block(path string, gq GraphQuery) {
+	if gq.Var != "" {
+		v.defines = append(v.defines, varRef{name: gq.Var, path: path})
+	}
+
+	for _, k := range sortedKeys(gq.FacetVar) {
+		v.defines = append(v.defines, varRef{name: gq.FacetVar[k], path: fmt.Sprintf("%s.facetVar[%s]", path, k)})
+	}
+
+	for i, e := range gq.NeedsVar {
+		v.needs = append(v.needs, varRef{name: e.Name, path: fmt.Sprintf("%s.needsVar[%d]", path, i)})
+	}
+
+	switch gq.Cardinality {
+	case "", CardinalityMany, CardinalityOne, CardinalityOptionalOne:
+	default:
+		v.errorf(path, "unknown cardinality %q", gq.Cardinality)
+	}
+
+	for i, e := range gq.Order {
+		if e.Attr == "" {
+			v.errorf(fmt.Sprintf("%s.order[%d]", path, i), "order needs an attribute")
+		}
+	}
+
+	v.facets(path, gq)
+}
+
+func (v *validator) function(path string, fn Function) {
+	if fn.Name == "" {
+		v.errorf(path, "function needs a name")
+	}
+
+	if fn.Name == "checkpwd" {
+		if fn.Attr == "" {
+			v.errorf(path, "checkpwd needs an attribute")
+		}
+
+		// Dgraph appends the attribute to the password.
+		args := fn.Args
+		if len(args) == 2 && args[1].Value == fn.Attr {
+			args = args[:1]
+		}
+
+		if len(args) != 1 {
+			v.errorf(path, "checkpwd needs exactly one argument, got %d", len(args))
+		}
+	}
+
+	for i, e := range fn.NeedsVar {
+		v.needs = append(v.needs, varRef{name: e.Name, path: fmt.Sprintf("%s.needsVar[%d]", path, i)})
+	}
+}
+
+func (v *validator) filter(path string, ft FilterTree) {
+	switch ft.Op {
+	case "":
+		if ft.Func == nil {
+			v.errorf(path, "filter needs a function or an operator")
+		}
+
+		if len(ft.Child) > 0 {
+			v.errorf(path, "filter function can't have children")
+		}
+	case "not":
+		if len(ft.Child) != 1 {
+			v.errorf(path, "not needs exactly one child, got %d", len(ft.Child))
+		}
+	case "and", "or":
+		if len(ft.Child) < 2 {
+			v.errorf(path, "%s needs at least two children, got %d", ft.Op, len(ft.Child))
+		}
+	default:
+		v.errorf(path, "unknown filter operator %s", ft.Op)
+	}
+
+	if ft.Op != "" && ft.Func != nil {
+		v.errorf(path, "filter can't have both an operator and a function")
+	}
+}
+
+func (v *validator) math(path string, mt MathTree) {
+	switch {
+	case mt.Fn == "":
+		if len(mt.Child) > 0 {
+			v.errorf(path, "math operand can't have children")
+		}
+
+		if mt.Var == "" && mt.Const.Value == nil {
+			v.errorf(path, "math operand needs a variable or a constant")
+		}
+
+		if mt.Var != "" {
+			v.needs = append(v.needs, varRef{name: mt.Var, path: path})
+		}
+	case !isMathFunc(mt.Fn) && mt.Fn != "u-":
+		v.errorf(path, "unknown math function %s", mt.Fn)
+	default:
+		if n := mathArity(mt.Fn); len(mt.Child) != n {
+			v.errorf(path, "%s needs %d operands, got %d", mt.Fn, n, len(mt.Child))
+		}
+	}
+}
+
+func mathArity(fn string) int {
+	switch {
+	case isUnaryMath(fn):
+		return 1
+	case fn == "cond":
+		return 3
+	}
+	return 2
+}
+
+func (v *validator) facets(path string, gq GraphQuery) {
+	keys := map[string]bool{}
+	if gq.Facets != nil {
+		names := map[string]bool{}
+		for i, e := range gq.Facets.Param {
+			p := fmt.Sprintf("%s.facets.param[%d]", path, i)
+			if e.Key == "" {
+				v.errorf(p, "facet needs a key")
+			}
+
+			name := e.Key
+			if e.Alias != "" {
+				name = e.Alias
+			}
+
+			if names[name] {
+				v.errorf(p, "duplicate facet %s", name)
+			}
+			names[name] = true
+			keys[e.Key] = true
+		}
+	}
+
+	known := func(key string) bool {
+		return gq.Facets != nil && (gq.Facets.AllKeys || keys[key])
+	}
+
+	if gq.FacetOrder != "" && !known(gq.FacetOrder) {
+		v.errorf(path+".facetOrder", "facet %s is ordered by but not requested", gq.FacetOrder)
+	}
+
+	if gq.FacetDesc && gq.FacetOrder == "" {
+		v.errorf(path+".facetDesc", "facetDesc needs a facetOrder")
+	}
+
+	for _, k := range sortedKeys(gq.FacetVar) {
+		if !known(k) {
+			v.errorf(fmt.Sprintf("%s.facetVar[%s]", path, k), "facet %s is assigned to a variable but not requested", k)
+		}
+	}
+}
+
+// variables makes sure every variable is defined exactly once and used.
+func (v *validator) variables() {
+	defined := map[string]string{}
+	for _, e := range v.defines {
+		if prev, ok := defined[e.name]; ok {
+			v.errorf(e.path, "variable %s is already defined at %s", e.name, prev)
+			continue
+		}
+		defined[e.name] = e.path
+	}
+
+	used := map[string]bool{}
+	for _, e := range v.needs {
+		used[e.name] = true
+		if _, ok := defined[e.name]; !ok {
+			v.errorf(e.path, "variable %s is not defined", e.name)
+		}
+	}
+
+	for _, e := range v.defines {
+		if !used[e.name] {
+			v.errorf(e.path, "variable %s is defined but never used", e.name)
+		}
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
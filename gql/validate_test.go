@@ -0,0 +1,125 @@
+package gql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	me := func(children ...GraphQuery) GraphQuery {
+		return GraphQuery{Alias: "me", UID: []uint64{1}, Func: &Function{Name: "uid"}, Children: children}
+	}
+
+	table := []struct {
+		name     string
+		queries  []GraphQuery
+		expected ValidationErrors
+	}{
+		{
+			name:    "valid",
+			queries: []GraphQuery{me(GraphQuery{Attr: "name"})},
+		},
+		{
+			name:     "root without function",
+			queries:  []GraphQuery{{Alias: "me", Children: []GraphQuery{{Attr: "name"}}}},
+			expected: ValidationErrors{{Path: "queries[0]", Msg: "root block needs a function"}},
+		},
+		{
+			name: "undefined variable",
+			queries: []GraphQuery{
+				{Alias: "me", Func: &Function{Name: "uid", NeedsVar: []VarContext{{Name: "f", Typ: 1}}}, NeedsVar: []VarContext{{Name: "f", Typ: 1}}, Children: []GraphQuery{{Attr: "name"}}},
+			},
+			expected: ValidationErrors{
+				{Path: "queries[0].needsVar[0]", Msg: "variable f is not defined"},
+				{Path: "queries[0].func.needsVar[0]", Msg: "variable f is not defined"},
+			},
+		},
+		{
+			name:     "unused variable",
+			queries:  []GraphQuery{me(GraphQuery{Attr: "friend", Var: "f"})},
+			expected: ValidationErrors{{Path: "queries[0].children[0]", Msg: "variable f is defined but never used"}},
+		},
+		{
+			name: "variable in nested filter",
+			queries: []GraphQuery{me(
+				GraphQuery{Attr: "name"},
+				GraphQuery{Attr: "friend", Filter: &FilterTree{Op: "and", Child: []FilterTree{
+					{Func: &Function{Name: "has", Attr: "name"}},
+					{Func: &Function{Name: "uid", NeedsVar: []VarContext{{Name: "f", Typ: 1}}}},
+				}}},
+			)},
+			expected: ValidationErrors{{Path: "queries[0].children[1].filter.child[1].func.needsVar[0]", Msg: "variable f is not defined"}},
+		},
+		{
+			name: "filter operator arity",
+			queries: []GraphQuery{me(GraphQuery{Attr: "friend", Filter: &FilterTree{Op: "not", Child: []FilterTree{
+				{Func: &Function{Name: "has", Attr: "name"}},
+				{Func: &Function{Name: "has", Attr: "age"}},
+			}}})},
+			expected: ValidationErrors{{Path: "queries[0].children[0].filter", Msg: "not needs exactly one child, got 2"}},
+		},
+		{
+			name: "math arity",
+			queries: []GraphQuery{me(
+				GraphQuery{Attr: "age", Var: "a"},
+				GraphQuery{Attr: "math", MathExp: &MathTree{Fn: "+", Child: []MathTree{{Var: "a"}}}},
+				GraphQuery{Attr: "math", MathExp: &MathTree{Fn: "cond", Child: []MathTree{{Var: "a"}, {Var: "a"}}}},
+				GraphQuery{Attr: "math", MathExp: &MathTree{Fn: "sqrt", Child: []MathTree{{}}}},
+				GraphQuery{Attr: "math", MathExp: &MathTree{Fn: "tan", Child: []MathTree{{Var: "a"}}}},
+			)},
+			expected: ValidationErrors{
+				{Path: "queries[0].children[1].mathExp", Msg: "+ needs 2 operands, got 1"},
+				{Path: "queries[0].children[2].mathExp", Msg: "cond needs 3 operands, got 2"},
+				{Path: "queries[0].children[3].mathExp.child[0]", Msg: "math operand needs a variable or a constant"},
+				{Path: "queries[0].children[4].mathExp", Msg: "unknown math function tan"},
+			},
+		},
+		{
+			name: "facet order not requested",
+			queries: []GraphQuery{me(GraphQuery{
+				Attr:       "friend",
+				Facets:     &FacetParams{Param: []FacetParam{{Key: "since"}}},
+				FacetOrder: "weight",
+				Children:   []GraphQuery{{Attr: "name"}},
+			})},
+			expected: ValidationErrors{{Path: "queries[0].children[0].facetOrder", Msg: "facet weight is ordered by but not requested"}},
+		},
+		{
+			name: "facet var not requested",
+			queries: []GraphQuery{me(
+				GraphQuery{Attr: "friend", FacetVar: map[string]string{"weight": "w"}, Children: []GraphQuery{{Attr: "name"}}},
+				GraphQuery{Attr: "val", NeedsVar: []VarContext{{Name: "w", Typ: 2}}},
+			)},
+			expected: ValidationErrors{{Path: "queries[0].children[0].facetVar[weight]", Msg: "facet weight is assigned to a variable but not requested"}},
+		},
+		{
+			name: "checkpwd arguments",
+			queries: []GraphQuery{me(
+				GraphQuery{Attr: "password", Func: &Function{Name: "checkpwd", Attr: "password", Args: []Arg{{Value: "secret"}, {Value: "password"}}}},
+				GraphQuery{Attr: "password", Func: &Function{Name: "checkpwd", Attr: "password"}},
+				GraphQuery{Attr: "password", Func: &Function{Name: "checkpwd", Attr: "password", Args: []Arg{{Value: "a"}, {Value: "b"}}}},
+			)},
+			expected: ValidationErrors{
+				{Path: "queries[0].children[1].func", Msg: "checkpwd needs exactly one argument, got 0"},
+				{Path: "queries[0].children[2].func", Msg: "checkpwd needs exactly one argument, got 2"},
+			},
+		},
+		{
+			name:     "duplicate alias",
+			queries:  []GraphQuery{me(GraphQuery{Attr: "name"}), me(GraphQuery{Attr: "name"})},
+			expected: ValidationErrors{{Path: "queries[1]", Msg: "duplicate alias me, already used by queries[0]"}},
+		},
+		{
+			name:     "unknown cardinality",
+			queries:  []GraphQuery{me(GraphQuery{Attr: "spouse", Cardinality: "two", Children: []GraphQuery{{Attr: "name"}}})},
+			expected: ValidationErrors{{Path: "queries[0].children[0]", Msg: `unknown cardinality "two"`}},
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			require.Equal(t, e.expected, Validate(e.queries))
+		})
+	}
+}
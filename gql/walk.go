@@ -0,0 +1,335 @@
+package gql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SkipChildren is returned by a WalkFunc to skip the nodes below the
+// current one. It is never returned by Walk or Rewrite.
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is called for every node of a query tree. The node is one of
+// *GraphQuery, *FilterTree, *Function or *MathTree.
+type WalkFunc func(c *Cursor) error
+
+// Cursor is the position of a node in the query tree.
+type Cursor struct {
+	node   interface{}
+	parent *Cursor
+	field  string
+	index  int
+	set    func(interface{})
+
+	replaced bool
+}
+
+// Node returns the current node.
+func (c *Cursor) Node() interface{} {
+	return c.node
+}
+
+// Parent returns the cursor of the enclosing node, nil for root blocks.
+func (c *Cursor) Parent() *Cursor {
+	return c.parent
+}
+
+// Path returns the location of the node, e.g.
+// `queries[0].children[2].filter.child[1]`.
+func (c *Cursor) Path() string {
+	var parts []string
+	for e := c; e != nil; e = e.parent {
+		if e.index < 0 {
+			parts = append(parts, e.field)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s[%d]", e.field, e.index))
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, ".")
+}
+
+// Replace replaces the current node with n, which has to be of the same
+// type. Functions, filters and math trees referenced by a pointer can be
+// removed by passing a nil pointer. The nodes below n are visited
+// afterwards instead of the ones below the replaced node.
+func (c *Cursor) Replace(n interface{}) {
+	c.set(n)
+	c.replaced = true
+}
+
+// Walk calls fn for every node of queries in depth-first order, the
+// parent before its children. Changes made to the nodes or by Replace
+// are applied to queries in place. If fn returns SkipChildren the nodes
+// below are skipped, any other error stops the walk and is returned.
+func Walk(queries []GraphQuery, fn WalkFunc) error {
+	w := walker{fn: fn}
+	return w.queries(queries)
+}
+
+// Rewrite is like Walk, but leaves queries untouched and returns the
+// changed copy. Maps, argument lists and default values are shared
+// with queries and must not be changed in place.
+func Rewrite(queries []GraphQuery, fn WalkFunc) ([]GraphQuery, error) {
+	if queries == nil {
+		return nil, nil
+	}
+
+	res := make([]GraphQuery, len(queries))
+	copy(res, queries)
+
+	w := walker{fn: fn, copy: true}
+	if err := w.queries(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+type walker struct {
+	fn   WalkFunc
+	copy bool
+}
+
+func (w walker) visit(c *Cursor) (bool, error) {
+	err := w.fn(c)
+	if err == SkipChildren {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (w walker) queries(queries []GraphQuery) error {
+	for i := range queries {
+		if err := w.graphQuery(nil, "queries", queries, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w walker) graphQuery(parent *Cursor, field string, s []GraphQuery, i int) error {
+	c := &Cursor{node: &s[i], parent: parent, field: field, index: i}
+	c.set = func(n interface{}) { s[i] = *n.(*GraphQuery) }
+
+	if w.copy {
+		s[i] = copyGraphQuery(s[i])
+	}
+
+	ok, err := w.visit(c)
+	if !ok {
+		return err
+	}
+
+	if w.copy && c.replaced {
+		s[i] = copyGraphQuery(s[i])
+	}
+
+	gq := &s[i]
+
+	if gq.Func != nil {
+		if err := w.function(c, "func", &gq.Func); err != nil {
+			return err
+		}
+	}
+
+	if gq.Filter != nil {
+		if err := w.filterTree(c, "filter", &gq.Filter); err != nil {
+			return err
+		}
+	}
+
+	if gq.FacetsFilter != nil {
+		if err := w.filterTree(c, "facetsFilter", &gq.FacetsFilter); err != nil {
+			return err
+		}
+	}
+
+	if gq.MathExp != nil {
+		if err := w.mathTree(c, "mathExp", &gq.MathExp); err != nil {
+			return err
+		}
+	}
+
+	for j := range gq.Children {
+		if err := w.graphQuery(c, "children", gq.Children, j); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w walker) function(parent *Cursor, field string, fn **Function) error {
+	c := &Cursor{node: *fn, parent: parent, field: field, index: -1}
+	c.set = func(n interface{}) {
+		*fn = n.(*Function)
+		c.node = *fn
+	}
+
+	_, err := w.visit(c)
+	return err
+}
+
+func (w walker) filterTree(parent *Cursor, field string, ft **FilterTree) error {
+	c := &Cursor{node: *ft, parent: parent, field: field, index: -1}
+	c.set = func(n interface{}) {
+		*ft = n.(*FilterTree)
+		c.node = *ft
+	}
+
+	if w.copy {
+		**ft = copyFilterTreeChildren(**ft)
+	}
+
+	ok, err := w.visit(c)
+	if !ok || *ft == nil {
+		return err
+	}
+
+	if w.copy && c.replaced {
+		*ft = copyFilterTree(*ft)
+		**ft = copyFilterTreeChildren(**ft)
+	}
+	return w.filterTreeChildren(c, *ft)
+}
+
+func (w walker) filterTreeElem(parent *Cursor, s []FilterTree, i int) error {
+	c := &Cursor{node: &s[i], parent: parent, field: "child", index: i}
+	c.set = func(n interface{}) { s[i] = *n.(*FilterTree) }
+
+	if w.copy {
+		s[i] = copyFilterTreeChildren(s[i])
+	}
+
+	ok, err := w.visit(c)
+	if !ok {
+		return err
+	}
+
+	if w.copy && c.replaced {
+		s[i] = copyFilterTreeChildren(s[i])
+	}
+	return w.filterTreeChildren(c, &s[i])
+}
+
+func (w walker) filterTreeChildren(c *Cursor, ft *FilterTree) error {
+	if ft.Func != nil {
+		if err := w.function(c, "func", &ft.Func); err != nil {
+			return err
+		}
+	}
+
+	for i := range ft.Child {
+		if err := w.filterTreeElem(c, ft.Child, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w walker) mathTree(parent *Cursor, field string, mt **MathTree) error {
+	c := &Cursor{node: *mt, parent: parent, field: field, index: -1}
+	c.set = func(n interface{}) {
+		*mt = n.(*MathTree)
+		c.node = *mt
+	}
+
+	if w.copy {
+		**mt = copyMathTreeChildren(**mt)
+	}
+
+	ok, err := w.visit(c)
+	if !ok || *mt == nil {
+		return err
+	}
+
+	if w.copy && c.replaced {
+		*mt = copyMathTree(*mt)
+		**mt = copyMathTreeChildren(**mt)
+	}
+	return w.mathTreeChildren(c, *mt)
+}
+
+func (w walker) mathTreeElem(parent *Cursor, s []MathTree, i int) error {
+	c := &Cursor{node: &s[i], parent: parent, field: "child", index: i}
+	c.set = func(n interface{}) { s[i] = *n.(*MathTree) }
+
+	if w.copy {
+		s[i] = copyMathTreeChildren(s[i])
+	}
+
+	ok, err := w.visit(c)
+	if !ok {
+		return err
+	}
+
+	if w.copy && c.replaced {
+		s[i] = copyMathTreeChildren(s[i])
+	}
+	return w.mathTreeChildren(c, &s[i])
+}
+
+func (w walker) mathTreeChildren(c *Cursor, mt *MathTree) error {
+	for i := range mt.Child {
+		if err := w.mathTreeElem(c, mt.Child, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyGraphQuery copies the nodes directly referenced by gq, so they
+// can be changed without affecting the original query.
+func copyGraphQuery(gq GraphQuery) GraphQuery {
+	gq.Func = copyFunction(gq.Func)
+	gq.Filter = copyFilterTree(gq.Filter)
+	gq.FacetsFilter = copyFilterTree(gq.FacetsFilter)
+	gq.MathExp = copyMathTree(gq.MathExp)
+	if gq.Children != nil {
+		gq.Children = append([]GraphQuery{}, gq.Children...)
+	}
+	return gq
+}
+
+func copyFilterTreeChildren(ft FilterTree) FilterTree {
+	ft.Func = copyFunction(ft.Func)
+	if ft.Child != nil {
+		ft.Child = append([]FilterTree{}, ft.Child...)
+	}
+	return ft
+}
+
+func copyMathTreeChildren(mt MathTree) MathTree {
+	if mt.Child != nil {
+		mt.Child = append([]MathTree{}, mt.Child...)
+	}
+	return mt
+}
+
+func copyFunction(fn *Function) *Function {
+	if fn == nil {
+		return nil
+	}
+	res := *fn
+	return &res
+}
+
+func copyFilterTree(ft *FilterTree) *FilterTree {
+	if ft == nil {
+		return nil
+	}
+	res := *ft
+	return &res
+}
+
+func copyMathTree(mt *MathTree) *MathTree {
+	if mt == nil {
+		return nil
+	}
+	res := *mt
+	return &res
+}
@@ -0,0 +1,144 @@
+package gql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func walkQuery() []GraphQuery {
+	return []GraphQuery{
+		{
+			Alias: "me",
+			UID:   []uint64{1},
+			Func:  &Function{Name: "uid"},
+			Children: []GraphQuery{
+				{Attr: "age", Var: "a"},
+				{
+					Attr: "friend",
+					Filter: &FilterTree{Op: "and", Child: []FilterTree{
+						{Func: &Function{Name: "has", Attr: "name"}},
+						{Op: "not", Child: []FilterTree{{Func: &Function{Name: "eq", Attr: "name", Args: []Arg{{Value: "Pat"}}}}}},
+					}},
+					Children: []GraphQuery{{Attr: "name"}},
+				},
+				{Attr: "math", Alias: "double", MathExp: &MathTree{Fn: "*", Child: []MathTree{{Var: "a"}, {Const: Val{Tid: floatID, Value: 2.0}}}}},
+			},
+		},
+	}
+}
+
+func TestWalk(t *testing.T) {
+	paths := []string{}
+	err := Walk(walkQuery(), func(c *Cursor) error {
+		paths = append(paths, c.Path())
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"queries[0]",
+		"queries[0].func",
+		"queries[0].children[0]",
+		"queries[0].children[1]",
+		"queries[0].children[1].filter",
+		"queries[0].children[1].filter.child[0]",
+		"queries[0].children[1].filter.child[0].func",
+		"queries[0].children[1].filter.child[1]",
+		"queries[0].children[1].filter.child[1].child[0]",
+		"queries[0].children[1].filter.child[1].child[0].func",
+		"queries[0].children[1].children[0]",
+		"queries[0].children[2]",
+		"queries[0].children[2].mathExp",
+		"queries[0].children[2].mathExp.child[0]",
+		"queries[0].children[2].mathExp.child[1]",
+	}, paths)
+}
+
+func TestWalkSkipChildren(t *testing.T) {
+	paths := []string{}
+	err := Walk(walkQuery(), func(c *Cursor) error {
+		paths = append(paths, c.Path())
+		if _, ok := c.Node().(*FilterTree); ok {
+			return SkipChildren
+		}
+
+		if gq, ok := c.Node().(*GraphQuery); ok && gq.Attr == "math" {
+			return SkipChildren
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"queries[0]",
+		"queries[0].func",
+		"queries[0].children[0]",
+		"queries[0].children[1]",
+		"queries[0].children[1].filter",
+		"queries[0].children[1].children[0]",
+		"queries[0].children[2]",
+	}, paths)
+}
+
+func TestWalkInPlace(t *testing.T) {
+	queries := walkQuery()
+	err := Walk(queries, func(c *Cursor) error {
+		if ft, ok := c.Node().(*FilterTree); ok && ft.Op == "not" {
+			c.Replace(&FilterTree{Func: &Function{Name: "has", Attr: "alias"}})
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, FilterTree{Func: &Function{Name: "has", Attr: "alias"}}, queries[0].Children[1].Filter.Child[1])
+}
+
+func TestRewrite(t *testing.T) {
+	queries := walkQuery()
+	res, err := Rewrite(queries, func(c *Cursor) error {
+		switch n := c.Node().(type) {
+		case *GraphQuery:
+			n.Alias = ""
+		case *Function:
+			if n.Name == "eq" {
+				n.Args = []Arg{{Value: "Sam"}}
+			}
+		case *FilterTree:
+			if c.Parent().Node().(*GraphQuery).Attr == "friend" {
+				c.Replace((*FilterTree)(nil))
+			}
+		case *MathTree:
+			if n.Var == "a" {
+				c.Replace(&MathTree{Var: "b"})
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, walkQuery(), queries)
+
+	expected := walkQuery()
+	expected[0].Alias = ""
+	expected[0].Children[1].Filter = nil
+	expected[0].Children[2].Alias = ""
+	expected[0].Children[2].MathExp.Child[0] = MathTree{Var: "b"}
+	require.Equal(t, expected, res)
+}
+
+func TestWalkError(t *testing.T) {
+	visited := 0
+	err := Walk(walkQuery(), func(c *Cursor) error {
+		visited++
+		if _, ok := c.Node().(*Function); ok {
+			return SkipChildren
+		}
+
+		if _, ok := c.Node().(*MathTree); ok {
+			return errTest
+		}
+		return nil
+	})
+	require.Equal(t, errTest, err)
+	require.Equal(t, 13, visited)
+}
+
+var errTest = errors.New("test")
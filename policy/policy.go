@@ -0,0 +1,121 @@
+// Package policy decides which parts of the query language an identity
+// may use, based on declarative rules.
+package policy
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"mooncamp.com/dgraphtools"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Policy is a list of rules. A query is allowed if every predicate and
+// function it uses is allowed by a rule matching the identity and not
+// denied by one.
+type Policy struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Rule allows or denies the listed predicates and functions to the
+// listed identities, subjects and roles, or to everyone if all are
+// empty. Identities are matched by their uid, subjects by the subject
+// of external identities. "*" matches any name.
+type Rule struct {
+	Name       string   `yaml:"name" json:"name"`
+	Effect     Effect   `yaml:"effect,omitempty" json:"effect,omitempty"`
+	Identities []uint64 `yaml:"identities,omitempty" json:"identities,omitempty"`
+	Subjects   []string `yaml:"subjects,omitempty" json:"subjects,omitempty"`
+	Roles      []string `yaml:"roles,omitempty" json:"roles,omitempty"`
+
+	Predicates []string `yaml:"predicates,omitempty" json:"predicates,omitempty"`
+	// ReverseEdges are the predicates that may be followed backwards,
+	// e.g. `~friends`.
+	ReverseEdges    []string `yaml:"reverseEdges,omitempty" json:"reverseEdges,omitempty"`
+	RootFunctions   []string `yaml:"rootFunctions,omitempty" json:"rootFunctions,omitempty"`
+	FilterFunctions []string `yaml:"filterFunctions,omitempty" json:"filterFunctions,omitempty"`
+}
+
+// Load reads a policy in YAML or JSON.
+func Load(r io.Reader) (Policy, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	var p Policy
+	if err := yaml.UnmarshalStrict(data, &p); err != nil {
+		return Policy{}, err
+	}
+
+	for i, e := range p.Rules {
+		switch e.Effect {
+		case "":
+			p.Rules[i].Effect = Allow
+		case Allow, Deny:
+		default:
+			return Policy{}, fmt.Errorf("rule %s: unknown effect %s", e.Name, e.Effect)
+		}
+	}
+
+	return p, nil
+}
+
+// names returns the names rule lists for kind.
+func (r Rule) names(k kind) []string {
+	switch k {
+	case predicate:
+		return r.Predicates
+	case reverseEdge:
+		return r.ReverseEdges
+	case rootFunction:
+		return r.RootFunctions
+	default:
+		return r.FilterFunctions
+	}
+}
+
+func (r Rule) matches(identity dgraphtools.Identity, roles []string) bool {
+	if len(r.Identities) == 0 && len(r.Subjects) == 0 && len(r.Roles) == 0 {
+		return true
+	}
+
+	for _, e := range r.Identities {
+		if e == identity.UID {
+			return true
+		}
+	}
+
+	for _, e := range r.Subjects {
+		if identity.Subject != "" && e == identity.Subject {
+			return true
+		}
+	}
+
+	for _, e := range r.Roles {
+		for _, role := range roles {
+			if e == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r Rule) covers(u use) bool {
+	for _, e := range r.names(u.kind) {
+		if e == "*" || e == u.name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package policy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+rules:
+- name: public
+  predicates: [name, friends, age]
+  reverseEdges: [friends]
+  rootFunctions: [uid, eq]
+  filterFunctions: [eq, ge, has]
+- name: admins
+  roles: [admin]
+  predicates: ["*"]
+  rootFunctions: ["*"]
+- name: partners
+  subjects: [partner]
+  predicates: [email]
+- name: no-email
+  effect: deny
+  identities: [7]
+  predicates: [email]
+`
+
+func TestVerifier(t *testing.T) {
+	p, err := Load(strings.NewReader(testPolicy))
+	require.NoError(t, err)
+
+	v := &Verifier{
+		Policy: p,
+		Roles: func(ctx context.Context, identity dgraphtools.Identity) ([]string, error) {
+			if identity.UID == 1 || identity.UID == 7 {
+				return []string{"admin"}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		identity uint64
+		subject  string
+		denied   *Denied
+	}{
+		{
+			name:     "allowed",
+			query:    `{ q(func: uid(0x1)) @filter(ge(age, 18)) { name friends (orderasc: age) { name } ~friends { name } } }`,
+			identity: 2,
+		},
+		{
+			name:     "predicate",
+			query:    `{ q(func: uid(0x1)) { name email } }`,
+			identity: 2,
+			denied:   &Denied{Kind: "predicate", Name: "email", Path: "queries[0].children[1]"},
+		},
+		{
+			name:     "predicate in filter",
+			query:    `{ q(func: uid(0x1)) { friends @filter(eq(email, "a@b.c")) { name } } }`,
+			identity: 2,
+			denied:   &Denied{Kind: "predicate", Name: "email", Path: "queries[0].children[0].filter.func"},
+		},
+		{
+			name:     "filter function",
+			query:    `{ q(func: uid(0x1)) @filter(anyofterms(name, "harry")) { name } }`,
+			identity: 2,
+			denied:   &Denied{Kind: "filter function", Name: "anyofterms", Path: "queries[0].filter.func"},
+		},
+		{
+			name:     "root function",
+			query:    `{ q(func: has(name)) { name } }`,
+			identity: 2,
+			denied:   &Denied{Kind: "root function", Name: "has", Path: "queries[0].func"},
+		},
+		{
+			name:     "reverse edge",
+			query:    `{ q(func: uid(0x1)) { ~owner { name } } }`,
+			identity: 2,
+			denied:   &Denied{Kind: "reverse edge", Name: "owner", Path: "queries[0].children[0]"},
+		},
+		{
+			name:     "order",
+			query:    `{ q(func: uid(0x1), orderasc: email) { name } }`,
+			identity: 2,
+			denied:   &Denied{Kind: "predicate", Name: "email", Path: "queries[0].order[0]"},
+		},
+		{
+			name:     "expand",
+			query:    `{ q(func: uid(0x1)) { expand(_all_) } }`,
+			identity: 2,
+			denied:   &Denied{Kind: "predicate", Name: "*", Path: "queries[0].children[0]"},
+		},
+		{
+			name:     "role",
+			query:    `{ q(func: has(email)) { email expand(_all_) } }`,
+			identity: 1,
+		},
+		{
+			name:     "subject",
+			query:    `{ q(func: uid(0x1)) { name email } }`,
+			identity: 2,
+			subject:  "partner",
+		},
+		{
+			name:     "deny rule",
+			query:    `{ q(func: uid(0x1)) { name email } }`,
+			identity: 7,
+			denied:   &Denied{Rule: "no-email", Kind: "predicate", Name: "email", Path: "queries[0].children[1]"},
+		},
+		{
+			name:     "deny rule with expand",
+			query:    `{ q(func: uid(0x1)) { expand(_all_) } }`,
+			identity: 7,
+			denied:   &Denied{Rule: "no-email", Kind: "predicate", Name: "*", Path: "queries[0].children[0]"},
+		},
+	}
+
+	for _, e := range tests {
+		t.Run(e.name, func(t *testing.T) {
+			queries, err := gql.Parse(gql.Request{Str: e.query})
+			require.NoError(t, err)
+
+			ok, err := v.QueryAllowed(context.Background(), queries, dgraphtools.Identity{UID: e.identity, Subject: e.subject}, nil)
+			if e.denied == nil {
+				require.NoError(t, err)
+				require.True(t, ok)
+				return
+			}
+
+			require.False(t, ok)
+			require.Equal(t, *e.denied, err)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	js := `{"rules": [{"name": "public", "predicates": ["name"]}, {"name": "secret", "effect": "deny", "roles": ["guest"], "predicates": ["password"]}]}`
+
+	expected := Policy{Rules: []Rule{
+		{Name: "public", Effect: Allow, Predicates: []string{"name"}},
+		{Name: "secret", Effect: Deny, Roles: []string{"guest"}, Predicates: []string{"password"}},
+	}}
+
+	p, err := Load(strings.NewReader(js))
+	require.NoError(t, err)
+	require.Equal(t, expected, p)
+
+	_, err = Load(strings.NewReader(`{"rules": [{"name": "public", "effect": "maybe"}]}`))
+	require.EqualError(t, err, "rule public: unknown effect maybe")
+
+	_, err = Load(strings.NewReader(`{"rules": [{"name": "public", "predicate": ["name"]}]}`))
+	require.Error(t, err)
+}
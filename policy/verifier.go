@@ -0,0 +1,187 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+)
+
+type kind int
+
+const (
+	predicate kind = iota
+	reverseEdge
+	rootFunction
+	filterFunction
+)
+
+func (k kind) String() string {
+	switch k {
+	case predicate:
+		return "predicate"
+	case reverseEdge:
+		return "reverse edge"
+	case rootFunction:
+		return "root function"
+	default:
+		return "filter function"
+	}
+}
+
+// use is a predicate or function used by the query at path.
+type use struct {
+	kind kind
+	name string
+	path string
+}
+
+// Denied is returned if the policy doesn't allow a query. Rule is the
+// name of the denying rule, empty if no rule allowed the use.
+type Denied struct {
+	Rule string
+	Kind string
+	Name string
+	Path string
+}
+
+func (e Denied) Error() string {
+	if e.Rule == "" {
+		return fmt.Sprintf("unauthorized action: no rule allows %s %s at %s", e.Kind, e.Name, e.Path)
+	}
+	return fmt.Sprintf("unauthorized action: %s %s at %s denied by rule %s", e.Kind, e.Name, e.Path, e.Rule)
+}
+
+// Unwrap makes Denied a dgraphtools.Unauthorized for errors.As.
+func (e Denied) Unwrap() error {
+	return dgraphtools.Unauthorized{}
+}
+
+// Verifier checks queries against a policy. It implements
+// dgraphtools.QueryVerifier.
+type Verifier struct {
+	Policy Policy
+	// Roles returns the roles of an identity. Without it only rules for
+	// identities and everyone apply.
+	Roles func(ctx context.Context, identity dgraphtools.Identity) ([]string, error)
+}
+
+// QueryAllowed returns false and a Denied error naming the rule if the
+// policy doesn't allow queries. Proofs aren't considered.
+func (v *Verifier) QueryAllowed(ctx context.Context, queries []gql.GraphQuery, identity dgraphtools.Identity, proofs map[uint64]gql.GraphQuery) (bool, error) {
+	if err := v.Check(ctx, queries, identity); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Check returns a Denied error if the policy doesn't allow queries.
+func (v *Verifier) Check(ctx context.Context, queries []gql.GraphQuery, identity dgraphtools.Identity) error {
+	var roles []string
+	if v.Roles != nil {
+		var err error
+		if roles, err = v.Roles(ctx, identity); err != nil {
+			return err
+		}
+	}
+
+	var rules []Rule
+	for _, e := range v.Policy.Rules {
+		if e.matches(identity, roles) {
+			rules = append(rules, e)
+		}
+	}
+
+	for _, u := range uses(queries) {
+		if err := check(rules, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func check(rules []Rule, u use) error {
+	allowed := false
+	for _, e := range rules {
+		// Expanding may return any predicate a deny rule names.
+		denies := e.Effect == Deny && u.name == "*" && len(e.names(u.kind)) > 0
+		if !e.covers(u) && !denies {
+			continue
+		}
+
+		if e.Effect == Deny {
+			return Denied{Rule: e.Name, Kind: u.kind.String(), Name: u.name, Path: u.path}
+		}
+		allowed = true
+	}
+
+	if !allowed {
+		return Denied{Kind: u.kind.String(), Name: u.name, Path: u.path}
+	}
+	return nil
+}
+
+// uses returns the predicates and functions of the whole query tree.
+func uses(queries []gql.GraphQuery) []use {
+	var res []use
+	add := func(k kind, name, path string) {
+		res = append(res, use{kind: k, name: name, path: path})
+	}
+
+	addAttr := func(attr, path string) {
+		switch {
+		case attr == "" || attr == "uid" || attr == "val" || strings.HasPrefix(attr, "val("):
+		case strings.HasPrefix(attr, "~"):
+			add(reverseEdge, attr[1:], path)
+		default:
+			add(predicate, attr, path)
+		}
+	}
+
+	_ = gql.Walk(queries, func(c *gql.Cursor) error {
+		switch n := c.Node().(type) {
+		case *gql.GraphQuery:
+			path := c.Path()
+			switch {
+			case n.Expand != "":
+				// Expanding may return any predicate.
+				add(predicate, "*", path)
+			case n.MathExp == nil:
+				addAttr(n.Attr, path)
+			}
+
+			for i, e := range n.Order {
+				addAttr(e.Attr, fmt.Sprintf("%s.order[%d]", path, i))
+			}
+
+			for i, e := range n.GroupbyAttrs {
+				addAttr(e.Attr, fmt.Sprintf("%s.groupbyAttrs[%d]", path, i))
+			}
+		case *gql.Function:
+			switch parent := c.Parent(); parent.Node().(type) {
+			case *gql.GraphQuery:
+				// Functions of child blocks are aggregations and
+				// checkpwd, their predicate is the one of the block.
+				if parent.Parent() != nil {
+					return nil
+				}
+				add(rootFunction, n.Name, c.Path())
+			case *gql.FilterTree:
+				add(filterFunction, n.Name, c.Path())
+				if strings.Contains(c.Path(), "facetsFilter") {
+					// The attribute of a facet filter is a facet.
+					return nil
+				}
+			}
+
+			if !n.IsValueVar {
+				addAttr(n.Attr, c.Path())
+			}
+		}
+		return nil
+	})
+
+	return res
+}
@@ -0,0 +1,175 @@
+package proof
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+)
+
+// Cache is a QueryVerifier remembering the results of proof queries for
+// a while, as the connection between an identity and a uid rarely
+// changes. Results are keyed by identity, uid and the hash of the proof
+// queries. Failed proof queries aren't cached.
+type Cache struct {
+	proof *Proof
+	ttl   time.Duration
+	size  int
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	// lru holds the entries, the most recently used first.
+	lru *list.List
+}
+
+type cacheKey struct {
+	identity uint64
+	uid      uint64
+	hash     string
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	allowed bool
+	expires time.Time
+}
+
+// NewCache caches the results of p for ttl. At most size results are
+// kept, the least recently used are dropped first. A size of 0 doesn't
+// limit the cache.
+func NewCache(p *Proof, ttl time.Duration, size int) *Cache {
+	return &Cache{
+		proof:   p,
+		ttl:     ttl,
+		size:    size,
+		now:     time.Now,
+		entries: map[cacheKey]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+func (c *Cache) QueryAllowed(ctx context.Context, queries []gql.GraphQuery, identity dgraphtools.Identity, proofs map[uint64]gql.GraphQuery) (bool, error) {
+	return c.proof.queryAllowed(ctx, queries, identity.UID, proofs, c.paths)
+}
+
+// Invalidate drops the results of every identity and uid in uids, e.g.
+// after a mutation changed the edges between them. Results are only
+// keyed by their ends, the nodes in between aren't known. Call Purge
+// after mutations of edges on the way, e.g. removing a user from a
+// company, which cuts the paths to every node of the company.
+func (c *Cache) Invalidate(uids ...uint64) {
+	drop := make(map[uint64]bool, len(uids))
+	for _, e := range uids {
+		drop[e] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, e := range c.entries {
+		if drop[k.identity] || drop[k.uid] {
+			c.remove(e)
+		}
+	}
+}
+
+// Purge drops every result.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[cacheKey]*list.Element{}
+	c.lru.Init()
+}
+
+// Len returns the number of cached results, including expired ones not
+// dropped yet.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// paths checks the proofs without cached results in one go.
+func (c *Cache) paths(ctx context.Context, identity uint64, proofs map[uint64][]gql.GraphQuery) (map[uint64]bool, error) {
+	res := make(map[uint64]bool, len(proofs))
+	missing := map[uint64][]gql.GraphQuery{}
+	keys := map[uint64]cacheKey{}
+	for uid, e := range proofs {
+		hash, err := gql.Hash(e...)
+		if err != nil {
+			return nil, err
+		}
+
+		key := cacheKey{identity: identity, uid: uid, hash: hash}
+		if allowed, ok := c.get(key); ok {
+			res[uid] = allowed
+			continue
+		}
+
+		missing[uid] = e
+		keys[uid] = key
+	}
+
+	if len(missing) == 0 {
+		return res, nil
+	}
+
+	found, err := c.proof.paths(ctx, identity, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for uid, allowed := range found {
+		c.put(keys[uid], allowed)
+		res[uid] = allowed
+	}
+
+	return res, nil
+}
+
+func (c *Cache) get(key cacheKey) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return false, false
+	}
+
+	entry := e.Value.(*cacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.remove(e)
+		return false, false
+	}
+
+	c.lru.MoveToFront(e)
+	return entry.allowed, true
+}
+
+func (c *Cache) put(key cacheKey, allowed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, allowed: allowed, expires: c.now().Add(c.ttl)}
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.lru.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.size > 0 && c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) remove(e *list.Element) {
+	c.lru.Remove(e)
+	delete(c.entries, e.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,110 @@
+package proof
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/stretchr/testify/require"
+)
+
+// countingHandler proves every uid to be connected and counts the proof
+// queries.
+type countingHandler struct {
+	queries int
+	err     error
+}
+
+func (h *countingHandler) Query(ctx context.Context, q string, vars map[string]string) (*api.Response, error) {
+	h.queries++
+	if h.err != nil {
+		return nil, h.err
+	}
+	return &api.Response{Json: []byte(`{"proof_5_0": [{"proof": "0x5"}]}`)}, nil
+}
+
+func TestCache(t *testing.T) {
+	queries := []gql.GraphQuery{{Alias: "q", UID: []uint64{5}, Func: &gql.Function{Name: "uid"}, Children: []gql.GraphQuery{{Attr: "name"}}}}
+	proofQuery := gql.GraphQuery{
+		Alias:    "proof",
+		UID:      []uint64{1},
+		Func:     &gql.Function{Name: "uid"},
+		Children: []gql.GraphQuery{{Attr: "uid", Alias: "proof"}},
+	}
+
+	otherProofQuery := proofQuery
+	otherProofQuery.Children = []gql.GraphQuery{{Attr: "company", Alias: "proof", Children: proofQuery.Children}}
+
+	setup := func(size int) (*Cache, *countingHandler, *time.Time) {
+		h := &countingHandler{}
+		c := NewCache(&Proof{QueryHandler: h}, time.Minute, size)
+		now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+		c.now = func() time.Time { return now }
+		return c, h, &now
+	}
+
+	allowed := func(t *testing.T, c *Cache, identity uint64, proofQuery gql.GraphQuery) {
+		ok, err := c.QueryAllowed(context.Background(), queries, dgraphtools.Identity{UID: identity}, map[uint64]gql.GraphQuery{5: proofQuery})
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	t.Run("hit", func(t *testing.T) {
+		c, h, _ := setup(0)
+		allowed(t, c, 1, proofQuery)
+		allowed(t, c, 1, proofQuery)
+		require.Equal(t, 1, h.queries)
+
+		allowed(t, c, 1, otherProofQuery)
+		require.Equal(t, 2, h.queries)
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		c, h, now := setup(0)
+		allowed(t, c, 1, proofQuery)
+		*now = now.Add(time.Minute)
+		allowed(t, c, 1, proofQuery)
+		require.Equal(t, 2, h.queries)
+	})
+
+	t.Run("size", func(t *testing.T) {
+		c, h, _ := setup(1)
+		allowed(t, c, 1, proofQuery)
+		allowed(t, c, 1, otherProofQuery)
+		require.Equal(t, 1, c.Len())
+
+		allowed(t, c, 1, proofQuery)
+		require.Equal(t, 3, h.queries)
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		c, h, _ := setup(0)
+		allowed(t, c, 1, proofQuery)
+		c.Invalidate(7)
+		allowed(t, c, 1, proofQuery)
+		require.Equal(t, 1, h.queries)
+
+		c.Invalidate(5)
+		allowed(t, c, 1, proofQuery)
+		require.Equal(t, 2, h.queries)
+
+		c.Purge()
+		require.Equal(t, 0, c.Len())
+	})
+
+	t.Run("errors aren't cached", func(t *testing.T) {
+		c, h, _ := setup(0)
+		h.err = errors.New("unavailable")
+		_, err := c.QueryAllowed(context.Background(), queries, dgraphtools.Identity{UID: 1}, map[uint64]gql.GraphQuery{5: proofQuery})
+		require.EqualError(t, err, "dgraph failed: unavailable")
+
+		h.err = nil
+		allowed(t, c, 1, proofQuery)
+		require.Equal(t, 2, h.queries)
+	})
+}
@@ -0,0 +1,44 @@
+package proof
+
+import "mooncamp.com/dgraphtools/gql"
+
+// Path lists the edges leading from an identity to the nodes it may
+// access, e.g. `user.company` followed by `~company.project`. Reverse
+// edges are prefixed by ~.
+type Path []string
+
+// derive returns a proof query for every path of p leading from
+// identity to uid.
+func (p *Proof) derive(uid, identity uint64) []gql.GraphQuery {
+	var res []gql.GraphQuery
+	for _, e := range p.Paths {
+		if len(e) == 0 {
+			continue
+		}
+		res = append(res, pathQuery(e, uid, identity))
+	}
+	return res
+}
+
+// pathQuery follows path from identity and keeps the nodes at its end
+// with the given uid. Every block is aliased proof, as followProof
+// expects.
+func pathQuery(path Path, uid, identity uint64) gql.GraphQuery {
+	block := gql.GraphQuery{
+		Attr:     path[len(path)-1],
+		Alias:    "proof",
+		Filter:   &gql.FilterTree{Func: &gql.Function{Name: "uid", UID: []uint64{uid}}},
+		Children: []gql.GraphQuery{{Attr: "uid", Alias: "proof"}},
+	}
+
+	for i := len(path) - 2; i >= 0; i-- {
+		block = gql.GraphQuery{Attr: path[i], Alias: "proof", Children: []gql.GraphQuery{block}}
+	}
+
+	return gql.GraphQuery{
+		Alias:    "proof",
+		UID:      []uint64{identity},
+		Func:     &gql.Function{Name: "uid"},
+		Children: []gql.GraphQuery{block},
+	}
+}
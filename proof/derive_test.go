@@ -0,0 +1,87 @@
+package proof
+
+import (
+	"context"
+	"testing"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+	"mooncamp.com/dgraphtools/render"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler answers every query with resp and keeps the last
+// query.
+type recordingHandler struct {
+	resp  string
+	query string
+}
+
+func (h *recordingHandler) Query(ctx context.Context, q string, vars map[string]string) (*api.Response, error) {
+	h.query = q
+	return &api.Response{Json: []byte(h.resp)}, nil
+}
+
+func TestPathQuery(t *testing.T) {
+	expected := gql.GraphQuery{
+		Alias: "proof",
+		UID:   []uint64{1},
+		Func:  &gql.Function{Name: "uid"},
+		Children: []gql.GraphQuery{
+			{
+				Attr:  "user.company",
+				Alias: "proof",
+				Children: []gql.GraphQuery{
+					{
+						Attr:     "~company.project",
+						Alias:    "proof",
+						Filter:   &gql.FilterTree{Func: &gql.Function{Name: "uid", UID: []uint64{5}}},
+						Children: []gql.GraphQuery{{Attr: "uid", Alias: "proof"}},
+					},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, expected, pathQuery(Path{"user.company", "~company.project"}, 5, 1))
+}
+
+func TestDerivedProofs(t *testing.T) {
+	queries, err := gql.Parse(gql.Request{Str: `{ q(func: uid(0x5)) { name } }`})
+	require.NoError(t, err)
+
+	paths := []Path{{"user.company"}, {"user.company", "~company.project"}}
+
+	cases := []struct {
+		name    string
+		resp    string
+		allowed bool
+	}{
+		{name: "second path", resp: `{"proof_5_0": [], "proof_5_1": [{"proof": [{"proof": [{"proof": "0x5"}]}]}]}`, allowed: true},
+		{name: "no path", resp: `{"proof_5_0": [], "proof_5_1": []}`},
+	}
+
+	for _, e := range cases {
+		t.Run(e.name, func(t *testing.T) {
+			h := &recordingHandler{resp: e.resp}
+			proof := &Proof{QueryHandler: h, Paths: paths}
+
+			ok, err := proof.QueryAllowed(context.Background(), queries, dgraphtools.Identity{UID: 1}, nil)
+			require.NoError(t, err)
+			require.Equal(t, e.allowed, ok)
+
+			var derived []gql.GraphQuery
+			for i, path := range paths {
+				q := pathQuery(path, 5, 1)
+				q.Alias = proofAlias(5, i)
+				derived = append(derived, q)
+			}
+
+			expected, err := render.Render(render.Query{Queries: derived})
+			require.NoError(t, err)
+			require.Equal(t, expected, h.query)
+		})
+	}
+}
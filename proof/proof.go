@@ -3,8 +3,12 @@ package proof
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 
 	"mooncamp.com/dgraphtools"
 	"mooncamp.com/dgraphtools/gql"
@@ -15,101 +19,357 @@ import (
 
 type Proof struct {
 	dgraphtools.QueryHandler
+	// BatchSize limits the number of proofs checked by one query. The
+	// batches are queried in parallel. With 0 all proofs of a request
+	// are checked by a single query.
+	BatchSize int
+	// Paths derives the proofs of uids the client didn't send a proof
+	// for. A uid is connected to the identity if one of the paths leads
+	// to it.
+	Paths []Path
+	// RenderOptions are used to render the proof queries, e.g. the
+	// version of Dgraph.
+	RenderOptions []render.Option
 }
 
-func (p *Proof) QueryAllowed(ctx context.Context, queries []gql.GraphQuery, identity int, proofs map[int]gql.GraphQuery) (bool, error) {
-	for _, e := range queries {
-		if e.Func == nil {
+// QueryAllowed allows queries if every uid they select nodes by is the
+// identity or connected to it by a proof. Besides root functions this
+// covers uid literals in filters and uid_in, the ends of shortest paths
+// and GraphQL variables, which can't be proven and are denied. Nodes
+// reached by following edges, expand, @recurse or the variables of
+// other blocks are connected to these uids anyway.
+func (p *Proof) QueryAllowed(ctx context.Context, queries []gql.GraphQuery, identity dgraphtools.Identity, proofs map[uint64]gql.GraphQuery) (bool, error) {
+	return p.queryAllowed(ctx, queries, identity.UID, proofs, p.paths)
+}
+
+// pathsFunc reports for every uid of proofs whether one of its proof
+// queries connects it to the identity.
+type pathsFunc func(ctx context.Context, identity uint64, proofs map[uint64][]gql.GraphQuery) (map[uint64]bool, error)
+
+func (p *Proof) queryAllowed(ctx context.Context, queries []gql.GraphQuery, identity uint64, proofs map[uint64]gql.GraphQuery, paths pathsFunc) (bool, error) {
+	// Identities not resolved to a node have no access.
+	if identity == 0 {
+		return false, nil
+	}
+
+	uids, ok := selectedUIDs(queries)
+	if !ok {
+		return false, nil
+	}
+
+	needed := map[uint64][]gql.GraphQuery{}
+	for _, uid := range uids {
+		if uid == identity {
+			continue
+		}
+
+		proofQuery, ok := proofs[uid]
+		switch {
+		case ok:
+			needed[uid] = []gql.GraphQuery{proofQuery}
+		case len(p.Paths) > 0:
+			needed[uid] = p.derive(uid, identity)
+		default:
 			return false, nil
 		}
+	}
 
-		if e.Func.Name != "uid" {
+	if len(needed) == 0 {
+		return true, nil
+	}
+
+	found, err := paths(ctx, identity, needed)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ok := range found {
+		if !ok {
 			return false, nil
 		}
+	}
+
+	return true, nil
+}
+
+var errDenied = errors.New("denied")
+
+// selectedUIDs returns the uids queries select nodes by, anywhere in the
+// tree. It returns false if nodes are selected in a way that can't be
+// proven, e.g. by a root function other than uid.
+func selectedUIDs(queries []gql.GraphQuery) ([]uint64, bool) {
+	var uids []uint64
+	seen := map[uint64]bool{}
+	add := func(s ...uint64) {
+		for _, e := range s {
+			if !seen[e] {
+				seen[e] = true
+				uids = append(uids, e)
+			}
+		}
+	}
+
+	err := gql.Walk(queries, func(c *gql.Cursor) error {
+		switch n := c.Node().(type) {
+		case *gql.GraphQuery:
+			if c.Parent() == nil {
+				s, ok := rootUIDs(*n)
+				if !ok {
+					return errDenied
+				}
+				add(s...)
+			}
 
-		for _, uid := range e.UID {
-			if int(uid) == identity {
-				continue
+			// Dgraph accepts uids passed as id, even though the parser
+			// moves them to UID.
+			if _, ok := n.Args["id"]; ok {
+				return errDenied
 			}
+			add(n.UID...)
 
-			proofQuery, ok := proofs[int(uid)]
+		case *gql.Function:
+			s, ok := functionUIDs(*n)
 			if !ok {
-				return false, nil
+				return errDenied
 			}
+			add(s...)
+		}
+		return nil
+	})
 
-			ok, err := p.hasPath(ctx, int(uid), identity, proofQuery)
+	if err != nil {
+		return nil, false
+	}
+	return uids, true
+}
+
+func rootUIDs(gq gql.GraphQuery) ([]uint64, bool) {
+	if gq.Func == nil && gq.Alias == "shortest" {
+		var uids []uint64
+		for _, k := range []string{"from", "to"} {
+			uid, err := parseUID(gq.Args[k])
 			if err != nil {
-				return false, err
+				return nil, false
 			}
+			uids = append(uids, uid)
+		}
+		return uids, true
+	}
 
-			if !ok {
-				return false, nil
+	if gq.Func == nil || gq.Func.Name != "uid" {
+		return nil, false
+	}
+	return nil, true
+}
+
+func functionUIDs(fn gql.Function) ([]uint64, bool) {
+	switch fn.Name {
+	case "uid":
+		// Literals are kept in UID, arguments are GraphQL variables.
+		if len(fn.Args) > 0 {
+			return nil, false
+		}
+		return fn.UID, true
+
+	case "uid_in":
+		var uids []uint64
+		for _, e := range fn.Args {
+			uid, err := parseUID(e.Value)
+			if e.IsGraphQLVar || e.IsValueVar || err != nil {
+				return nil, false
 			}
+			uids = append(uids, uid)
 		}
+		return append(uids, fn.UID...), true
 	}
 
-	return true, nil
+	return fn.UID, true
 }
 
-func (p *Proof) hasPath(ctx context.Context, uid, identity int, proofQuery gql.GraphQuery) (bool, error) {
-	if proofQuery.Func == nil {
-		return false, nil
+func parseUID(s string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimSpace(s), 0, 64)
+}
+
+// paths checks the proofs of BatchSize uids at a time, each batch
+// rendered as one query with a block per proof. The first failing batch
+// cancels the others.
+func (p *Proof) paths(ctx context.Context, identity uint64, proofs map[uint64][]gql.GraphQuery) (map[uint64]bool, error) {
+	res := make(map[uint64]bool, len(proofs))
+	valid := make(map[uint64][]gql.GraphQuery, len(proofs))
+	var uids []uint64
+	for uid, alternatives := range proofs {
+		for _, e := range alternatives {
+			if validProof(e, identity) {
+				valid[uid] = append(valid[uid], e)
+			}
+		}
+
+		if len(valid[uid]) == 0 {
+			res[uid] = false
+			continue
+		}
+		uids = append(uids, uid)
 	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
 
-	if proofQuery.Func.Name != "uid" {
-		return false, nil
+	size := p.BatchSize
+	if size <= 0 || size > len(uids) {
+		size = len(uids)
 	}
 
-	if int(proofQuery.UID[0]) != identity {
-		return false, nil
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+
+	for i := 0; i < len(uids); i += size {
+		end := i + size
+		if end > len(uids) {
+			end = len(uids)
+		}
+
+		wg.Add(1)
+		go func(batch []uint64) {
+			defer wg.Done()
+			found, err := p.queryPaths(ctx, batch, valid)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+
+			for uid, ok := range found {
+				res[uid] = ok
+			}
+		}(uids[i:end])
 	}
 
-	query, err := render.Render(render.Query{Queries: []gql.GraphQuery{proofQuery}})
-	if err != nil {
-		return false, err
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
 	}
+	return res, nil
+}
 
-	resp, err := p.QueryHandler.Query(ctx, query, map[string]string{})
-	if err != nil {
-		return false, err
+// validProof reports whether proofQuery starts at the identity alone,
+// otherwise it could start at the uid it proves, and follows edges to
+// uids.
+func validProof(proofQuery gql.GraphQuery, identity uint64) bool {
+	if proofQuery.Func == nil || proofQuery.Func.Name != "uid" {
+		return false
 	}
 
-	proof := make(map[string]interface{})
-	if err := json.Unmarshal(resp.GetJson(), &proof); err != nil {
-		return false, err
+	if len(proofQuery.UID) != 1 || len(proofQuery.Func.NeedsVar) > 0 || len(proofQuery.NeedsVar) > 0 {
+		return false
 	}
 
-	proofUID, err := followProof(proof)
-	if err != nil {
-		return false, err
+	return proofQuery.UID[0] == identity && validProofPath(proofQuery.Children)
+}
+
+// validProofPath reports whether the blocks only follow edges and end in
+// uids. Otherwise a value the user controls, like a name or an aliased
+// facet, could pretend to be the proven uid.
+func validProofPath(children []gql.GraphQuery) bool {
+	if len(children) == 0 {
+		return false
 	}
 
-	puid, err := strconv.ParseInt(proofUID, 0, 64)
-	if err != nil {
-		return false, err
+	for _, e := range children {
+		if e.IsInternal || e.IsCount || e.Expand != "" || e.MathExp != nil || e.Func != nil {
+			return false
+		}
+		if e.Facets != nil || len(e.FacetVar) > 0 || e.FacetOrder != "" || len(e.GroupbyAttrs) > 0 {
+			return false
+		}
+
+		if len(e.Children) == 0 {
+			if e.Attr != "uid" {
+				return false
+			}
+			continue
+		}
+
+		if !validProofPath(e.Children) {
+			return false
+		}
 	}
 
-	return int(puid) == uid, nil
+	return true
+}
+
+func proofAlias(uid uint64, i int) string {
+	return fmt.Sprintf("proof_%d_%d", uid, i)
 }
 
-func followProof(node interface{}) (string, error) {
-	if next, ok := node.(string); ok {
-		return next, nil
+func (p *Proof) queryPaths(ctx context.Context, uids []uint64, proofs map[uint64][]gql.GraphQuery) (map[uint64]bool, error) {
+	var queries []gql.GraphQuery
+	for _, uid := range uids {
+		for i, e := range proofs[uid] {
+			e.Alias = proofAlias(uid, i)
+			queries = append(queries, e)
+		}
 	}
 
-	if next, ok := node.(map[string]interface{}); ok {
-		return followProof(next["proof"])
+	query, err := render.Render(render.Query{Queries: queries}, p.RenderOptions...)
+	if err != nil {
+		return nil, err
 	}
 
-	if next, ok := node.([]interface{}); ok {
-		if len(next) == 0 {
-			return "", fmt.Errorf("incorrect proof")
+	resp, err := p.QueryHandler.Query(ctx, query, map[string]string{})
+	if err != nil {
+		return nil, dgraphtools.Upstream(err)
+	}
+
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(resp.GetJson(), &data); err != nil {
+		return nil, err
+	}
+
+	res := make(map[uint64]bool, len(uids))
+	for _, uid := range uids {
+		found := false
+		for i := 0; i < len(proofs[uid]) && !found; i++ {
+			// Dgraph leaves out the nodes of a path that doesn't exist.
+			for _, e := range followProof(data[proofAlias(uid, i)]) {
+				if puid, err := parseUID(e); err == nil && puid == uid {
+					found = true
+					break
+				}
+			}
 		}
+		res[uid] = found
+	}
+
+	return res, nil
+}
 
-		return followProof(next[0])
+// followProof returns the uids at the ends of the paths. Every branch is
+// followed, as not all nodes of a level need to lead to the proven uid.
+func followProof(node interface{}) []string {
+	switch next := node.(type) {
+	case string:
+		return []string{next}
+	case map[string]interface{}:
+		return followProof(next["proof"])
+	case []interface{}:
+		var res []string
+		for _, e := range next {
+			res = append(res, followProof(e)...)
+		}
+		return res
 	}
 
-	return "", fmt.Errorf("incorrect proof")
+	return nil
 }
 
 func Middleware(verifier dgraphtools.QueryVerifier) endpoint.Middleware {
@@ -3,9 +3,13 @@ package proof
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
+	"mooncamp.com/dgraphtools"
 	"mooncamp.com/dgraphtools/gql"
 
 	"github.com/dgraph-io/dgo"
@@ -129,8 +133,8 @@ func Test_query_allowed(t *testing.T) {
 	cases := []struct {
 		name     string
 		queries  []gql.GraphQuery
-		identity int
-		proofs   map[int]gql.GraphQuery
+		identity uint64
+		proofs   map[uint64]gql.GraphQuery
 		allowed  bool
 	}{
 		{
@@ -150,9 +154,9 @@ func Test_query_allowed(t *testing.T) {
 		{
 			name:     "allow uid inputs with proof query",
 			queries:  []gql.GraphQuery{{UID: []uint64{parseID(t, users[0].Company.UID)}, Func: &gql.Function{Name: "uid"}}},
-			identity: int(parseID(t, users[0].UID)),
-			proofs: map[int]gql.GraphQuery{
-				int(parseID(t, users[0].Company.UID)): gql.GraphQuery{
+			identity: parseID(t, users[0].UID),
+			proofs: map[uint64]gql.GraphQuery{
+				parseID(t, users[0].Company.UID): gql.GraphQuery{
 					Alias: "proof",
 					UID:   []uint64{parseID(t, users[0].UID)},
 					Func:  &gql.Function{Name: "uid"},
@@ -173,9 +177,9 @@ func Test_query_allowed(t *testing.T) {
 		{
 			name:     "disallow uid inputs with misleading proof query",
 			queries:  []gql.GraphQuery{{UID: []uint64{parseID(t, users[1].Company.UID)}, Func: &gql.Function{Name: "uid"}}},
-			identity: int(parseID(t, users[0].UID)),
-			proofs: map[int]gql.GraphQuery{
-				int(parseID(t, users[0].Company.UID)): gql.GraphQuery{
+			identity: parseID(t, users[0].UID),
+			proofs: map[uint64]gql.GraphQuery{
+				parseID(t, users[0].Company.UID): gql.GraphQuery{
 					Alias: "proof",
 					UID:   []uint64{parseID(t, users[0].UID)},
 					Func:  &gql.Function{Name: "uid"},
@@ -202,7 +206,7 @@ func Test_query_allowed(t *testing.T) {
 	for _, e := range cases {
 		allowed, queries, identity, proofs := e.allowed, e.queries, e.identity, e.proofs
 		t.Run(e.name, func(t *testing.T) {
-			ok, err := proof.QueryAllowed(context.Background(), queries, identity, proofs)
+			ok, err := proof.QueryAllowed(context.Background(), queries, dgraphtools.Identity{UID: identity}, proofs)
 			if err != nil {
 				t.Fatalf("check query: %v", err)
 			}
@@ -211,3 +215,225 @@ func Test_query_allowed(t *testing.T) {
 		})
 	}
 }
+
+// staticHandler answers every query with resp.
+type staticHandler struct {
+	resp string
+}
+
+func (h staticHandler) Query(ctx context.Context, q string, vars map[string]string) (*api.Response, error) {
+	return &api.Response{Json: []byte(h.resp)}, nil
+}
+
+func Test_bypass_attempts(t *testing.T) {
+	proofOf5 := gql.GraphQuery{
+		Alias: "proof",
+		UID:   []uint64{1},
+		Func:  &gql.Function{Name: "uid"},
+		Children: []gql.GraphQuery{
+			{Attr: "company", Alias: "proof", Children: []gql.GraphQuery{{Attr: "uid", Alias: "proof"}}},
+		},
+	}
+
+	misleadingProofOf5 := proofOf5
+	misleadingProofOf5.UID = []uint64{1, 5}
+
+	facetProof := proofOf5
+	facetProof.Children = []gql.GraphQuery{{
+		Attr:     "company",
+		Alias:    "proof",
+		Facets:   &gql.FacetParams{Param: []gql.FacetParam{{Key: "note", Alias: "proof"}}},
+		Children: []gql.GraphQuery{{Attr: "uid"}},
+	}}
+
+	valueProof, err := gql.Parse(gql.Request{Str: `{ proof(func: uid(0x1)) { proof: name } }`})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	cases := []struct {
+		name      string
+		query     string
+		variables map[string]string
+		queries   []gql.GraphQuery
+		proofs    map[uint64]gql.GraphQuery
+		allowed   bool
+	}{
+		{name: "identity", query: `{ q(func: uid(0x1)) @recurse { friend expand(_all_) } }`, allowed: true},
+		{name: "variable of proven block", query: `{ var(func: uid(0x1)) { a as friend } q(func: uid(a)) @filter(uid(a)) { name } }`, allowed: true},
+		{name: "root uid", query: `{ q(func: uid(0x1, 0x5)) { name } }`},
+		{name: "root function", query: `{ q(func: has(name)) { name } }`},
+		{name: "var block", query: `{ var(func: uid(0x5)) { a as friend } q(func: uid(0x1)) @filter(uid(a)) { name } }`},
+		{name: "var block with root function", query: `{ a as var(func: has(name)) q(func: uid(a)) { name } }`},
+		{name: "nested filter", query: `{ q(func: uid(0x1)) { friend @filter(uid(0x5)) { name } } }`},
+		{name: "nested filter tree", query: `{ q(func: uid(0x1)) { friend @filter(has(name) and (eq(name, "a") or uid(0x5))) { name } } }`},
+		{name: "uid_in", query: `{ q(func: uid(0x1)) { friend @filter(uid_in(company, 0x5)) { name } } }`},
+		{name: "shortest path", query: `{ path as shortest(from: 0x1, to: 0x5) { friend } q(func: uid(path)) { name } }`},
+		{name: "shortest path from identity", query: `{ path as shortest(from: 0x1, to: 0x1) { friend } q(func: uid(path)) { name } }`, allowed: true},
+		{name: "graphql variable", query: `query q($id: string) { q(func: uid(0x1)) { friend @filter(uid($id)) { name } } }`, variables: map[string]string{"$id": "0x5"}},
+		{
+			name:    "id argument",
+			queries: []gql.GraphQuery{{Alias: "q", UID: []uint64{1}, Func: &gql.Function{Name: "uid"}, Args: map[string]string{"id": "0x5"}, Children: []gql.GraphQuery{{Attr: "name"}}}},
+		},
+		{
+			name:    "shortest path with variable",
+			queries: []gql.GraphQuery{{Alias: "shortest", Args: map[string]string{"from": "uid(a)", "to": "0x1"}, Children: []gql.GraphQuery{{Attr: "friend"}}}},
+		},
+		{
+			name:    "proven nested filter",
+			query:   `{ q(func: uid(0x1)) { friend @filter(uid(0x5)) { name } } }`,
+			proofs:  map[uint64]gql.GraphQuery{5: proofOf5},
+			allowed: true,
+		},
+		{
+			name:   "proof starting at the proven uid",
+			query:  `{ q(func: uid(0x5)) { name } }`,
+			proofs: map[uint64]gql.GraphQuery{5: misleadingProofOf5},
+		},
+		{
+			name:   "proof ending in a value",
+			query:  `{ q(func: uid(0x5)) { name } }`,
+			proofs: map[uint64]gql.GraphQuery{5: valueProof[0]},
+		},
+		{
+			name:   "proof ending in a facet",
+			query:  `{ q(func: uid(0x5)) { name } }`,
+			proofs: map[uint64]gql.GraphQuery{5: facetProof},
+		},
+	}
+
+	proof := &Proof{QueryHandler: staticHandler{resp: `{"proof_5_0": [{"proof": [{"proof": "0x5"}]}]}`}}
+
+	for _, e := range cases {
+		t.Run(e.name, func(t *testing.T) {
+			queries := e.queries
+			if e.query != "" {
+				var err error
+				queries, err = gql.Parse(gql.Request{Str: e.query, Variables: e.variables})
+				if err != nil {
+					t.Fatalf("parse: %v", err)
+				}
+			}
+
+			ok, err := proof.QueryAllowed(context.Background(), queries, dgraphtools.Identity{UID: 1}, e.proofs)
+			if err != nil {
+				t.Fatalf("check query: %v", err)
+			}
+
+			assert.Equal(t, e.allowed, ok)
+		})
+	}
+}
+
+// pathHandler answers the blocks of proof queries for the uids in paths
+// and records the queries.
+type pathHandler struct {
+	mu      sync.Mutex
+	paths   map[uint64]bool
+	queries []string
+}
+
+func (h *pathHandler) Query(ctx context.Context, q string, vars map[string]string) (*api.Response, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.queries = append(h.queries, q)
+
+	resp := map[string]interface{}{}
+	for uid, ok := range h.paths {
+		alias := fmt.Sprintf("proof_%d_0", uid)
+		if !strings.Contains(q, alias+" ") {
+			continue
+		}
+
+		resp[alias] = []interface{}{}
+		if ok {
+			resp[alias] = []interface{}{map[string]interface{}{"proof": fmt.Sprintf("0x%x", uid)}}
+		}
+	}
+
+	js, err := json.Marshal(resp)
+	return &api.Response{Json: js}, err
+}
+
+func Test_proof_branches(t *testing.T) {
+	queries, err := gql.Parse(gql.Request{Str: `{ q(func: uid(0x5)) { name } }`})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	proofs := map[uint64]gql.GraphQuery{5: {
+		Alias: "proof",
+		UID:   []uint64{1},
+		Func:  &gql.Function{Name: "uid"},
+		Children: []gql.GraphQuery{
+			{Attr: "friend", Alias: "proof", Children: []gql.GraphQuery{{Attr: "uid", Alias: "proof"}}},
+		},
+	}}
+
+	cases := []struct {
+		name    string
+		resp    string
+		allowed bool
+	}{
+		{name: "first branch", resp: `{"proof_5_0": [{"proof": [{"proof": "0x5"}, {"proof": "0x7"}]}]}`, allowed: true},
+		{name: "second branch", resp: `{"proof_5_0": [{"proof": [{"proof": "0x7"}, {"proof": "0x5"}]}]}`, allowed: true},
+		{name: "no branch", resp: `{"proof_5_0": [{"proof": [{"proof": "0x7"}, {"proof": "0x8"}]}]}`},
+	}
+
+	for _, e := range cases {
+		t.Run(e.name, func(t *testing.T) {
+			proof := &Proof{QueryHandler: staticHandler{resp: e.resp}}
+
+			ok, err := proof.QueryAllowed(context.Background(), queries, dgraphtools.Identity{UID: 1}, proofs)
+			if err != nil {
+				t.Fatalf("check query: %v", err)
+			}
+
+			assert.Equal(t, e.allowed, ok)
+		})
+	}
+}
+
+func Test_batched_proofs(t *testing.T) {
+	queries, err := gql.Parse(gql.Request{Str: `{ q(func: uid(0x1, 0x5, 0x6, 0x7)) { name } }`})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	proofs := map[uint64]gql.GraphQuery{}
+	for _, uid := range []uint64{5, 6, 7} {
+		proofs[uid] = gql.GraphQuery{
+			Alias:    "proof",
+			UID:      []uint64{1},
+			Func:     &gql.Function{Name: "uid"},
+			Children: []gql.GraphQuery{{Attr: "uid", Alias: "proof"}},
+		}
+	}
+
+	cases := []struct {
+		name      string
+		batchSize int
+		paths     map[uint64]bool
+		queries   int
+		allowed   bool
+	}{
+		{name: "single query", paths: map[uint64]bool{5: true, 6: true, 7: true}, queries: 1, allowed: true},
+		{name: "batches", batchSize: 2, paths: map[uint64]bool{5: true, 6: true, 7: true}, queries: 2, allowed: true},
+		{name: "missing path", batchSize: 1, paths: map[uint64]bool{5: true, 6: false, 7: true}, queries: 3},
+	}
+
+	for _, e := range cases {
+		t.Run(e.name, func(t *testing.T) {
+			h := &pathHandler{paths: e.paths}
+			proof := &Proof{QueryHandler: h, BatchSize: e.batchSize}
+
+			ok, err := proof.QueryAllowed(context.Background(), queries, dgraphtools.Identity{UID: 1}, proofs)
+			if err != nil {
+				t.Fatalf("check query: %v", err)
+			}
+
+			assert.Equal(t, e.allowed, ok)
+			assert.Len(t, h.queries, e.queries)
+		})
+	}
+}
@@ -3,11 +3,11 @@ package endpoint
 import (
 	"context"
 
+	"mooncamp.com/dgraphtools"
 	"mooncamp.com/dgraphtools/gql"
 	"mooncamp.com/dgraphtools/qb"
 	"mooncamp.com/dgraphtools/render"
 
-	dgraphgql "github.com/dgraph-io/dgraph/gql"
 	"github.com/go-kit/kit/endpoint"
 )
 
@@ -49,6 +49,9 @@ func MakeTemplateEndpoint() endpoint.Endpoint {
 			Alias:     req.Alias,
 			Variables: req.Variables,
 		})
+		if err != nil {
+			err = dgraphtools.InvalidRequest{Err: err}
+		}
 
 		return qb.TemplateResponse{
 			Query: q,
@@ -60,12 +63,11 @@ func MakeTemplateEndpoint() endpoint.Endpoint {
 func MakeParseEndpoint() endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(qb.ParseRequest)
-		result, err := dgraphgql.Parse(dgraphgql.Request{Str: req.Query, Variables: req.Variables})
+		queries, err := gql.Parse(gql.Request{Str: req.Query, Variables: req.Variables})
 		if err != nil {
-			return qb.ParseResponse{Error: err}, nil
+			return qb.ParseResponse{Error: dgraphtools.InvalidRequest{Err: err}}, nil
 		}
 
-		queries := gql.DecodeGraphQueries(result.Query)
 		return qb.ParseResponse{
 			Error:   nil,
 			Queries: queries,
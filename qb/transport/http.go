@@ -3,11 +3,12 @@ package transport
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 
+	"mooncamp.com/dgraphtools"
 	"mooncamp.com/dgraphtools/gql"
 	"mooncamp.com/dgraphtools/qb"
+	dgtransport "mooncamp.com/dgraphtools/transport"
 
 	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
@@ -19,11 +20,13 @@ func NewHTTPHandler(eps qb.EndpointSet, pathPrefix string) http.Handler {
 		eps.Template,
 		decodeTemplateRequest,
 		encodeTemplateResponse,
+		httptransport.ServerErrorEncoder(dgtransport.ErrorEncoder),
 	)).Methods(http.MethodPost)
 	r.Handle("/parse", httptransport.NewServer(
 		eps.Parse,
 		decodeParseRequest,
 		encodeParseResponse,
+		httptransport.ServerErrorEncoder(dgtransport.ErrorEncoder),
 	)).Methods(http.MethodPost)
 
 	return r
@@ -37,7 +40,7 @@ func decodeTemplateRequest(ctx context.Context, r *http.Request) (request interf
 	}{}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return nil, err
+		return nil, dgraphtools.InvalidRequest{Err: err}
 	}
 
 	return qb.TemplateRequest{
@@ -50,7 +53,7 @@ func decodeTemplateRequest(ctx context.Context, r *http.Request) (request interf
 func encodeTemplateResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
 	resp := response.(qb.TemplateResponse)
 	if resp.Error != nil {
-		http.Error(w, fmt.Sprintf("%v", resp.Error), http.StatusInternalServerError)
+		dgtransport.ErrorEncoder(ctx, resp.Error, w)
 		return nil
 	}
 
@@ -65,7 +68,7 @@ func decodeParseRequest(ctx context.Context, r *http.Request) (request interface
 	}{}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return nil, err
+		return nil, dgraphtools.InvalidRequest{Err: err}
 	}
 
 	return qb.ParseRequest{
@@ -77,7 +80,7 @@ func decodeParseRequest(ctx context.Context, r *http.Request) (request interface
 func encodeParseResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
 	resp := response.(qb.ParseResponse)
 	if resp.Error != nil {
-		http.Error(w, fmt.Sprintf("%v", resp.Error), http.StatusInternalServerError)
+		dgtransport.ErrorEncoder(ctx, resp.Error, w)
 		return nil
 	}
 
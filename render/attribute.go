@@ -32,10 +32,7 @@ func renderAttributeArgs(query gql.GraphQuery) string {
 		return ""
 	}
 
-	res := make([]string, 0, len(query.Args))
-	for k, v := range query.Args {
-		res = append(res, fmt.Sprintf("%s: %s", k, v))
-	}
+	res := encodeArgMap(query.Args)
 
 	for _, e := range query.Order {
 		dir := "orderasc"
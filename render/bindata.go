@@ -68,7 +68,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _tmplQueryTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x54\x3d\x8f\xdb\x30\x0c\xdd\xf3\x2b\x08\x4f\x3d\xa0\xf1\xd0\xf1\xa6\x5e\x0f\x0d\xd0\xa1\x01\xba\x74\xe7\xc5\xcc\x59\x85\x22\x3b\x12\x7d\xe8\x55\xd1\x7f\x2f\xf4\x65\xcb\x49\xed\x74\xa3\x88\xc7\x27\x3e\x3e\x4a\xd6\x6e\xa1\xa1\xa3\x50\x04\xd5\x1b\xea\x0a\xb6\xce\x6d\xac\x05\x71\x84\xfa\x27\x6a\x70\xce\xda\x1c\x01\x1a\xb0\x16\x48\x35\x10\x40\xdb\x10\xfa\x82\x4d\x49\xd3\x6b\x6a\x22\x8f\x8f\x1e\x7d\x49\xfd\xc4\xac\x57\x8b\x0e\xdd\xa0\x38\x56\x85\xf0\x43\x51\x15\x18\x76\x42\x32\x69\xb8\xc0\x31\x06\xce\x3d\x2c\xb3\xa1\x14\x68\xb2\x96\x6d\x10\xf3\xe4\x53\x59\x5d\x3a\x39\xf7\x38\xe3\x58\xe4\x53\x9d\x3e\xa1\x14\x7f\x68\xce\xb9\xcf\xe9\x90\xfd\x3c\xa2\xfe\x97\xb4\xb9\xe2\xdb\x0d\xea\x10\xa7\x04\x4c\xa7\x5e\x22\x67\x57\xea\x64\x84\xd7\xaf\xa6\x58\x28\x26\xad\x50\xee\xd4\xf2\x98\x60\xc6\x56\x28\xa9\x73\x0d\x5c\xe0\x55\x77\x43\xff\xe5\x7d\xb4\x48\x1a\x0a\x1d\x7d\x47\x6e\xbf\xfe\xee\x17\x9a\x0a\x7d\x9c\x90\xdb\x2b\xe0\x44\x40\xe7\xe4\x62\xf5\x86\xb2\xba\x66\x49\x36\x65\x71\xc9\xee\x60\xfd\x9e\xa8\x31\x7e\xed\x2e\x20\x85\xe1\x5f\x9d\x50\x50\xd5\x7b\x3c\x51\x05\xd5\xc7\x6a\xb2\xbf\xbc\x28\x98\x9a\xf6\x6f\xa5\xe3\x29\x19\xa1\xf5\x3f\xbb\x8e\x64\xa6\xed\x34\x93\xe1\x55\x42\x3f\xc1\x0c\xbc\x19\xe1\x37\xf3\xec\x17\xfa\xa6\x7e\x2e\xfe\x4e\xa3\xe9\x79\xcc\x3b\xbd\xd3\x11\x32\x6b\xf1\x32\x30\xad\xef\xc6\xdc\xfe\x9c\x39\xe2\x81\xd8\xdc\x24\x76\xb9\x72\x5c\xeb\xbc\xd5\x5e\xea\x73\x2b\x64\xa3\x29\x2e\x23\x94\x08\x80\x70\xd4\xa8\x5e\x69\x86\x0b\x7f\x8d\x3a\xc8\xa1\x19\x5f\x44\x5c\xec\x86\x14\xc3\x27\x8f\x48\xb5\xe3\xb7\x73\x73\x95\x5b\xfa\x95\xce\x03\xe9\x77\x18\xdf\x7b\x14\x93\x7e\x36\x81\x2f\x92\x4c\x5e\xb7\x29\xe1\x87\x81\x7d\x7b\x96\x25\xe6\x61\xbc\x00\xec\xa6\x10\xf2\x63\x20\x2d\x02\x22\xce\x60\x5d\x48\xa1\xc2\xfd\x0d\x00\x00\xff\xff\xcd\x7b\x51\xe3\x78\x05\x00\x00")
+var _tmplQueryTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\x03\x8d\x54\x3d\x6f\xe3\x30\x0c\xdd\xf3\x2b\x08\x4f\x0d\x70\xf1\xd0\x31\xd3\xf5\x8a\x0b\x70\xc3\x05\xe8\xd0\xee\xaa\xc5\x34\x2a\x14\xd9\x91\xe4\xa0\xa9\xab\xff\x7e\xfa\xb4\x65\xf7\xec\x76\x23\x89\xc7\x27\x3e\x92\x62\xd7\x6d\x80\xe2\x81\x09\x84\xe2\x42\x64\x01\x1b\x63\x56\x5d\x07\xec\x00\xe5\x13\x91\x60\x8c\x75\xa2\x05\x44\x81\xf5\x50\x50\xf0\xa0\x8d\x37\x5d\x82\x77\x12\x4d\x23\x91\x06\x1e\x67\x6d\x5d\x4a\x79\xa7\xb5\x5c\x4c\xaa\xea\x56\xe8\x90\xe5\xcd\x9b\x2c\xcb\x33\xec\x18\xd7\x28\xe1\x03\x0e\xc1\x30\x66\x3d\xcf\x46\x38\x23\x2a\x69\xd9\x78\x31\x77\x2e\x94\xd4\x45\xcf\x98\xed\x88\x63\x96\x4f\xd4\xf2\x64\x39\xdf\x71\xcc\xb9\x4f\x61\x1f\xfd\xd9\xa3\xbe\x4b\x4a\x27\x7c\xbb\x56\x54\xa1\x4b\xa0\xf1\xd4\x70\xa2\xd3\x54\xca\x38\x08\xa7\x5f\x0c\x36\x13\xb6\x15\x82\xf0\x9d\x98\x6f\x13\x8c\xd8\x32\x25\x65\xca\xb1\x68\xca\x24\x56\x9a\x5d\x50\x65\xc1\x17\x59\xb7\xcd\xaf\x6b\x3f\x37\xae\xd0\x97\xf9\x97\xe8\xe3\xef\xb7\x66\xa6\x52\x5f\xdc\xc9\x42\x26\xc0\x81\x00\xcf\x71\xb4\x36\x83\x17\x53\x96\x38\xbb\xa4\x38\xee\x80\xdf\x87\x3d\x22\x55\x6e\x17\x3f\x80\x33\xa5\x5f\x6b\x26\xa0\x28\xf7\xe4\x64\xd5\x14\x3f\x8a\x61\x27\xf2\x87\xfc\xa4\xe3\x52\x2e\x54\x3c\x04\x03\xb4\xfc\x6f\xd5\x81\x4c\x1d\x6b\xa9\x51\xe9\x45\x42\xd7\xc1\x04\xfc\xd4\xc2\x3f\xea\xde\x6d\x39\x2c\x8b\xff\xa2\xd0\xf8\x67\xc6\x95\x7e\x51\x11\xb1\xed\x64\xcf\xad\xc6\xe5\x85\xf9\xc6\x4e\xa4\xc8\x81\x54\xa8\xd5\xa7\xc0\x2e\xd1\xf5\x1f\x20\xed\xbf\xd5\x5f\x4b\x28\xef\x8f\x8c\x53\x89\x02\xca\x47\x46\x53\x37\xa0\x83\x1c\x0e\xe0\x5d\x49\xc4\x0b\x66\x19\xf1\x44\x89\x8a\xb7\xb4\xff\x48\xe1\x3f\x50\xb4\x3c\xb7\x0e\x11\x73\x27\x54\xae\xf9\xd9\x7b\x2b\x17\x1d\x05\xd3\x65\x70\x62\xa6\xb1\xed\x70\xfe\xc2\x91\x6a\x19\x5d\x4f\x1e\x0a\x74\xf3\x02\xcd\xdc\x09\x3d\xb7\x28\xaf\xd0\x1f\xa7\xd0\xcf\x78\x86\x19\x79\xe6\x7e\x0c\x37\xf1\x1a\xc7\x80\x9b\x07\x69\x8e\x67\x9e\x63\xd6\xfd\x03\xd0\xad\xb2\xf6\x3d\xd8\x07\x98\x47\x84\x31\x2c\xb7\x2f\x93\x64\xfe\x01\x78\xb8\x1d\x00\x25\x06\x00\x00")
 
 func tmplQueryTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -83,7 +83,7 @@ func tmplQueryTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/query.tmpl", size: 1400, mode: os.FileMode(420), modTime: time.Unix(1547672537, 0)}
+	info := bindataFileInfo{name: "tmpl/query.tmpl", size: 1573, mode: os.FileMode(420), modTime: time.Unix(1547672537, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -0,0 +1,385 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"mooncamp.com/dgraphtools/gql"
+)
+
+// Version is a Dgraph release the query is rendered for.
+type Version struct {
+	Major int
+	Minor int
+}
+
+var (
+	Version1_0  = Version{Major: 1, Minor: 0}
+	Version1_1  = Version{Major: 1, Minor: 1}
+	Version1_2  = Version{Major: 1, Minor: 2}
+	Version20_3 = Version{Major: 20, Minor: 3}
+	Version20_7 = Version{Major: 20, Minor: 7}
+	Version21_3 = Version{Major: 21, Minor: 3}
+
+	// DefaultVersion is the version queries are rendered for if no
+	// version is given.
+	DefaultVersion = Version1_0
+)
+
+// ParseVersion parses versions like `v20.07.1` or `1.1`. The patch
+// release is ignored as it never changes the query language.
+func ParseVersion(s string) (Version, error) {
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid dgraph version %q", s)
+	}
+
+	nums := make([]int, 0, len(parts))
+	for _, e := range parts {
+		n, err := strconv.Atoi(e)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid dgraph version %q", s)
+		}
+		nums = append(nums, n)
+	}
+
+	return Version{Major: nums[0], Minor: nums[1]}, nil
+}
+
+func (v Version) String() string {
+	if v.Major >= 20 {
+		return fmt.Sprintf("v%d.%02d", v.Major, v.Minor)
+	}
+	return fmt.Sprintf("v%d.%d", v.Major, v.Minor)
+}
+
+// Less reports whether v was released before o.
+func (v Version) Less(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	return v.Minor < o.Minor
+}
+
+// Option configures the rendering of a query.
+type Option func(*options)
+
+type options struct {
+	version Version
+}
+
+// WithVersion renders the query for the given Dgraph version.
+func WithVersion(v Version) Option {
+	return func(o *options) {
+		o.version = v
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{version: DefaultVersion}
+	for _, e := range opts {
+		e(&o)
+	}
+	return o
+}
+
+// feature is a part of the query language that isn't available in
+// every version.
+type feature struct {
+	name  string
+	since Version
+	until Version // zero if the feature is still supported
+}
+
+var (
+	featureType          = feature{name: "type()", since: Version1_1}
+	featureExpandType    = feature{name: "expand(Type)", since: Version1_1}
+	featureExpandForward = feature{name: "expand(_forward_)", until: Version1_1}
+	featureExpandReverse = feature{name: "expand(_reverse_)", until: Version1_1}
+	featureNestedCascade = feature{name: "nested @cascade", since: Version1_1}
+	featureCascadeFields = feature{name: "@cascade(fields)", since: Version20_7}
+	featureBetween       = feature{name: "between() at root", since: Version20_7}
+	featureUIDInList     = feature{name: "uid_in() with several uids", since: Version21_3}
+	featureUIDInVar      = feature{name: "uid_in() with a uid variable", since: Version21_3}
+)
+
+func (f feature) supported(v Version) bool {
+	if v.Less(f.since) {
+		return false
+	}
+	return f.until == (Version{}) || v.Less(f.until)
+}
+
+func checkFeature(f feature, v Version) error {
+	if !f.supported(v) {
+		return UnsupportedFeatureError{Feature: f.name, Version: v}
+	}
+	return nil
+}
+
+// checkVersion makes sure the query only uses features the version
+// supports. Features that can be expressed differently, e.g. between()
+// in a filter, are rewritten by lowerQuery instead.
+func checkVersion(query Query, v Version) error {
+	for _, e := range query.Queries {
+		if err := checkGraphQueryVersion(e, v, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkGraphQueryVersion(gq gql.GraphQuery, v Version, root bool) error {
+	if gq.Cascade && !root {
+		if err := checkFeature(featureNestedCascade, v); err != nil {
+			return err
+		}
+	}
+
+	if len(gq.CascadeFields) > 0 {
+		if err := checkFeature(featureCascadeFields, v); err != nil {
+			return err
+		}
+	}
+
+	if gq.Recurse && gq.RecurseArgs.AllowLoop && gq.RecurseArgs.Depth == 0 {
+		return InvalidLiteralError{Kind: "recurse depth", Value: "0", Reason: "a depth is required if loops are allowed"}
+	}
+
+	if err := checkExpandVersion(gq, v); err != nil {
+		return err
+	}
+
+	if gq.Func != nil && gq.Func.Name == "between" {
+		if err := checkFeature(featureBetween, v); err != nil {
+			return err
+		}
+	}
+
+	checks := []error{
+		checkFunctionVersion(gq.Func, v),
+		checkFilterTreeVersion(gq.Filter, v),
+		checkFilterTreeVersion(gq.FacetsFilter, v),
+	}
+
+	for _, e := range checks {
+		if e != nil {
+			return e
+		}
+	}
+
+	for _, e := range gq.Children {
+		if err := checkGraphQueryVersion(e, v, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkExpandVersion(gq gql.GraphQuery, v Version) error {
+	if gq.Expand == "" || len(gq.NeedsVar) > 0 {
+		return nil
+	}
+
+	switch gq.Expand {
+	case "_all_":
+		// The syntax is the same in every version, but since v1.1 only
+		// the predicates of the node's types in dgraph.type are expanded
+		// instead of all its predicates. Nodes without a type expand to
+		// nothing there.
+		return nil
+	case "_forward_":
+		return checkFeature(featureExpandForward, v)
+	case "_reverse_":
+		return checkFeature(featureExpandReverse, v)
+	}
+
+	return checkFeature(featureExpandType, v)
+}
+
+func checkFunctionVersion(fn *gql.Function, v Version) error {
+	if fn == nil {
+		return nil
+	}
+
+	switch fn.Name {
+	case "type":
+		if err := checkFeature(featureType, v); err != nil {
+			return err
+		}
+
+		if len(fn.Args) != 1 {
+			return InvalidLiteralError{Kind: "function", Value: fn.Name, Reason: "expected exactly one type name"}
+		}
+		return checkName("type", fn.Args[0].Value)
+	case "uid_in":
+		if len(fn.Args) > 1 {
+			if err := checkFeature(featureUIDInList, v); err != nil {
+				return err
+			}
+		}
+
+		if len(fn.Args) == 0 && len(fn.NeedsVar) > 0 {
+			if err := checkFeature(featureUIDInVar, v); err != nil {
+				return err
+			}
+		}
+
+		for _, e := range fn.Args {
+			if !e.IsGraphQLVar && !isUID(e.Value) {
+				return InvalidLiteralError{Kind: "uid", Value: e.Value, Reason: "expected a uid"}
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkFilterTreeVersion(ft *gql.FilterTree, v Version) error {
+	if ft == nil {
+		return nil
+	}
+
+	if err := checkFunctionVersion(ft.Func, v); err != nil {
+		return err
+	}
+
+	for i := range ft.Child {
+		if err := checkFilterTreeVersion(&ft.Child[i], v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lowerQuery rewrites the parts of the query the version doesn't
+// support, but can be expressed with older syntax. The query passed in
+// is left untouched.
+func lowerQuery(query Query, v Version) Query {
+	if featureBetween.supported(v) {
+		return query
+	}
+
+	queries := make([]gql.GraphQuery, 0, len(query.Queries))
+	for _, e := range query.Queries {
+		queries = append(queries, lowerGraphQuery(e))
+	}
+	query.Queries = queries
+
+	return query
+}
+
+func lowerGraphQuery(gq gql.GraphQuery) gql.GraphQuery {
+	gq.Filter = lowerFilterTree(gq.Filter)
+	gq.FacetsFilter = lowerFilterTree(gq.FacetsFilter)
+
+	if len(gq.Children) > 0 {
+		children := make([]gql.GraphQuery, 0, len(gq.Children))
+		for _, e := range gq.Children {
+			children = append(children, lowerGraphQuery(e))
+		}
+		gq.Children = children
+	}
+
+	return gq
+}
+
+// lowerFilterTree replaces between(attr, a, b) with
+// (ge(attr, a) and le(attr, b)).
+func lowerFilterTree(ft *gql.FilterTree) *gql.FilterTree {
+	if ft == nil {
+		return nil
+	}
+
+	res := *ft
+	if fn := ft.Func; fn != nil && fn.Name == "between" && len(fn.Args) == 2 {
+		ge, le := *fn, *fn
+		ge.Name, ge.Args = "ge", fn.Args[:1]
+		le.Name, le.Args = "le", fn.Args[1:]
+
+		return &gql.FilterTree{
+			Op:    "and",
+			Child: []gql.FilterTree{{Func: &ge}, {Func: &le}},
+		}
+	}
+
+	if len(ft.Child) > 0 {
+		res.Child = make([]gql.FilterTree, 0, len(ft.Child))
+		for i := range ft.Child {
+			res.Child = append(res.Child, *lowerFilterTree(&ft.Child[i]))
+		}
+	}
+
+	return &res
+}
+
+// renderSpecialFunc renders functions whose arguments aren't quoted
+// values, e.g. `type(Person)` or `uid_in(friend, [0x1, 0x2])`.
+func renderSpecialFunc(fn *gql.Function) (string, bool) {
+	switch fn.Name {
+	case "type":
+		if len(fn.Args) == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("type(%s)", fn.Args[0].Value), true
+	case "uid_in":
+		if fn.Attr == "" {
+			return "", false
+		}
+
+		attr := formatAttribute(fn.Attr)
+		if len(fn.Args) == 0 && len(fn.NeedsVar) > 0 {
+			return fmt.Sprintf("uid_in(%s, uid(%s))", attr, strings.Join(encodeNeedVar(fn.NeedsVar, false), ", ")), true
+		}
+
+		uids := make([]string, 0, len(fn.Args))
+		for _, e := range fn.Args {
+			uids = append(uids, e.Value)
+		}
+
+		if len(uids) == 1 {
+			return fmt.Sprintf("uid_in(%s, %s)", attr, uids[0]), true
+		}
+		return fmt.Sprintf("uid_in(%s, [%s])", attr, strings.Join(uids, ", ")), true
+	}
+
+	return "", false
+}
+
+func renderDirectives(gq gql.GraphQuery) string {
+	res := []string{}
+	if len(gq.CascadeFields) > 0 {
+		res = append(res, fmt.Sprintf("@cascade(%s)", strings.Join(gq.CascadeFields, ", ")))
+	} else if gq.Cascade {
+		res = append(res, "@cascade")
+	}
+
+	if gq.Recurse {
+		res = append(res, renderRecurse(gq.RecurseArgs))
+	}
+
+	if gq.IgnoreReflex {
+		res = append(res, "@ignorereflex")
+	}
+
+	return strings.Join(res, " ")
+}
+
+func renderRecurse(args gql.RecurseArgs) string {
+	res := []string{}
+	if args.Depth > 0 {
+		res = append(res, fmt.Sprintf("depth: %d", args.Depth))
+	}
+
+	if args.AllowLoop {
+		res = append(res, "loop: true")
+	}
+
+	if len(res) == 0 {
+		return "@recurse"
+	}
+	return fmt.Sprintf("@recurse(%s)", strings.Join(res, ", "))
+}
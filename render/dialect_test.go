@@ -0,0 +1,268 @@
+package render
+
+import (
+	"testing"
+
+	"mooncamp.com/dgraphtools/gql"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	table := []struct {
+		in       string
+		expected Version
+	}{
+		{in: "v1.0.11", expected: Version1_0},
+		{in: "1.1", expected: Version1_1},
+		{in: "v20.07.1", expected: Version20_7},
+		{in: "v21.03", expected: Version21_3},
+	}
+
+	for _, e := range table {
+		t.Run(e.in, func(t *testing.T) {
+			v, err := ParseVersion(e.in)
+			require.NoError(t, err)
+			require.Equal(t, e.expected, v)
+		})
+	}
+
+	for _, e := range []string{"", "v1", "latest", "v1.x.0", "1.2.3.4"} {
+		_, err := ParseVersion(e)
+		require.Error(t, err, e)
+	}
+}
+
+func TestRenderVersion(t *testing.T) {
+	name := gql.GraphQuery{Attr: "name"}
+	table := []struct {
+		name     string
+		version  Version
+		query    gql.GraphQuery
+		expected string
+	}{
+		{
+			name:    "type at root",
+			version: Version1_1,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "type", Args: []gql.Arg{{Value: "Person"}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: "me (func: type(Person)) {",
+		},
+		{
+			name:    "type in filter",
+			version: Version1_1,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "has", Attr: "name"},
+				Filter:   &gql.FilterTree{Func: &gql.Function{Name: "type", Args: []gql.Arg{{Value: "Person"}}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: "me (func: has(name)) @filter(type(Person)) {",
+		},
+		{
+			name:    "expand type",
+			version: Version1_1,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				UID:      []uint64{1},
+				Func:     &gql.Function{Name: "uid"},
+				Children: []gql.GraphQuery{{Attr: "expand", Expand: "Person"}},
+			},
+			expected: "expand(Person)",
+		},
+		{
+			name:    "nested cascade with fields",
+			version: Version20_7,
+			query: gql.GraphQuery{
+				Alias: "me",
+				UID:   []uint64{1},
+				Func:  &gql.Function{Name: "uid"},
+				Children: []gql.GraphQuery{
+					{Attr: "friend", Cascade: true, CascadeFields: []string{"name", "age"}, Children: []gql.GraphQuery{name}},
+				},
+			},
+			expected: "friend @cascade(name, age) {",
+		},
+		{
+			name:    "between at root",
+			version: Version20_7,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "between", Attr: "age", Args: []gql.Arg{{Value: "18"}, {Value: "30"}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: "me (func: between(age, \"18\", \"30\")) {",
+		},
+		{
+			name:    "between in filter before v20.07",
+			version: Version1_1,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "has", Attr: "name"},
+				Filter:   &gql.FilterTree{Func: &gql.Function{Name: "between", Attr: "age", Args: []gql.Arg{{Value: "18"}, {Value: "30"}}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: "me (func: has(name)) @filter((ge(age, \"18\") and le(age, \"30\"))) {",
+		},
+		{
+			name:    "between in filter",
+			version: Version20_7,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "has", Attr: "name"},
+				Filter:   &gql.FilterTree{Func: &gql.Function{Name: "between", Attr: "age", Args: []gql.Arg{{Value: "18"}, {Value: "30"}}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: "me (func: has(name)) @filter(between(age, \"18\", \"30\")) {",
+		},
+		{
+			name:    "uid_in with several uids",
+			version: Version21_3,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "uid_in", Attr: "school", Args: []gql.Arg{{Value: "0x1"}, {Value: "0x2"}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: "me (func: uid_in(school, [0x1, 0x2])) {",
+		},
+		{
+			name:    "uid_in with uid variable",
+			version: Version21_3,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "has", Attr: "name"},
+				Filter:   &gql.FilterTree{Func: &gql.Function{Name: "uid_in", Attr: "school", NeedsVar: []gql.VarContext{{Name: "s", Typ: 1}}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: "me (func: has(name)) @filter(uid_in(school, uid(s))) {",
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			res, err := Render(Query{Queries: []gql.GraphQuery{e.query}}, WithVersion(e.version))
+			require.NoError(t, err)
+			require.Contains(t, res, e.expected)
+		})
+	}
+}
+
+func TestRenderUnsupportedFeature(t *testing.T) {
+	name := gql.GraphQuery{Attr: "name"}
+	table := []struct {
+		name     string
+		version  Version
+		query    gql.GraphQuery
+		expected error
+	}{
+		{
+			name:    "type before v1.1",
+			version: Version1_0,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "type", Args: []gql.Arg{{Value: "Person"}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: UnsupportedFeatureError{Feature: "type()", Version: Version1_0},
+		},
+		{
+			name:    "expand type before v1.1",
+			version: Version1_0,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				UID:      []uint64{1},
+				Func:     &gql.Function{Name: "uid"},
+				Children: []gql.GraphQuery{{Attr: "expand", Expand: "Person"}},
+			},
+			expected: UnsupportedFeatureError{Feature: "expand(Type)", Version: Version1_0},
+		},
+		{
+			name:    "expand forward since v1.1",
+			version: Version1_1,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				UID:      []uint64{1},
+				Func:     &gql.Function{Name: "uid"},
+				Children: []gql.GraphQuery{{Attr: "expand", Expand: "_forward_"}},
+			},
+			expected: UnsupportedFeatureError{Feature: "expand(_forward_)", Version: Version1_1},
+		},
+		{
+			name:    "nested cascade before v1.1",
+			version: Version1_0,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				UID:      []uint64{1},
+				Func:     &gql.Function{Name: "uid"},
+				Children: []gql.GraphQuery{{Attr: "friend", Cascade: true, Children: []gql.GraphQuery{name}}},
+			},
+			expected: UnsupportedFeatureError{Feature: "nested @cascade", Version: Version1_0},
+		},
+		{
+			name:    "cascade fields before v20.07",
+			version: Version20_3,
+			query: gql.GraphQuery{
+				Alias:         "me",
+				UID:           []uint64{1},
+				Func:          &gql.Function{Name: "uid"},
+				Cascade:       true,
+				CascadeFields: []string{"name"},
+				Children:      []gql.GraphQuery{name},
+			},
+			expected: UnsupportedFeatureError{Feature: "@cascade(fields)", Version: Version20_3},
+		},
+		{
+			name:    "between at root before v20.07",
+			version: Version1_2,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "between", Attr: "age", Args: []gql.Arg{{Value: "18"}, {Value: "30"}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: UnsupportedFeatureError{Feature: "between() at root", Version: Version1_2},
+		},
+		{
+			name:    "uid_in with several uids before v21.03",
+			version: Version20_7,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "uid_in", Attr: "school", Args: []gql.Arg{{Value: "0x1"}, {Value: "0x2"}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: UnsupportedFeatureError{Feature: "uid_in() with several uids", Version: Version20_7},
+		},
+		{
+			name:    "uid_in with invalid uid",
+			version: Version21_3,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "uid_in", Attr: "school", Args: []gql.Arg{{Value: "0x1) { secret"}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: InvalidLiteralError{Kind: "uid", Value: "0x1) { secret", Reason: "expected a uid"},
+		},
+		{
+			name:    "recurse loop without depth",
+			version: Version1_0,
+			query: gql.GraphQuery{
+				Alias:       "me",
+				UID:         []uint64{1},
+				Func:        &gql.Function{Name: "uid"},
+				Recurse:     true,
+				RecurseArgs: gql.RecurseArgs{AllowLoop: true},
+				Children:    []gql.GraphQuery{name},
+			},
+			expected: InvalidLiteralError{Kind: "recurse depth", Value: "0", Reason: "a depth is required if loops are allowed"},
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			_, err := Render(Query{Queries: []gql.GraphQuery{e.query}}, WithVersion(e.version))
+			require.Equal(t, e.expected, err)
+		})
+	}
+}
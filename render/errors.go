@@ -0,0 +1,47 @@
+package render
+
+import "fmt"
+
+// InvalidIdentifierError is returned when a name used in a query, e.g.
+// a predicate, alias or variable, is not a valid GraphQL+- identifier.
+type InvalidIdentifierError struct {
+	Kind  string
+	Value string
+}
+
+func (e InvalidIdentifierError) Error() string {
+	return fmt.Sprintf("invalid %s %q", e.Kind, e.Value)
+}
+
+// InvalidLiteralError is returned when a literal can't be rendered
+// without changing its meaning.
+type InvalidLiteralError struct {
+	Kind   string
+	Value  string
+	Reason string
+}
+
+func (e InvalidLiteralError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Kind, e.Value, e.Reason)
+}
+
+// UndefinedVariableError is returned when a mutation of an upsert block
+// references a variable the query doesn't define.
+type UndefinedVariableError struct {
+	Name string
+}
+
+func (e UndefinedVariableError) Error() string {
+	return fmt.Sprintf("variable %q is not defined in the query", e.Name)
+}
+
+// UnsupportedFeatureError is returned when a query uses a feature the
+// Dgraph version it is rendered for doesn't support.
+type UnsupportedFeatureError struct {
+	Feature string
+	Version Version
+}
+
+func (e UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("%s is not supported by dgraph %s", e.Feature, e.Version)
+}
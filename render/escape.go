@@ -0,0 +1,69 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+var stringEscapes = map[rune]string{
+	'"':  `\"`,
+	'\\': `\\`,
+	'\n': `\n`,
+	'\r': `\r`,
+	'\t': `\t`,
+	'\b': `\b`,
+	'\f': `\f`,
+	'\v': `\v`,
+}
+
+// quote renders s as a string literal the GraphQL+- lexer reads back
+// unchanged.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		if esc, ok := stringEscapes[r]; ok {
+			b.WriteString(esc)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+func quoteMeta(s string) string {
+	return strings.Replace(s, "/", "\\/", -1)
+}
+
+// checkRegexp makes sure the regular expression survives being
+// wrapped in slashes. The lexer treats a backslash as escaping the
+// next character and the parser turns every `\/` back into `/`, so a
+// trailing backslash or a backslash in front of a slash can't be
+// represented.
+func checkRegexp(expr, flags string) error {
+	runes := []rune(expr)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\\' {
+			continue
+		}
+
+		if i+1 == len(runes) {
+			return InvalidLiteralError{Kind: "regexp", Value: expr, Reason: "trailing backslash"}
+		}
+
+		if runes[i+1] == '/' {
+			return InvalidLiteralError{Kind: "regexp", Value: expr, Reason: `escaped slash, use a plain "/" instead`}
+		}
+		i++
+	}
+
+	for _, r := range flags {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return InvalidLiteralError{Kind: "regexp flags", Value: flags, Reason: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	return nil
+}
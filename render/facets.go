@@ -13,11 +13,11 @@ func encodeFacetVar(query gql.GraphQuery) []string {
 	}
 
 	res := []string{}
-	for k, v := range query.FacetVar {
+	for _, k := range sortedKeys(query.FacetVar) {
 		if k == query.FacetOrder {
 			continue
 		}
-		res = append(res, fmt.Sprintf("%s as %s", v, k))
+		res = append(res, fmt.Sprintf("%s as %s", query.FacetVar[k], k))
 	}
 
 	return res
@@ -12,57 +12,60 @@ func renderFilter(filter *gql.FilterTree) string {
 		return ""
 	}
 
-	if filter.Op != "" {
-		return fmt.Sprintf("@filter(%s)", renderFilterTree(*filter))
+	if filter.Op == "" && filter.Func == nil {
+		return ""
 	}
 
-	if filter.Func == nil {
-		return ""
+	return fmt.Sprintf("@filter(%s)", renderFilterTree(*filter))
+}
+
+func renderFilterfunc(fn *gql.Function) string {
+	if res, ok := renderSpecialFunc(fn); ok {
+		return res
 	}
 
-	if len(filter.Func.UID) != 0 && len(filter.Func.Args) == 0 {
-		uids := make([]string, 0, len(filter.Func.UID))
-		for _, e := range filter.Func.UID {
+	if len(fn.UID) != 0 && len(fn.Args) == 0 {
+		uids := make([]string, 0, len(fn.UID))
+		for _, e := range fn.UID {
 			uids = append(uids, fmt.Sprintf("%d", e))
 		}
 
-		return fmt.Sprintf("@filter(%s(%s))", filter.Func.Name, strings.Join(uids, ", "))
+		return fmt.Sprintf("%s(%s)", fn.Name, strings.Join(uids, ", "))
 	}
 
-	if filter.Func.Attr != "" && len(filter.Func.Args) == 0 {
-		return fmt.Sprintf("@filter(%s(%s))", filter.Func.Name, filter.Func.Attr)
+	if fn.Attr != "" && len(fn.Args) == 0 {
+		return fmt.Sprintf("%s(%s)", fn.Name, formatAttribute(fn.Attr))
 	}
 
-	if len(filter.Func.Args) == 0 {
-		return fmt.Sprintf("@filter(%s(%s))", filter.Func.Name, strings.Join(encodeNeedVar(filter.Func.NeedsVar, true), ","))
+	if len(fn.Args) == 0 {
+		return fmt.Sprintf("%s(%s)", fn.Name, strings.Join(encodeNeedVar(fn.NeedsVar, true), ","))
 	}
 
-	if filter.Func.Attr != "" {
-		arguments, _ := encodeArgs(filter.Func)
-		attr := formatAttribute(filter.Func.Attr)
-		if filter.Func.IsValueVar {
-			attr = fmt.Sprintf("val(%s)", formatAttribute(filter.Func.Attr))
+	if fn.Attr != "" {
+		arguments, _ := encodeArgs(fn)
+		attr := formatAttribute(fn.Attr)
+		if fn.IsValueVar {
+			attr = fmt.Sprintf("val(%s)", formatAttribute(fn.Attr))
 		}
 
-		if filter.Func.IsCount {
-			return fmt.Sprintf("@filter(%s(count(%s), %s))", filter.Func.Name, attr, arguments)
+		if fn.IsCount {
+			return fmt.Sprintf("%s(count(%s), %s)", fn.Name, attr, arguments)
 		}
 
 		langs := []string{}
-		if filter.Func.Lang != "" {
-			langs = []string{filter.Func.Lang}
+		if fn.Lang != "" {
+			langs = []string{fn.Lang}
 		}
 
-		return fmt.Sprintf("@filter(%s(%s%s, %s))", filter.Func.Name, attr, renderLangs(langs), arguments)
+		return fmt.Sprintf("%s(%s%s, %s)", fn.Name, attr, renderLangs(langs), arguments)
 	}
 
 	return fmt.Sprintf(
-		"@filter(%s(%s, %s))",
-		filter.Func.Name,
-		strings.Join(encodeNeedVar(filter.Func.NeedsVar, true), ","),
-		strings.Join(encodeFilterArgs(filter.Func.Args), ","),
+		"%s(%s, %s)",
+		fn.Name,
+		strings.Join(encodeNeedVar(fn.NeedsVar, true), ","),
+		strings.Join(encodeFilterArgs(fn.Args), ","),
 	)
-
 }
 
 func encodeFilterArgs(args []gql.Arg) []string {
@@ -74,7 +77,15 @@ func encodeFilterArgs(args []gql.Arg) []string {
 }
 
 func encodeFilterArg(arg gql.Arg) string {
-	return arg.Value
+	if arg.IsValueVar {
+		return fmt.Sprintf("val(%s)", arg.Value)
+	}
+
+	if arg.IsGraphQLVar {
+		return arg.Value
+	}
+
+	return quote(arg.Value)
 }
 
 func encodeNeedVar(varContexts []gql.VarContext, annotate bool) []string {
@@ -90,15 +101,6 @@ func encodeNeedVar(varContexts []gql.VarContext, annotate bool) []string {
 	return s
 }
 
-func renderFilterfunc(fn *gql.Function) string {
-	args := []string{fn.Attr}
-	for _, e := range fn.Args {
-		args = append(args, fmt.Sprintf(`"%s"`, e.Value))
-	}
-
-	return fmt.Sprintf(`%s(%s)`, fn.Name, strings.Join(args, ", "))
-}
-
 func renderSingleArgFunction(tree gql.FilterTree) (string, bool) {
 	for _, e := range []string{"not", "eq"} {
 		args := []string{}
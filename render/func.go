@@ -8,7 +8,7 @@ import (
 )
 
 func renderCheckPwd(query gql.GraphQuery) string {
-	return fmt.Sprintf(`checkpwd(%s, "%s")`, formatAttribute(query.Func.Attr), query.Func.Args[0].Value)
+	return fmt.Sprintf(`checkpwd(%s, %s)`, formatAttribute(query.Func.Attr), quote(query.Func.Args[0].Value))
 }
 
 func renderFunc(query gql.GraphQuery) string {
@@ -34,12 +34,7 @@ func renderFuncArgs(query gql.GraphQuery) string {
 		return ""
 	}
 
-	res := make([]string, 0, len(query.Args))
-	for k, v := range query.Args {
-		res = append(res, fmt.Sprintf("%s: %s", k, v))
-	}
-
-	return fmt.Sprintf(", %s", strings.Join(res, ","))
+	return fmt.Sprintf(", %s", strings.Join(encodeArgMap(query.Args), ","))
 }
 
 func isInternal(query gql.GraphQuery) bool {
@@ -83,10 +78,6 @@ func encodeUID(query gql.GraphQuery) (string, bool) {
 	return strings.Join(res, ", "), true
 }
 
-func quoteMeta(s string) string {
-	return strings.Replace(s, "/", "\\/", -1)
-}
-
 func encodeArgs(f *gql.Function) (string, bool) {
 	if len(f.Args) == 0 {
 		return "", false
@@ -113,12 +104,12 @@ func encodeArgs(f *gql.Function) (string, bool) {
 			continue
 		}
 
-		if e.IsGraphQLVar || f.IsValueVar {
+		if e.IsGraphQLVar {
 			res = append(res, e.Value)
 			continue
 		}
 
-		res = append(res, fmt.Sprintf(`"%s"`, e.Value))
+		res = append(res, quote(e.Value))
 	}
 
 	if f.Name == "regexp" {
@@ -145,6 +136,12 @@ func renderArguments(query gql.GraphQuery) string {
 }
 
 func renderFuncBody(query gql.GraphQuery) string {
+	if query.Attr == "" && len(query.UID) == 0 {
+		if fn, ok := renderSpecialFunc(query.Func); ok {
+			return fn
+		}
+	}
+
 	arguments := renderArguments(query)
 
 	if query.Attr != "" && arguments == "" {
@@ -0,0 +1,369 @@
+package render
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"mooncamp.com/dgraphtools/gql"
+)
+
+var (
+	argValuePattern     = regexp.MustCompile(`^\$?[a-zA-Z0-9_.+\-]+$`)
+	variableTypePattern = regexp.MustCompile(`^\[?[a-zA-Z]+\]?!?(\s*=\s*(-?[0-9.]+|true|false|"[^"\\]*"))?$`)
+	filterOps           = map[string]bool{"": true, "and": true, "or": true, "not": true}
+	mathFunctions       = map[string]bool{
+		"u-": true, "floor": true, "ceil": true, "since": true, "exp": true, "ln": true, "sqrt": true,
+		"cond": true, "pow": true, "logbase": true, "max": true, "min": true,
+		"/": true, "*": true, "%": true, "-": true, "+": true,
+		"<": true, ">": true, "<=": true, ">=": true, "==": true, "!=": true,
+	}
+)
+
+func isNameBegin(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_' || r == '~'
+}
+
+func isNameSuffix(r rune) bool {
+	return isNameBegin(r) || (r >= '0' && r <= '9') || r == '.'
+}
+
+// isName reports whether s lexes as a single name. Dashes are allowed
+// between name parts, e.g. `first-name`.
+func isName(s string) bool {
+	if s == "" || strings.HasSuffix(s, "-") {
+		return false
+	}
+
+	for i, r := range s {
+		if i == 0 {
+			if !isNameBegin(r) {
+				return false
+			}
+			continue
+		}
+
+		if !isNameSuffix(r) && r != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isIRI(s string) bool {
+	for _, r := range s {
+		if r <= ' ' || strings.ContainsRune("<>\"{}|^`\\", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isLang(s string) bool {
+	if s == "." {
+		return true
+	}
+
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if !isNameBegin(r) && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func checkName(kind, s string) error {
+	if !isName(s) {
+		return InvalidIdentifierError{Kind: kind, Value: s}
+	}
+	return nil
+}
+
+func checkOptionalName(kind, s string) error {
+	if s == "" {
+		return nil
+	}
+	return checkName(kind, s)
+}
+
+func checkAttribute(kind, attr string) error {
+	if attr == "" {
+		return nil
+	}
+
+	u, err := url.Parse(attr)
+	if err == nil && u.Scheme != "" {
+		if !isIRI(attr) {
+			return InvalidIdentifierError{Kind: kind, Value: attr}
+		}
+		return nil
+	}
+
+	return checkName(kind, attr)
+}
+
+func checkLangs(langs []string) error {
+	for _, e := range langs {
+		if !isLang(e) {
+			return InvalidIdentifierError{Kind: "language", Value: e}
+		}
+	}
+	return nil
+}
+
+func checkVarContexts(vc []gql.VarContext) error {
+	for _, e := range vc {
+		if err := checkName("variable", e.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkQuery makes sure every identifier and raw value of the query can
+// be rendered without altering the structure of the query.
+func checkQuery(query Query) error {
+	if err := checkOptionalName("query name", query.Alias); err != nil {
+		return err
+	}
+
+	for _, k := range sortedKeys(query.Variables) {
+		v := query.Variables[k]
+		if !strings.HasPrefix(k, "$") || !isName(k[1:]) {
+			return InvalidIdentifierError{Kind: "graphql variable", Value: k}
+		}
+
+		if !variableTypePattern.MatchString(v) {
+			return InvalidIdentifierError{Kind: "graphql variable type", Value: v}
+		}
+	}
+
+	for _, e := range query.Queries {
+		if err := checkGraphQuery(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkGraphQuery(gq gql.GraphQuery) error {
+	checks := []error{
+		checkAttribute("attribute", gq.Attr),
+		checkLangs(gq.Langs),
+		checkOptionalName("alias", gq.Alias),
+		checkOptionalName("alias", gq.UidCountAlias),
+		checkOptionalName("variable", gq.Var),
+		checkOptionalName("expand", gq.Expand),
+		checkVarContexts(gq.NeedsVar),
+		checkFunction(gq.Func),
+		checkArgMap(gq.Args),
+		checkOrders(gq.Order),
+		checkFilterTree(gq.Filter),
+		checkMathTree(gq.MathExp),
+		checkFacets(gq),
+		checkFilterTree(gq.FacetsFilter),
+		checkGroupBy(gq.GroupbyAttrs),
+		checkCascadeFields(gq.CascadeFields),
+	}
+
+	for _, e := range checks {
+		if e != nil {
+			return e
+		}
+	}
+
+	for _, e := range gq.Children {
+		if err := checkGraphQuery(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkFunction(fn *gql.Function) error {
+	if fn == nil {
+		return nil
+	}
+
+	if err := checkName("function", fn.Name); err != nil {
+		return err
+	}
+
+	if fn.IsValueVar {
+		if err := checkName("variable", fn.Attr); err != nil {
+			return err
+		}
+	} else if err := checkAttribute("attribute", fn.Attr); err != nil {
+		return err
+	}
+
+	if fn.Lang != "" {
+		if err := checkLangs([]string{fn.Lang}); err != nil {
+			return err
+		}
+	}
+
+	if err := checkVarContexts(fn.NeedsVar); err != nil {
+		return err
+	}
+
+	for _, e := range fn.Args {
+		switch {
+		case e.IsValueVar:
+			if err := checkName("variable", e.Value); err != nil {
+				return err
+			}
+		case e.IsGraphQLVar:
+			if !strings.HasPrefix(e.Value, "$") || !isName(e.Value[1:]) {
+				return InvalidIdentifierError{Kind: "graphql variable", Value: e.Value}
+			}
+		}
+	}
+
+	if fn.Name == "regexp" && len(fn.Args) > 0 {
+		flags := ""
+		if len(fn.Args) > 1 {
+			flags = fn.Args[1].Value
+		}
+		return checkRegexp(fn.Args[0].Value, flags)
+	}
+
+	return nil
+}
+
+func checkArgMap(args map[string]string) error {
+	for _, k := range sortedKeys(args) {
+		v := args[k]
+		if err := checkName("argument", k); err != nil {
+			return err
+		}
+
+		if !argValuePattern.MatchString(v) {
+			return InvalidLiteralError{Kind: "argument", Value: v, Reason: "expected a number, uid or graphql variable"}
+		}
+	}
+	return nil
+}
+
+func checkOrders(orders []gql.Order) error {
+	for _, e := range orders {
+		if err := checkAttribute("order attribute", e.Attr); err != nil {
+			return err
+		}
+
+		if err := checkLangs(e.Langs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkFilterTree(ft *gql.FilterTree) error {
+	if ft == nil {
+		return nil
+	}
+
+	if !filterOps[ft.Op] {
+		return InvalidIdentifierError{Kind: "filter operator", Value: ft.Op}
+	}
+
+	if err := checkFunction(ft.Func); err != nil {
+		return err
+	}
+
+	for i := range ft.Child {
+		if err := checkFilterTree(&ft.Child[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkMathTree(mt *gql.MathTree) error {
+	if mt == nil {
+		return nil
+	}
+
+	if mt.Fn != "" && !mathFunctions[mt.Fn] {
+		return InvalidIdentifierError{Kind: "math function", Value: mt.Fn}
+	}
+
+	if err := checkOptionalName("variable", mt.Var); err != nil {
+		return err
+	}
+
+	switch mt.Const.Value.(type) {
+	case nil, float64, float32, int, int32, int64, uint, uint32, uint64:
+	default:
+		return InvalidLiteralError{Kind: "math constant", Value: fmt.Sprintf("%v", mt.Const.Value), Reason: "expected a number"}
+	}
+
+	for i := range mt.Child {
+		if err := checkMathTree(&mt.Child[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkFacets(gq gql.GraphQuery) error {
+	if gq.Facets != nil {
+		for _, e := range gq.Facets.Param {
+			if err := checkName("facet", e.Key); err != nil {
+				return err
+			}
+
+			if err := checkOptionalName("facet alias", e.Alias); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, k := range sortedKeys(gq.FacetVar) {
+		if err := checkName("facet", k); err != nil {
+			return err
+		}
+
+		if err := checkName("variable", gq.FacetVar[k]); err != nil {
+			return err
+		}
+	}
+
+	return checkOptionalName("facet", gq.FacetOrder)
+}
+
+func checkGroupBy(attrs []gql.GroupByAttr) error {
+	for _, e := range attrs {
+		if err := checkAttribute("group by attribute", e.Attr); err != nil {
+			return err
+		}
+
+		if err := checkOptionalName("group by alias", e.Alias); err != nil {
+			return err
+		}
+
+		if err := checkLangs(e.Langs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkCascadeFields(fields []string) error {
+	for _, e := range fields {
+		if err := checkAttribute("cascade field", e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
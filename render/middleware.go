@@ -4,36 +4,27 @@ import (
 	"context"
 	"fmt"
 
+	"mooncamp.com/dgraphtools"
 	"mooncamp.com/dgraphtools/gql"
 
-	dgraphgql "github.com/dgraph-io/dgraph/gql"
-
 	"github.com/go-kit/kit/endpoint"
-	"github.com/stretchr/testify/assert"
 )
 
-func nullDefault(gq gql.GraphQuery) gql.GraphQuery {
-	gq.Default = nil
-
-	if gq.Children == nil {
-		return gq
-	}
-
-	children := make([]gql.GraphQuery, 0, len(gq.Children))
-	for _, e := range gq.Children {
-		children = append(children, nullDefault(e))
-	}
-	gq.Children = children
-	return gq
-}
-
-func TemplateErrorMiddleware(queryReader func(request interface{}) Query, errFormatter func(err error) interface{}) endpoint.Middleware {
+// TemplateErrorMiddleware rejects queries that don't survive rendering
+// and parsing unchanged. The options must match the ones the query is
+// rendered with later on, e.g. the version of Dgraph.
+func TemplateErrorMiddleware(queryReader func(request interface{}) Query, errFormatter func(err error) interface{}, opts ...Option) endpoint.Middleware {
+	o := newOptions(opts)
 	return func(next endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 			query := queryReader(request)
-			q, err := Render(query)
+			if errs := gql.Validate(query.Queries); len(errs) > 0 {
+				return errFormatter(dgraphtools.InvalidRequest{Path: errs[0].Path, Err: errs}), nil
+			}
+
+			q, err := Render(query, opts...)
 			if err != nil {
-				return errFormatter(err), nil
+				return errFormatter(dgraphtools.InvalidRequest{Err: err}), nil
 			}
 
 			gqlVariables := make(map[string]string, len(query.Variables))
@@ -41,22 +32,21 @@ func TemplateErrorMiddleware(queryReader func(request interface{}) Query, errFor
 				gqlVariables[k] = k
 			}
 
-			parseResult, err := dgraphgql.Parse(dgraphgql.Request{Str: q, Variables: gqlVariables})
+			expected, err := gql.Parse(gql.Request{Str: q, Variables: gqlVariables})
 			if err != nil {
-				return errFormatter(err), nil
-			}
-
-			actual := make([]gql.GraphQuery, len(query.Queries))
-			copy(actual, query.Queries)
-			for i := range actual {
-				actual[i] = nullDefault(actual[i])
+				return errFormatter(dgraphtools.InternalError{Err: err}), nil
 			}
 
-			expected := gql.DecodeGraphQueries(parseResult.Query)
+			// Features like between() are rewritten for older versions, so
+			// the rewritten query is what comes back from parsing.
+			actual := gql.StripExtensions(lowerQuery(query, o.version).Queries)
 
-			if !assert.ObjectsAreEqual(expected, actual) {
+			// The client sent a query that can't be expressed in
+			// GraphQL+-, e.g. a predicate marked as internal.
+			if !gql.EqualQueries(expected, actual) {
 				d := diff(expected, actual)
-				return errFormatter(fmt.Errorf("parsing difference: %s", d)), nil
+				path := differingBlock("queries", expected, actual)
+				return errFormatter(dgraphtools.InvalidRequest{Path: path, Err: fmt.Errorf("parsing difference: %s", d)}), nil
 			}
 
 			return next(ctx, request)
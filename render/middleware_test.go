@@ -0,0 +1,113 @@
+package render
+
+import (
+	"context"
+	"testing"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateErrorMiddleware(t *testing.T) {
+	name := gql.GraphQuery{Attr: "name"}
+	between := &gql.FilterTree{Func: &gql.Function{Name: "between", Attr: "age", Args: []gql.Arg{{Value: "18"}, {Value: "30"}}}}
+
+	table := []struct {
+		name     string
+		version  Version
+		query    gql.GraphQuery
+		expected error
+	}{
+		{
+			name:    "new syntax",
+			version: Version21_3,
+			query: gql.GraphQuery{
+				Alias:         "me",
+				Func:          &gql.Function{Name: "type", Args: []gql.Arg{{Value: "Person"}}},
+				Cascade:       true,
+				CascadeFields: []string{"name"},
+				Filter: &gql.FilterTree{Op: "and", Child: []gql.FilterTree{
+					{Func: &gql.Function{Name: "uid_in", Attr: "friend", Args: []gql.Arg{{Value: "0x1"}, {Value: "0x2"}}}},
+					*between,
+				}},
+				Children: []gql.GraphQuery{
+					name,
+					{Attr: "friend", Cascade: true, Children: []gql.GraphQuery{name}},
+					{Attr: "expand", Expand: "Person", IsInternal: true},
+				},
+			},
+		},
+		{
+			name:    "between at root",
+			version: Version20_7,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "between", Attr: "age", Args: []gql.Arg{{Value: "18"}, {Value: "30"}}},
+				Children: []gql.GraphQuery{name},
+			},
+		},
+		{
+			name:    "between rewritten",
+			version: Version1_1,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "has", Attr: "name"},
+				Filter:   between,
+				Children: []gql.GraphQuery{name},
+			},
+		},
+		{
+			name:    "unsupported",
+			version: DefaultVersion,
+			query: gql.GraphQuery{
+				Alias:    "me",
+				Func:     &gql.Function{Name: "type", Args: []gql.Arg{{Value: "Person"}}},
+				Children: []gql.GraphQuery{name},
+			},
+			expected: dgraphtools.InvalidRequest{Err: UnsupportedFeatureError{Feature: "type()", Version: DefaultVersion}},
+		},
+	}
+
+	next := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	queryReader := func(request interface{}) Query {
+		return request.(Query)
+	}
+	errFormatter := func(err error) interface{} {
+		return err
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			ep := TemplateErrorMiddleware(queryReader, errFormatter, WithVersion(e.version))(next)
+			res, err := ep(context.Background(), Query{Queries: []gql.GraphQuery{e.query}})
+			require.NoError(t, err)
+			if e.expected == nil {
+				require.Nil(t, res)
+				return
+			}
+			require.Equal(t, e.expected, res)
+		})
+	}
+
+	t.Run("parsing difference", func(t *testing.T) {
+		query := gql.GraphQuery{
+			Alias: "me",
+			UID:   []uint64{1},
+			Func:  &gql.Function{Name: "uid"},
+			Children: []gql.GraphQuery{
+				name,
+				{Attr: "friend", Children: []gql.GraphQuery{name, {Attr: "age", IsInternal: true}}},
+			},
+		}
+
+		ep := TemplateErrorMiddleware(queryReader, errFormatter)(next)
+		res, err := ep(context.Background(), Query{Queries: []gql.GraphQuery{query}})
+		require.NoError(t, err)
+		require.IsType(t, dgraphtools.InvalidRequest{}, res)
+		require.Equal(t, "queries[0].children[1].children[1]", res.(dgraphtools.InvalidRequest).Path)
+	})
+}
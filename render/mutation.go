@@ -0,0 +1,316 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"mooncamp.com/dgraphtools/gql"
+)
+
+var (
+	varRefPattern    = regexp.MustCompile(`^(uid|val)\((.+)\)$`)
+	uidPattern       = regexp.MustCompile(`^(0x[0-9a-fA-F]+|[0-9]+)$`)
+	blankNodePattern = regexp.MustCompile(`^_:[a-zA-Z0-9_]([a-zA-Z0-9_.\-]*[a-zA-Z0-9_\-])?$`)
+	datetimePattern  = regexp.MustCompile(`^[0-9]{4}(-[0-9]{2}(-[0-9]{2}(T[0-9]{2}(:[0-9]{2}(:[0-9]{2}(\.[0-9]+)?)?)?(Z|[+\-][0-9]{2}:[0-9]{2})?)?)?)?$`)
+	rdfTypes         = map[string]bool{
+		"xs:password": true, "xs:string": true, "xs:date": true, "xs:dateTime": true, "xs:int": true,
+		"xs:positiveInteger": true, "xs:boolean": true, "xs:double": true, "xs:float": true, "xs:base64Binary": true,
+	}
+)
+
+// RenderMutation renders the set and delete parts of a mutation as
+// N-Quads, one per line.
+func RenderMutation(m gql.Mutation) (set, del string, err error) {
+	if set, err = renderNQuads(m.Set); err != nil {
+		return "", "", err
+	}
+
+	if del, err = renderNQuads(m.Delete); err != nil {
+		return "", "", err
+	}
+
+	return set, del, nil
+}
+
+func renderNQuads(nquads []gql.NQuad) (string, error) {
+	lines := make([]string, 0, len(nquads))
+	for _, e := range nquads {
+		if err := checkNQuad(e); err != nil {
+			return "", err
+		}
+		lines = append(lines, renderNQuad(e))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func renderNQuad(nq gql.NQuad) string {
+	parts := []string{renderNode(nq.Subject), renderNode(nq.Predicate)}
+
+	switch {
+	case nq.ObjectID != "":
+		parts = append(parts, renderNode(nq.ObjectID))
+	case nq.Lang != "":
+		parts = append(parts, fmt.Sprintf("%s@%s", quote(nq.ObjectValue), nq.Lang))
+	case nq.ObjectType != "":
+		parts = append(parts, fmt.Sprintf("%s^^<%s>", quote(nq.ObjectValue), nq.ObjectType))
+	default:
+		parts = append(parts, quote(nq.ObjectValue))
+	}
+
+	if len(nq.Facets) != 0 {
+		facets := make([]string, 0, len(nq.Facets))
+		for _, e := range nq.Facets {
+			facets = append(facets, fmt.Sprintf("%s=%s", e.Key, renderFacetValue(e)))
+		}
+		parts = append(parts, fmt.Sprintf("(%s)", strings.Join(facets, ", ")))
+	}
+
+	return fmt.Sprintf("%s .", strings.Join(parts, " "))
+}
+
+func renderNode(s string) string {
+	if s == gql.Star || strings.HasPrefix(s, "_:") || varRefPattern.MatchString(s) {
+		return s
+	}
+	return fmt.Sprintf("<%s>", s)
+}
+
+func renderFacetValue(f gql.Facet) string {
+	if f.ValType == gql.FacetString {
+		return quote(f.Value)
+	}
+	return f.Value
+}
+
+// isUID reports whether s is a hex or decimal uid. Other forms
+// ParseUint accepts, like "1_000" or "0o7", aren't uids to Dgraph.
+func isUID(s string) bool {
+	if !uidPattern.MatchString(s) {
+		return false
+	}
+	_, err := strconv.ParseUint(s, 0, 64)
+	return err == nil
+}
+
+// varRef returns the function, uid or val, and the variable name of a
+// variable reference like `uid(v)`.
+func varRef(s string) (string, string, bool) {
+	m := varRefPattern.FindStringSubmatch(s)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+func checkNode(kind, s string) error {
+	if s == gql.Star || isUID(s) || blankNodePattern.MatchString(s) {
+		return nil
+	}
+
+	if fn, name, ok := varRef(s); ok && (fn == "uid" || kind == "object") {
+		return checkName("variable", name)
+	}
+
+	return InvalidIdentifierError{Kind: kind, Value: s}
+}
+
+func checkNQuad(nq gql.NQuad) error {
+	if err := checkNode("subject", nq.Subject); err != nil {
+		return err
+	}
+
+	if nq.Predicate != gql.Star {
+		if nq.Predicate == "" {
+			return InvalidIdentifierError{Kind: "predicate", Value: nq.Predicate}
+		}
+
+		if err := checkAttribute("predicate", nq.Predicate); err != nil {
+			return err
+		}
+	}
+
+	if (nq.Subject == gql.Star || nq.Predicate == gql.Star) && nq.ObjectID != gql.Star {
+		return InvalidLiteralError{Kind: "object", Value: nq.ObjectID + nq.ObjectValue, Reason: "star subjects and predicates need a star object"}
+	}
+
+	if nq.ObjectID != "" {
+		if nq.ObjectValue != "" || nq.ObjectType != "" || nq.Lang != "" {
+			return InvalidLiteralError{Kind: "object", Value: nq.ObjectID, Reason: "an object can't be both a node and a value"}
+		}
+
+		if err := checkNode("object", nq.ObjectID); err != nil {
+			return err
+		}
+	}
+
+	if nq.Lang != "" && nq.ObjectType != "" {
+		return InvalidLiteralError{Kind: "object type", Value: nq.ObjectType, Reason: "values with a language are always strings"}
+	}
+
+	if nq.Lang != "" && (nq.Lang == "." || !isLang(nq.Lang)) {
+		return InvalidIdentifierError{Kind: "language", Value: nq.Lang}
+	}
+
+	if nq.ObjectType != "" && !rdfTypes[nq.ObjectType] {
+		return InvalidIdentifierError{Kind: "object type", Value: nq.ObjectType}
+	}
+
+	for _, e := range nq.Facets {
+		if err := checkFacet(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkFacet(f gql.Facet) error {
+	if err := checkName("facet", f.Key); err != nil {
+		return err
+	}
+
+	valid := true
+	switch f.ValType {
+	case gql.FacetString:
+	case gql.FacetInt:
+		_, err := strconv.ParseInt(f.Value, 0, 64)
+		valid = err == nil
+	case gql.FacetFloat:
+		_, err := strconv.ParseFloat(f.Value, 64)
+		_, isInt := strconv.ParseInt(f.Value, 0, 64)
+		valid = err == nil && isInt != nil
+	case gql.FacetBool:
+		valid = f.Value == "true" || f.Value == "false"
+	case gql.FacetDateTime:
+		valid = datetimePattern.MatchString(f.Value)
+	default:
+		valid = false
+	}
+
+	if !valid {
+		return InvalidLiteralError{Kind: "facet value", Value: f.Value, Reason: fmt.Sprintf("not a valid value of type %d", f.ValType)}
+	}
+
+	return nil
+}
+
+// RenderMutationJSON renders the set and delete parts of a mutation in
+// dgraph's JSON format, one object per subject. Ints, floats and
+// booleans become JSON numbers and booleans, every other type, as well
+// as datetime facets, is rendered as a string, so parsing the result
+// doesn't restore those types.
+func RenderMutationJSON(m gql.Mutation) (set, del []byte, err error) {
+	if set, err = renderJSONNQuads(m.Set); err != nil {
+		return nil, nil, err
+	}
+
+	if del, err = renderJSONNQuads(m.Delete); err != nil {
+		return nil, nil, err
+	}
+
+	return set, del, nil
+}
+
+func renderJSONNQuads(nquads []gql.NQuad) ([]byte, error) {
+	if len(nquads) == 0 {
+		return nil, nil
+	}
+
+	objects := []map[string]interface{}{}
+	bySubject := map[string]map[string]interface{}{}
+	for _, e := range nquads {
+		if err := checkNQuad(e); err != nil {
+			return nil, err
+		}
+
+		if e.Subject == gql.Star {
+			return nil, InvalidIdentifierError{Kind: "subject", Value: e.Subject}
+		}
+
+		obj, ok := bySubject[e.Subject]
+		if !ok {
+			obj = map[string]interface{}{"uid": e.Subject}
+			bySubject[e.Subject] = obj
+			objects = append(objects, obj)
+		}
+
+		if e.Predicate == gql.Star {
+			continue
+		}
+
+		key := e.Predicate
+		if e.Lang != "" {
+			key = fmt.Sprintf("%s@%s", e.Predicate, e.Lang)
+		}
+
+		var value interface{}
+		switch {
+		case e.ObjectID == gql.Star:
+			value = nil
+		case strings.HasPrefix(e.ObjectID, "val("):
+			value = e.ObjectID
+		case e.ObjectID != "":
+			child := map[string]interface{}{"uid": e.ObjectID}
+			addJSONFacets(child, key, e.Facets)
+			value = child
+		default:
+			v, err := jsonValue(e)
+			if err != nil {
+				return nil, err
+			}
+			addJSONFacets(obj, key, e.Facets)
+			value = v
+		}
+
+		existing, ok := obj[key]
+		switch list, isList := existing.([]interface{}); {
+		case !ok:
+			obj[key] = value
+		case isList:
+			obj[key] = append(list, value)
+		default:
+			obj[key] = []interface{}{existing, value}
+		}
+	}
+
+	return json.Marshal(objects)
+}
+
+func jsonValue(nq gql.NQuad) (interface{}, error) {
+	var v interface{}
+	var err error
+	switch nq.ObjectType {
+	case "xs:int", "xs:positiveInteger":
+		v, err = strconv.ParseInt(nq.ObjectValue, 0, 64)
+	case "xs:float", "xs:double":
+		v, err = strconv.ParseFloat(nq.ObjectValue, 64)
+	case "xs:boolean":
+		v, err = strconv.ParseBool(nq.ObjectValue)
+	default:
+		return nq.ObjectValue, nil
+	}
+
+	if err != nil {
+		return nil, InvalidLiteralError{Kind: nq.ObjectType, Value: nq.ObjectValue, Reason: err.Error()}
+	}
+	return v, nil
+}
+
+func addJSONFacets(obj map[string]interface{}, key string, facets []gql.Facet) {
+	for _, e := range facets {
+		var v interface{} = e.Value
+		switch e.ValType {
+		case gql.FacetInt:
+			v, _ = strconv.ParseInt(e.Value, 0, 64)
+		case gql.FacetFloat:
+			v, _ = strconv.ParseFloat(e.Value, 64)
+		case gql.FacetBool:
+			v = e.Value == "true"
+		}
+		obj[fmt.Sprintf("%s|%s", key, e.Key)] = v
+	}
+}
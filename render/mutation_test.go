@@ -0,0 +1,285 @@
+package render
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"mooncamp.com/dgraphtools/gql"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMutation(t *testing.T) {
+	table := []struct {
+		name     string
+		mutation gql.Mutation
+		set      string
+		del      string
+	}{
+		{
+			name: "values and edges",
+			mutation: gql.Mutation{
+				Set: []gql.NQuad{
+					{Subject: "_:alice", Predicate: "name", ObjectValue: "Alice"},
+					{Subject: "_:alice", Predicate: "name", ObjectValue: "Alicia", Lang: "es"},
+					{Subject: "_:alice", Predicate: "age", ObjectValue: "32", ObjectType: "xs:int"},
+					{Subject: "_:alice", Predicate: "friend", ObjectID: "0x1a", Facets: []gql.Facet{
+						{Key: "close", Value: "true", ValType: gql.FacetBool},
+						{Key: "note", Value: "met at \"work\"\n", ValType: gql.FacetString},
+						{Key: "since", Value: "2006-01-02T15:04:05", ValType: gql.FacetDateTime},
+						{Key: "weight", Value: "0.5", ValType: gql.FacetFloat},
+						{Key: "years", Value: "12", ValType: gql.FacetInt},
+					}},
+				},
+			},
+			set: strings.Join([]string{
+				`_:alice <name> "Alice" .`,
+				`_:alice <name> "Alicia"@es .`,
+				`_:alice <age> "32"^^<xs:int> .`,
+				`_:alice <friend> <0x1a> (close=true, note="met at \"work\"\n", since=2006-01-02T15:04:05, weight=0.5, years=12) .`,
+			}, "\n"),
+		},
+		{
+			name: "star deletes",
+			mutation: gql.Mutation{
+				Delete: []gql.NQuad{
+					{Subject: "0x1", Predicate: gql.Star, ObjectID: gql.Star},
+					{Subject: "0x2", Predicate: "friend", ObjectID: gql.Star},
+					{Subject: "0x2", Predicate: "friend", ObjectID: "0x3"},
+				},
+			},
+			del: strings.Join([]string{
+				`<0x1> * * .`,
+				`<0x2> <friend> * .`,
+				`<0x2> <friend> <0x3> .`,
+			}, "\n"),
+		},
+		{
+			name: "escaping",
+			mutation: gql.Mutation{
+				Set: []gql.NQuad{
+					{Subject: "0x1", Predicate: "name", ObjectValue: "\" . \n<0x1> <admin> \"true"},
+					{Subject: "0x1", Predicate: "path", ObjectValue: `C:\Users\`},
+				},
+			},
+			set: strings.Join([]string{
+				`<0x1> <name> "\" . \n<0x1> <admin> \"true" .`,
+				`<0x1> <path> "C:\\Users\\" .`,
+			}, "\n"),
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			set, del, err := RenderMutation(e.mutation)
+			require.NoError(t, err)
+			require.Equal(t, e.set, set)
+			require.Equal(t, e.del, del)
+
+			parsed, err := gql.ParseMutation(set, del)
+			require.NoError(t, err)
+			require.Equal(t, e.mutation, parsed)
+
+			jsonSet, jsonDel, err := RenderMutationJSON(e.mutation)
+			require.NoError(t, err)
+
+			for _, c := range []struct {
+				data   []byte
+				nquads []gql.NQuad
+			}{{jsonSet, e.mutation.Set}, {jsonDel, e.mutation.Delete}} {
+				if c.data == nil {
+					require.Empty(t, c.nquads)
+					continue
+				}
+
+				parsed, err := gql.ParseJSONMutation(c.data)
+				require.NoError(t, err)
+				require.Equal(t, jsonNormalized(c.nquads), jsonNormalized(parsed), string(c.data))
+			}
+		})
+	}
+}
+
+func TestRenderMutationRejectsInvalid(t *testing.T) {
+	table := []struct {
+		name  string
+		nquad gql.NQuad
+		err   error
+	}{
+		{
+			name:  "subject",
+			nquad: gql.NQuad{Subject: "alice> <admin", Predicate: "name", ObjectValue: "x"},
+			err:   InvalidIdentifierError{Kind: "subject", Value: "alice> <admin"},
+		},
+		{
+			name:  "uid with underscores",
+			nquad: gql.NQuad{Subject: "1_000", Predicate: "name", ObjectValue: "x"},
+			err:   InvalidIdentifierError{Kind: "subject", Value: "1_000"},
+		},
+		{
+			name:  "predicate",
+			nquad: gql.NQuad{Subject: "0x1", Predicate: "name> \"x\" .", ObjectValue: "x"},
+			err:   InvalidIdentifierError{Kind: "predicate", Value: "name> \"x\" ."},
+		},
+		{
+			name:  "language",
+			nquad: gql.NQuad{Subject: "0x1", Predicate: "name", ObjectValue: "x", Lang: "en ."},
+			err:   InvalidIdentifierError{Kind: "language", Value: "en ."},
+		},
+		{
+			name:  "object type",
+			nquad: gql.NQuad{Subject: "0x1", Predicate: "age", ObjectValue: "1", ObjectType: "xs:integer"},
+			err:   InvalidIdentifierError{Kind: "object type", Value: "xs:integer"},
+		},
+		{
+			name: "facet value",
+			nquad: gql.NQuad{Subject: "0x1", Predicate: "friend", ObjectID: "0x2", Facets: []gql.Facet{
+				{Key: "since", Value: "2006) .", ValType: gql.FacetDateTime},
+			}},
+			err: InvalidLiteralError{Kind: "facet value", Value: "2006) .", Reason: "not a valid value of type 4"},
+		},
+		{
+			name:  "star predicate",
+			nquad: gql.NQuad{Subject: "0x1", Predicate: gql.Star, ObjectValue: "x"},
+			err:   InvalidLiteralError{Kind: "object", Value: "x", Reason: "star subjects and predicates need a star object"},
+		},
+	}
+
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			_, _, err := RenderMutation(gql.Mutation{Set: []gql.NQuad{e.nquad}})
+			require.Equal(t, e.err, err)
+		})
+	}
+}
+
+func TestParseJSONMutationRejectsEmptyObjects(t *testing.T) {
+	_, err := gql.ParseJSONMutation([]byte(`[{"uid": "0x1"}, {}]`))
+	require.EqualError(t, err, "empty object")
+
+	parsed, err := gql.ParseJSONMutation([]byte(`{"uid": "0x1"}`))
+	require.NoError(t, err)
+	require.Equal(t, []gql.NQuad{{Subject: "0x1", Predicate: gql.Star, ObjectID: gql.Star}}, parsed)
+}
+
+// jsonNormalized drops what the JSON format can't express, datetime
+// facets and value types other than numbers and booleans, and sorts
+// the N-Quads.
+func jsonNormalized(nquads []gql.NQuad) []gql.NQuad {
+	res := make([]gql.NQuad, 0, len(nquads))
+	for _, e := range nquads {
+		if e.ObjectType != "xs:int" && e.ObjectType != "xs:boolean" {
+			e.ObjectType = ""
+		}
+
+		facets := []gql.Facet{}
+		for _, f := range e.Facets {
+			if f.ValType == gql.FacetDateTime {
+				f.ValType = gql.FacetString
+			}
+			facets = append(facets, f)
+		}
+		e.Facets = nil
+		if len(facets) != 0 {
+			e.Facets = facets
+		}
+
+		res = append(res, e)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Subject+res[i].Predicate < res[j].Subject+res[j].Predicate
+	})
+
+	return res
+}
+
+func TestRenderUpsert(t *testing.T) {
+	query := Query{
+		Queries: []gql.GraphQuery{
+			{
+				Alias: "q",
+				Func:  &gql.Function{Name: "eq", Attr: "email", Args: []gql.Arg{{Value: "deckard@tyrell.com"}}},
+				Children: []gql.GraphQuery{
+					{Attr: "uid", Var: "v"},
+					{Attr: "name", Var: "n"},
+				},
+			},
+		},
+	}
+
+	upsert := Upsert{
+		Query: query,
+		Mutations: []ConditionalMutation{
+			{
+				Cond: &gql.FilterTree{Func: &gql.Function{
+					Name:     "eq",
+					NeedsVar: []gql.VarContext{{Name: "v", Typ: 1}},
+					Args:     []gql.Arg{{Value: "0"}},
+				}},
+				Mutation: gql.Mutation{Set: []gql.NQuad{
+					{Subject: "_:user", Predicate: "email", ObjectValue: "deckard@tyrell.com"},
+				}},
+			},
+			{
+				Cond: &gql.FilterTree{Op: "and", Child: []gql.FilterTree{
+					{Func: &gql.Function{Name: "eq", NeedsVar: []gql.VarContext{{Name: "v", Typ: 1}}, Args: []gql.Arg{{Value: "1"}}}},
+					{Op: "not", Child: []gql.FilterTree{
+						{Func: &gql.Function{Name: "gt", NeedsVar: []gql.VarContext{{Name: "n", Typ: 1}}, Args: []gql.Arg{{Value: "1"}}}},
+					}},
+				}},
+				Mutation: gql.Mutation{
+					Set:    []gql.NQuad{{Subject: "uid(v)", Predicate: "display_name", ObjectID: "val(n)"}},
+					Delete: []gql.NQuad{{Subject: "uid(v)", Predicate: "name", ObjectID: gql.Star}},
+				},
+			},
+		},
+	}
+
+	rendered, err := Render(query)
+	require.NoError(t, err)
+
+	res, err := RenderUpsert(upsert)
+	require.NoError(t, err)
+	require.Equal(t, strings.Join([]string{
+		"upsert {",
+		indentLines(rendered, 2),
+		"",
+		"  mutation @if(eq(len(v), 0)) {",
+		"    set {",
+		`      _:user <email> "deckard@tyrell.com" .`,
+		"    }",
+		"  }",
+		"",
+		"  mutation @if((eq(len(v), 1) and not (gt(len(n), 1)))) {",
+		"    set {",
+		"      uid(v) <display_name> val(n) .",
+		"    }",
+		"    delete {",
+		"      uid(v) <name> * .",
+		"    }",
+		"  }",
+		"}",
+	}, "\n"), res)
+
+	set, del, err := RenderMutation(upsert.Mutations[1].Mutation)
+	require.NoError(t, err)
+	parsed, err := gql.ParseMutation(set, del)
+	require.NoError(t, err)
+	require.Equal(t, upsert.Mutations[1].Mutation, parsed)
+
+	undefined := []ConditionalMutation{
+		{Mutation: gql.Mutation{Set: []gql.NQuad{{Subject: "uid(u)", Predicate: "name", ObjectValue: "x"}}}},
+		{Mutation: gql.Mutation{Set: []gql.NQuad{{Subject: "0x1", Predicate: "name", ObjectID: "val(u)"}}}},
+		{
+			Cond:     &gql.FilterTree{Func: &gql.Function{Name: "eq", NeedsVar: []gql.VarContext{{Name: "u", Typ: 1}}, Args: []gql.Arg{{Value: "0"}}}},
+			Mutation: gql.Mutation{Set: []gql.NQuad{{Subject: "uid(v)", Predicate: "name", ObjectValue: "x"}}},
+		},
+	}
+
+	for _, e := range undefined {
+		_, err := RenderUpsert(Upsert{Query: query, Mutations: []ConditionalMutation{e}})
+		require.Equal(t, UndefinedVariableError{Name: "u"}, err)
+	}
+}
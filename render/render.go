@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -82,13 +83,50 @@ func renderGraphqlVariables(variables map[string]string) string {
 	}
 
 	res := make([]string, 0, len(variables))
-	for k, e := range variables {
-		res = append(res, fmt.Sprintf("%s: %s", k, e))
+	for _, k := range sortedKeys(variables) {
+		res = append(res, fmt.Sprintf("%s: %s", k, variables[k]))
 	}
 
 	return strings.Join(res, ", ")
 }
 
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// argPositions is the canonical position of the pagination arguments.
+// Any other argument is rendered after them in lexical order.
+var argPositions = map[string]int{
+	"first":  1,
+	"offset": 2,
+	"after":  3,
+}
+
+func argPosition(key string) int {
+	if p, ok := argPositions[key]; ok {
+		return p
+	}
+	return len(argPositions) + 1
+}
+
+func encodeArgMap(args map[string]string) []string {
+	keys := sortedKeys(args)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return argPosition(keys[i]) < argPosition(keys[j])
+	})
+
+	res := make([]string, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, fmt.Sprintf("%s: %s", k, args[k]))
+	}
+	return res
+}
+
 func splitIndent(s string) (string, string) {
 	indent := ""
 	for i, c := range s {
@@ -127,7 +165,24 @@ func cleanWhitespace(query string) string {
 	return strings.Join(res, "\n")
 }
 
-func Render(query Query) (string, error) {
+// Render renders the data representation of a query to its GraphQL+-
+// string. The output is deterministic: equal queries always render to
+// the same string, so it can be used for cache keys and golden tests.
+//
+// The query is rendered for DefaultVersion unless WithVersion is given.
+// Features the version doesn't support result in an
+// UnsupportedFeatureError.
+func Render(query Query, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	if err := checkQuery(query); err != nil {
+		return "", err
+	}
+
+	if err := checkVersion(query, o.version); err != nil {
+		return "", err
+	}
+	query = lowerQuery(query, o.version)
+
 	templ, err := Asset("tmpl/query.tmpl")
 	if err != nil {
 		return "", err
@@ -147,6 +202,7 @@ func Render(query Query) (string, error) {
 		"groupBy":          renderGroupBy,
 		"facets":           renderFacets,
 		"facetsFilter":     renderFacetsFilter,
+		"directives":       renderDirectives,
 	}
 
 	t.Funcs(funcMap).Funcs(sprig.TxtFuncMap()).Parse(string(templ))
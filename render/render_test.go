@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"mooncamp.com/dgraphtools/gql"
+	"mooncamp.com/dgraphtools/gql/dgraph"
 
 	dgraphgql "github.com/dgraph-io/dgraph/gql"
 	"github.com/stretchr/testify/require"
@@ -189,6 +190,34 @@ func TestRender(t *testing.T) {
 				}
 			}`,
 		},
+		{
+			name: "recurse with depth and loop",
+			query: `{
+				me(func: uid(0x01)) @recurse(depth: 3, loop: true) {
+					name
+					friend
+				}
+			}`,
+		},
+		{
+			name: "cascade and ignorereflex at root",
+			query: `{
+				me(func: uid(0x01)) @cascade @ignorereflex {
+					name
+					friend {
+						name
+					}
+				}
+			}`,
+		},
+		{
+			name: "uid_in filter",
+			query: `{
+				me(func: has(name)) @filter(uid_in(school, 0x1388)) {
+					name
+				}
+			}`,
+		},
 		{
 			name: "has func at root",
 			query: `{
@@ -206,6 +235,17 @@ func TestRender(t *testing.T) {
 				}
 			}`,
 		},
+		{
+			name: "count uid alias",
+			query: `{
+				me(func: uid( 1)) {
+					friend {
+						name
+						total: count(uid)
+					}
+				}
+			}`,
+		},
 		{
 			name: "regexp 3",
 			query: `
@@ -1482,7 +1522,14 @@ func TestRender(t *testing.T) {
 				t.Fatalf("parse: %v", err)
 			}
 
-			expected := gql.DecodeGraphQueries(gqlExpected.Query)
+			expected := dgraph.DecodeGraphQueries(gqlExpected.Query)
+			parsed, err := gql.Parse(gql.Request{Str: e.query, Variables: gqlVariables})
+			if err != nil {
+				t.Fatalf("gql parse: %v", err)
+			}
+			require.Equal(t, expected, parsed)
+			require.Empty(t, gql.Validate(expected))
+
 			renderedQuery, err := Render(Query{Queries: expected, Alias: e.alias, Variables: e.variables})
 			if err != nil {
 				t.Fatalf("render: %v", err)
@@ -1493,8 +1540,170 @@ func TestRender(t *testing.T) {
 				t.Fatalf("parse: %v", err)
 			}
 
-			actual := gql.DecodeGraphQueries(gqlActual.Query)
+			actual := dgraph.DecodeGraphQueries(gqlActual.Query)
 			require.Equal(t, expected, actual)
+
+			parsed, err = gql.Parse(gql.Request{Str: renderedQuery, Variables: gqlVariables})
+			if err != nil {
+				t.Fatalf("gql parse: %v", err)
+			}
+			require.Equal(t, expected, parsed)
+		})
+	}
+}
+
+func TestRenderDeterministic(t *testing.T) {
+	query := Query{
+		Alias:     "q",
+		Variables: map[string]string{"$b": "int", "$a": "string", "$c": "int"},
+		Queries: []gql.GraphQuery{
+			{
+				Alias: "me",
+				UID:   []uint64{1},
+				Func:  &gql.Function{Name: "uid"},
+				Args:  map[string]string{"after": "0x2", "offset": "$b", "first": "$c"},
+				Children: []gql.GraphQuery{
+					{
+						Attr:       "friends",
+						Args:       map[string]string{"offset": "1", "first": "10", "after": "0x5"},
+						Facets:     &gql.FacetParams{Param: []gql.FacetParam{{Key: "close"}, {Key: "since"}, {Key: "weight"}}},
+						FacetVar:   map[string]string{"weight": "w", "since": "s", "close": "c"},
+						FacetOrder: "since",
+						Children:   []gql.GraphQuery{{Attr: "name"}},
+					},
+				},
+			},
+		},
+	}
+
+	first, err := Render(query)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	require.Contains(t, first, "query q ($a: string, $b: int, $c: int)")
+	require.Contains(t, first, "me (func: uid(0x01), first: $c,offset: $b,after: 0x2)")
+	require.Contains(t, first, "friends(first: 10,offset: 1,after: 0x5) @facets(orderasc: s as since, c as close, w as weight)")
+
+	for i := 0; i < 20; i++ {
+		res, err := Render(query)
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+
+		require.Equal(t, first, res)
+	}
+}
+
+func TestRenderEscaping(t *testing.T) {
+	values := []string{
+		`x") { secret }`,
+		`back\slash`,
+		"new\nline",
+		`"quoted"`,
+		`trailing\`,
+	}
+
+	for _, e := range values {
+		value := e
+		t.Run(value, func(t *testing.T) {
+			expected := []gql.GraphQuery{
+				{
+					Alias: "me",
+					Func:  &gql.Function{Name: "eq", Attr: "name", Args: []gql.Arg{{Value: value}}},
+					Filter: &gql.FilterTree{
+						Op: "or",
+						Child: []gql.FilterTree{
+							{Func: &gql.Function{Name: "anyofterms", Attr: "name", Args: []gql.Arg{{Value: value}}}},
+							{Func: &gql.Function{Name: "regexp", Attr: "name", Args: []gql.Arg{{Value: "a/b"}, {Value: "i"}}}},
+						},
+					},
+					Children: []gql.GraphQuery{
+						{Attr: "uid"},
+						{Attr: "password", Func: &gql.Function{Name: "checkpwd", Attr: "password", Args: []gql.Arg{{Value: value}, {Value: "password"}}}},
+					},
+				},
+			}
+
+			renderedQuery, err := Render(Query{Queries: expected})
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+
+			parsed, err := dgraphgql.Parse(dgraphgql.Request{Str: renderedQuery})
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			require.Equal(t, expected, dgraph.DecodeGraphQueries(parsed.Query))
+
+			actual, err := gql.Parse(gql.Request{Str: renderedQuery})
+			if err != nil {
+				t.Fatalf("gql parse: %v", err)
+			}
+			require.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestRenderRejectsInvalidIdentifiers(t *testing.T) {
+	root := func(gq gql.GraphQuery) gql.GraphQuery {
+		gq.Alias = "me"
+		gq.UID = []uint64{1}
+		gq.Func = &gql.Function{Name: "uid"}
+		gq.Children = append(gq.Children, gql.GraphQuery{Attr: "uid"})
+		return gq
+	}
+
+	table := []struct {
+		name  string
+		query Query
+		err   error
+	}{
+		{
+			name:  "attribute",
+			query: Query{Queries: []gql.GraphQuery{root(gql.GraphQuery{Children: []gql.GraphQuery{{Attr: "name } secret {"}}})}},
+			err:   InvalidIdentifierError{Kind: "attribute", Value: "name } secret {"},
+		},
+		{
+			name:  "alias",
+			query: Query{Queries: []gql.GraphQuery{root(gql.GraphQuery{Children: []gql.GraphQuery{{Attr: "name", Alias: "a: b"}}})}},
+			err:   InvalidIdentifierError{Kind: "alias", Value: "a: b"},
+		},
+		{
+			name:  "variable",
+			query: Query{Queries: []gql.GraphQuery{root(gql.GraphQuery{Children: []gql.GraphQuery{{Attr: "name", Var: "a as b"}}})}},
+			err:   InvalidIdentifierError{Kind: "variable", Value: "a as b"},
+		},
+		{
+			name:  "language",
+			query: Query{Queries: []gql.GraphQuery{root(gql.GraphQuery{Children: []gql.GraphQuery{{Attr: "name", Langs: []string{"en secret"}}}})}},
+			err:   InvalidIdentifierError{Kind: "language", Value: "en secret"},
+		},
+		{
+			name:  "argument",
+			query: Query{Queries: []gql.GraphQuery{root(gql.GraphQuery{Args: map[string]string{"first": "1) { secret }"}})}},
+			err:   InvalidLiteralError{Kind: "argument", Value: "1) { secret }", Reason: "expected a number, uid or graphql variable"},
+		},
+		{
+			name:  "graphql variable",
+			query: Query{Variables: map[string]string{"$a: int) { secret } query q($b": "int"}},
+			err:   InvalidIdentifierError{Kind: "graphql variable", Value: "$a: int) { secret } query q($b"},
+		},
+		{
+			name: "regexp",
+			query: Query{Queries: []gql.GraphQuery{root(gql.GraphQuery{Filter: &gql.FilterTree{
+				Func: &gql.Function{Name: "regexp", Attr: "name", Args: []gql.Arg{{Value: `a\`}, {Value: "i"}}},
+			}})}},
+			err: InvalidLiteralError{Kind: "regexp", Value: `a\`, Reason: "trailing backslash"},
+		},
+	}
+
+	for _, e := range table {
+		query, expected := e.query, e.err
+		t.Run(e.name, func(t *testing.T) {
+			_, err := Render(query)
+			require.Equal(t, expected, err)
 		})
 	}
 }
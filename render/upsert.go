@@ -0,0 +1,161 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"mooncamp.com/dgraphtools/gql"
+)
+
+var condFunctions = map[string]bool{"eq": true, "le": true, "lt": true, "ge": true, "gt": true}
+
+// Upsert is a query followed by mutations that may reference the
+// variables of the query through `uid(v)` and `val(v)`.
+type Upsert struct {
+	Query     Query                 `yaml:"query,omitempty" json:"query,omitempty"`
+	Mutations []ConditionalMutation `yaml:"mutations,omitempty" json:"mutations,omitempty"`
+}
+
+// ConditionalMutation is only run if Cond holds. The functions of Cond
+// compare the number of uids of a variable, e.g. eq(len(v), 0), which is
+// stored as Function{Name: "eq", NeedsVar: []VarContext{{Name: "v"}},
+// Args: []Arg{{Value: "0"}}}.
+type ConditionalMutation struct {
+	Cond     *gql.FilterTree `yaml:"cond,omitempty" json:"cond,omitempty"`
+	Mutation gql.Mutation    `yaml:"mutation,omitempty" json:"mutation,omitempty"`
+}
+
+// RenderUpsert renders an upsert block. Every variable referenced by
+// the mutations has to be defined in the query.
+func RenderUpsert(upsert Upsert) (string, error) {
+	query, err := Render(upsert.Query)
+	if err != nil {
+		return "", err
+	}
+
+	defined := map[string]bool{}
+	for _, e := range upsert.Query.Queries {
+		definedVars(e, defined)
+	}
+
+	blocks := []string{indentLines(query, 2)}
+	for _, e := range upsert.Mutations {
+		block, err := renderConditionalMutation(e, defined)
+		if err != nil {
+			return "", err
+		}
+		blocks = append(blocks, indentLines(block, 2))
+	}
+
+	return fmt.Sprintf("upsert {\n%s\n}", strings.Join(blocks, "\n\n")), nil
+}
+
+func renderConditionalMutation(cm ConditionalMutation, defined map[string]bool) (string, error) {
+	set, del, err := RenderMutation(cm.Mutation)
+	if err != nil {
+		return "", err
+	}
+
+	for _, e := range append(append([]gql.NQuad{}, cm.Mutation.Set...), cm.Mutation.Delete...) {
+		for _, n := range []string{e.Subject, e.ObjectID} {
+			if _, name, ok := varRef(n); ok && !defined[name] {
+				return "", UndefinedVariableError{Name: name}
+			}
+		}
+	}
+
+	head := "mutation"
+	if cm.Cond != nil {
+		if err := checkCond(*cm.Cond, defined); err != nil {
+			return "", err
+		}
+		head = fmt.Sprintf("mutation @if(%s)", renderCond(*cm.Cond))
+	}
+
+	parts := []string{}
+	if set != "" {
+		parts = append(parts, fmt.Sprintf("set {\n%s\n}", indentLines(set, 2)))
+	}
+
+	if del != "" {
+		parts = append(parts, fmt.Sprintf("delete {\n%s\n}", indentLines(del, 2)))
+	}
+
+	return fmt.Sprintf("%s {\n%s\n}", head, indentLines(strings.Join(parts, "\n"), 2)), nil
+}
+
+func renderCond(tree gql.FilterTree) string {
+	if tree.Func != nil {
+		return fmt.Sprintf("%s(len(%s), %s)", tree.Func.Name, tree.Func.NeedsVar[0].Name, tree.Func.Args[0].Value)
+	}
+
+	childs := make([]string, 0, len(tree.Child))
+	for _, e := range tree.Child {
+		childs = append(childs, renderCond(e))
+	}
+
+	if tree.Op == "not" {
+		return fmt.Sprintf("not (%s)", strings.Join(childs, ", "))
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(childs, fmt.Sprintf(" %s ", tree.Op)))
+}
+
+func checkCond(tree gql.FilterTree, defined map[string]bool) error {
+	if fn := tree.Func; fn != nil {
+		if !condFunctions[fn.Name] {
+			return InvalidIdentifierError{Kind: "condition function", Value: fn.Name}
+		}
+
+		if len(fn.NeedsVar) != 1 || len(fn.Args) != 1 {
+			return InvalidLiteralError{Kind: "condition", Value: fn.Name, Reason: "expected one variable and one argument"}
+		}
+
+		if !defined[fn.NeedsVar[0].Name] {
+			return UndefinedVariableError{Name: fn.NeedsVar[0].Name}
+		}
+
+		if !isUID(fn.Args[0].Value) {
+			return InvalidLiteralError{Kind: "condition argument", Value: fn.Args[0].Value, Reason: "expected a number"}
+		}
+
+		return nil
+	}
+
+	if !filterOps[tree.Op] || tree.Op == "" {
+		return InvalidIdentifierError{Kind: "condition operator", Value: tree.Op}
+	}
+
+	for _, e := range tree.Child {
+		if err := checkCond(e, defined); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func definedVars(gq gql.GraphQuery, defined map[string]bool) {
+	if gq.Var != "" {
+		defined[gq.Var] = true
+	}
+
+	for _, e := range gq.FacetVar {
+		defined[e] = true
+	}
+
+	for _, e := range gq.Children {
+		definedVars(e, defined)
+	}
+}
+
+func indentLines(s string, n int) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, e := range lines {
+		if e != "" {
+			lines[i] = pad + e
+		}
+	}
+	return strings.Join(lines, "\n")
+}
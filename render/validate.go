@@ -1,8 +1,11 @@
 package render
 
 import (
+	"fmt"
 	"reflect"
 
+	"mooncamp.com/dgraphtools/gql"
+
 	"github.com/davecgh/go-spew/spew"
 	"github.com/pmezard/go-difflib/difflib"
 )
@@ -62,3 +65,27 @@ func typeAndKind(v interface{}) (reflect.Type, reflect.Kind) {
 	}
 	return t, k
 }
+
+// differingBlock returns the path of the first block that differs
+// between expected and actual, like queries[0].children[2]. A block
+// whose children differ in number is reported itself.
+func differingBlock(path string, expected, actual []gql.GraphQuery) string {
+	for i := 0; i < len(expected) && i < len(actual); i++ {
+		if gql.Equal(expected[i], actual[i]) {
+			continue
+		}
+
+		p := fmt.Sprintf("%s[%d]", path, i)
+		e, a := expected[i], actual[i]
+		e.Children, a.Children = nil, nil
+		if !gql.Equal(e, a) || len(expected[i].Children) != len(actual[i].Children) {
+			return p
+		}
+		return differingBlock(p+".children", expected[i].Children, actual[i].Children)
+	}
+
+	if len(expected) < len(actual) {
+		return fmt.Sprintf("%s[%d]", path, len(expected))
+	}
+	return fmt.Sprintf("%s[%d]", path, len(actual))
+}
@@ -8,7 +8,32 @@ import (
 )
 
 type QueryVerifier interface {
-	QueryAllowed(ctx context.Context, queries []gql.GraphQuery, userID int, proofs map[int]gql.GraphQuery) (bool, error)
+	QueryAllowed(ctx context.Context, queries []gql.GraphQuery, identity Identity, proofs map[uint64]gql.GraphQuery) (bool, error)
+}
+
+// Identity is the user a request is made for.
+type Identity struct {
+	// UID is the node of the identity in the graph.
+	UID uint64
+	// Subject and Tenant are the claims of an external identity, e.g.
+	// the subject of a JWT. They are mapped to UID by an
+	// IdentityResolver.
+	Subject string
+	Tenant  string
+}
+
+// IdentityResolver looks up the node of an external identity. Unknown
+// identities are reported by uid 0 or an Unauthenticated error, other
+// errors are taken as failures of Dgraph.
+type IdentityResolver interface {
+	ResolveIdentity(ctx context.Context, identity Identity) (uint64, error)
+}
+
+// IdentityResolverFunc is a function used as IdentityResolver.
+type IdentityResolverFunc func(ctx context.Context, identity Identity) (uint64, error)
+
+func (f IdentityResolverFunc) ResolveIdentity(ctx context.Context, identity Identity) (uint64, error) {
+	return f(ctx, identity)
 }
 
 type QueryHandler interface {
@@ -16,21 +41,15 @@ type QueryHandler interface {
 }
 
 type QueryRequest struct {
-	Identity int
+	Identity Identity
 
 	Queries   []gql.GraphQuery
 	Alias     string
 	Variables map[string]string
-	Proof     map[int]gql.GraphQuery
+	Proof     map[uint64]gql.GraphQuery
 }
 
 type QueryResponse struct {
 	Response []byte
 	Error    error
 }
-
-type Unauthorized struct{}
-
-func (Unauthorized) Error() string {
-	return "unauthorized action"
-}
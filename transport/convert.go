@@ -0,0 +1,419 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"mooncamp.com/dgraphtools/gql"
+	"mooncamp.com/dgraphtools/transport/pb"
+
+	structpb "github.com/golang/protobuf/ptypes/struct"
+)
+
+func toPBQueries(gqs []gql.GraphQuery) ([]*pb.GraphQuery, error) {
+	if gqs == nil {
+		return nil, nil
+	}
+
+	res := make([]*pb.GraphQuery, len(gqs))
+	for i := range gqs {
+		var err error
+		if res[i], err = toPBQuery(&gqs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func toPBQuery(gq *gql.GraphQuery) (*pb.GraphQuery, error) {
+	children, err := toPBQueries(gq.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	def, err := toPBValue(gq.Default)
+	if err != nil {
+		return nil, fmt.Errorf("default: %v", err)
+	}
+
+	var extensions map[string]*structpb.Value
+	if gq.Extensions != nil {
+		extensions = make(map[string]*structpb.Value, len(gq.Extensions))
+		for k, v := range gq.Extensions {
+			if extensions[k], err = toPBJSONValue(v); err != nil {
+				return nil, fmt.Errorf("extension %s: %v", k, err)
+			}
+		}
+	}
+
+	mathExp, err := toPBMathTree(gq.MathExp)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.GraphQuery{
+		Uid:           gq.UID,
+		Attr:          gq.Attr,
+		Langs:         gq.Langs,
+		Alias:         gq.Alias,
+		Default:       def,
+		IsCount:       gq.IsCount,
+		IsInternal:    gq.IsInternal,
+		IsGroupby:     gq.IsGroupby,
+		Var:           gq.Var,
+		NeedsVar:      toPBVarContexts(gq.NeedsVar),
+		Func:          toPBFunction(gq.Func),
+		Expand:        gq.Expand,
+		Args:          gq.Args,
+		Children:      children,
+		Filter:        toPBFilterTree(gq.Filter),
+		MathExp:       mathExp,
+		Normalize:     gq.Normalize,
+		Recurse:       gq.Recurse,
+		Cascade:       gq.Cascade,
+		CascadeFields: gq.CascadeFields,
+		IgnoreReflex:  gq.IgnoreReflex,
+		FacetsFilter:  toPBFilterTree(gq.FacetsFilter),
+		FacetVar:      gq.FacetVar,
+		FacetOrder:    gq.FacetOrder,
+		FacetDesc:     gq.FacetDesc,
+		UidCount:      gq.UidCount,
+		UidCountAlias: gq.UidCountAlias,
+		Cardinality:   string(gq.Cardinality),
+		Extensions:    extensions,
+	}
+
+	if gq.RecurseArgs != (gql.RecurseArgs{}) {
+		res.RecurseArgs = &pb.RecurseArgs{Depth: gq.RecurseArgs.Depth, AllowLoop: gq.RecurseArgs.AllowLoop}
+	}
+
+	for _, e := range gq.Order {
+		res.Order = append(res.Order, &pb.Order{Attr: e.Attr, Desc: e.Desc, Langs: e.Langs})
+	}
+
+	if gq.Facets != nil {
+		res.Facets = &pb.FacetParams{AllKeys: gq.Facets.AllKeys}
+		for _, e := range gq.Facets.Param {
+			res.Facets.Param = append(res.Facets.Param, &pb.FacetParam{Key: e.Key, Alias: e.Alias})
+		}
+	}
+
+	for _, e := range gq.GroupbyAttrs {
+		res.GroupbyAttrs = append(res.GroupbyAttrs, &pb.GroupByAttr{Attr: e.Attr, Alias: e.Alias, Langs: e.Langs})
+	}
+
+	return res, nil
+}
+
+func toPBVarContexts(vcs []gql.VarContext) []*pb.VarContext {
+	var res []*pb.VarContext
+	for _, e := range vcs {
+		res = append(res, &pb.VarContext{Name: e.Name, Typ: int64(e.Typ)})
+	}
+	return res
+}
+
+func toPBFunction(f *gql.Function) *pb.Function {
+	if f == nil {
+		return nil
+	}
+
+	res := &pb.Function{
+		Attr:       f.Attr,
+		Lang:       f.Lang,
+		Name:       f.Name,
+		Uid:        f.UID,
+		NeedsVar:   toPBVarContexts(f.NeedsVar),
+		IsCount:    f.IsCount,
+		IsValueVar: f.IsValueVar,
+	}
+	for _, e := range f.Args {
+		res.Args = append(res.Args, &pb.Arg{Value: e.Value, IsValueVar: e.IsValueVar, IsGraphQlVar: e.IsGraphQLVar})
+	}
+	return res
+}
+
+func toPBFilterTree(ft *gql.FilterTree) *pb.FilterTree {
+	if ft == nil {
+		return nil
+	}
+
+	res := &pb.FilterTree{Op: ft.Op, Func: toPBFunction(ft.Func)}
+	for i := range ft.Child {
+		res.Child = append(res.Child, toPBFilterTree(&ft.Child[i]))
+	}
+	return res
+}
+
+func toPBMathTree(mt *gql.MathTree) (*pb.MathTree, error) {
+	if mt == nil {
+		return nil, nil
+	}
+
+	c, err := toPBVal(mt.Const)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.MathTree{Fn: mt.Fn, Var: mt.Var, Const: c}
+	if mt.Val != nil {
+		res.Val = make(map[uint64]*pb.Val, len(mt.Val))
+		for k, v := range mt.Val {
+			if res.Val[k], err = toPBVal(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i := range mt.Child {
+		child, err := toPBMathTree(&mt.Child[i])
+		if err != nil {
+			return nil, err
+		}
+		res.Child = append(res.Child, child)
+	}
+	return res, nil
+}
+
+func toPBVal(v gql.Val) (*pb.Val, error) {
+	if v.Tid == 0 && v.Value == nil {
+		return nil, nil
+	}
+
+	value, err := toPBValue(v.Value)
+	if err != nil {
+		return nil, fmt.Errorf("math value: %v", err)
+	}
+	return &pb.Val{Tid: int32(v.Tid), Value: value}, nil
+}
+
+// toPBValue converts v like encoding/json would, nil stays nil.
+func toPBValue(v interface{}) (*structpb.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return toPBJSONValue(v)
+}
+
+func toPBJSONValue(v interface{}) (*structpb.Value, error) {
+	switch t := v.(type) {
+	case nil:
+		return &structpb.Value{Kind: &structpb.Value_NullValue{}}, nil
+	case bool:
+		return &structpb.Value{Kind: &structpb.Value_BoolValue{BoolValue: t}}, nil
+	case string:
+		return &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: t}}, nil
+	case []interface{}:
+		list := &structpb.ListValue{}
+		for _, e := range t {
+			value, err := toPBJSONValue(e)
+			if err != nil {
+				return nil, err
+			}
+			list.Values = append(list.Values, value)
+		}
+		return &structpb.Value{Kind: &structpb.Value_ListValue{ListValue: list}}, nil
+	case map[string]interface{}:
+		s := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(t))}
+		for k, e := range t {
+			value, err := toPBJSONValue(e)
+			if err != nil {
+				return nil, err
+			}
+			s.Fields[k] = value
+		}
+		return &structpb.Value{Kind: &structpb.Value_StructValue{StructValue: s}}, nil
+	}
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: float64(rv.Int())}}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: float64(rv.Uint())}}, nil
+	case reflect.Float32, reflect.Float64:
+		return &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: rv.Float()}}, nil
+	}
+
+	// Other types, e.g. structs, are sent as their JSON.
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(buf, &generic); err != nil {
+		return nil, err
+	}
+	return toPBJSONValue(generic)
+}
+
+func fromPBQueries(gqs []*pb.GraphQuery) []gql.GraphQuery {
+	if gqs == nil {
+		return nil
+	}
+
+	res := make([]gql.GraphQuery, len(gqs))
+	for i, e := range gqs {
+		res[i] = fromPBQuery(e)
+	}
+	return res
+}
+
+func fromPBQuery(gq *pb.GraphQuery) gql.GraphQuery {
+	var extensions map[string]interface{}
+	if gq.Extensions != nil {
+		extensions = make(map[string]interface{}, len(gq.Extensions))
+		for k, v := range gq.Extensions {
+			extensions[k] = fromPBValue(v)
+		}
+	}
+
+	res := gql.GraphQuery{
+		UID:           gq.Uid,
+		Attr:          gq.Attr,
+		Langs:         gq.Langs,
+		Alias:         gq.Alias,
+		Default:       fromPBValue(gq.Default),
+		IsCount:       gq.IsCount,
+		IsInternal:    gq.IsInternal,
+		IsGroupby:     gq.IsGroupby,
+		Var:           gq.Var,
+		NeedsVar:      fromPBVarContexts(gq.NeedsVar),
+		Func:          fromPBFunction(gq.Func),
+		Expand:        gq.Expand,
+		Args:          gq.Args,
+		Children:      fromPBQueries(gq.Children),
+		Filter:        fromPBFilterTree(gq.Filter),
+		MathExp:       fromPBMathTree(gq.MathExp),
+		Normalize:     gq.Normalize,
+		Recurse:       gq.Recurse,
+		Cascade:       gq.Cascade,
+		CascadeFields: gq.CascadeFields,
+		IgnoreReflex:  gq.IgnoreReflex,
+		FacetsFilter:  fromPBFilterTree(gq.FacetsFilter),
+		FacetVar:      gq.FacetVar,
+		FacetOrder:    gq.FacetOrder,
+		FacetDesc:     gq.FacetDesc,
+		UidCount:      gq.UidCount,
+		UidCountAlias: gq.UidCountAlias,
+		Cardinality:   gql.Cardinality(gq.Cardinality),
+		Extensions:    extensions,
+	}
+
+	if gq.RecurseArgs != nil {
+		res.RecurseArgs = gql.RecurseArgs{Depth: gq.RecurseArgs.Depth, AllowLoop: gq.RecurseArgs.AllowLoop}
+	}
+
+	for _, e := range gq.Order {
+		res.Order = append(res.Order, gql.Order{Attr: e.Attr, Desc: e.Desc, Langs: e.Langs})
+	}
+
+	if gq.Facets != nil {
+		res.Facets = &gql.FacetParams{AllKeys: gq.Facets.AllKeys}
+		for _, e := range gq.Facets.Param {
+			res.Facets.Param = append(res.Facets.Param, gql.FacetParam{Key: e.Key, Alias: e.Alias})
+		}
+	}
+
+	for _, e := range gq.GroupbyAttrs {
+		res.GroupbyAttrs = append(res.GroupbyAttrs, gql.GroupByAttr{Attr: e.Attr, Alias: e.Alias, Langs: e.Langs})
+	}
+
+	return res
+}
+
+func fromPBVarContexts(vcs []*pb.VarContext) []gql.VarContext {
+	var res []gql.VarContext
+	for _, e := range vcs {
+		res = append(res, gql.VarContext{Name: e.Name, Typ: int(e.Typ)})
+	}
+	return res
+}
+
+func fromPBFunction(f *pb.Function) *gql.Function {
+	if f == nil {
+		return nil
+	}
+
+	res := &gql.Function{
+		Attr:       f.Attr,
+		Lang:       f.Lang,
+		Name:       f.Name,
+		UID:        f.Uid,
+		NeedsVar:   fromPBVarContexts(f.NeedsVar),
+		IsCount:    f.IsCount,
+		IsValueVar: f.IsValueVar,
+	}
+	for _, e := range f.Args {
+		res.Args = append(res.Args, gql.Arg{Value: e.Value, IsValueVar: e.IsValueVar, IsGraphQLVar: e.IsGraphQlVar})
+	}
+	return res
+}
+
+func fromPBFilterTree(ft *pb.FilterTree) *gql.FilterTree {
+	if ft == nil {
+		return nil
+	}
+
+	res := &gql.FilterTree{Op: ft.Op, Func: fromPBFunction(ft.Func)}
+	for _, e := range ft.Child {
+		res.Child = append(res.Child, *fromPBFilterTree(e))
+	}
+	return res
+}
+
+func fromPBMathTree(mt *pb.MathTree) *gql.MathTree {
+	if mt == nil {
+		return nil
+	}
+
+	res := &gql.MathTree{Fn: mt.Fn, Var: mt.Var, Const: fromPBVal(mt.Const)}
+	if mt.Val != nil {
+		res.Val = make(map[uint64]gql.Val, len(mt.Val))
+		for k, v := range mt.Val {
+			res.Val[k] = fromPBVal(v)
+		}
+	}
+
+	for _, e := range mt.Child {
+		res.Child = append(res.Child, *fromPBMathTree(e))
+	}
+	return res
+}
+
+func fromPBVal(v *pb.Val) gql.Val {
+	if v == nil {
+		return gql.Val{}
+	}
+	return gql.Val{Tid: gql.TypeID(v.Tid), Value: fromPBValue(v.Value)}
+}
+
+// fromPBValue returns v like encoding/json decodes into interface{}.
+func fromPBValue(v *structpb.Value) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	switch k := v.Kind.(type) {
+	case *structpb.Value_BoolValue:
+		return k.BoolValue
+	case *structpb.Value_NumberValue:
+		return k.NumberValue
+	case *structpb.Value_StringValue:
+		return k.StringValue
+	case *structpb.Value_ListValue:
+		res := make([]interface{}, len(k.ListValue.Values))
+		for i, e := range k.ListValue.Values {
+			res[i] = fromPBValue(e)
+		}
+		return res
+	case *structpb.Value_StructValue:
+		res := make(map[string]interface{}, len(k.StructValue.Fields))
+		for key, e := range k.StructValue.Fields {
+			res[key] = fromPBValue(e)
+		}
+		return res
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// Package transport exposes the endpoints of this module over HTTP and
+// gRPC.
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+	"mooncamp.com/dgraphtools/gql/extension"
+	"mooncamp.com/dgraphtools/render"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+// Codes are the machine readable part of error responses.
+const (
+	CodeInvalidRequest  = "invalid_request"
+	CodeUnauthenticated = "unauthenticated"
+	CodeForbidden       = "forbidden"
+	CodeExtensionFailed = "extension_failed"
+	CodeUpstreamTimeout = "upstream_timeout"
+	CodeUpstreamError   = "upstream_error"
+	CodeInternal        = "internal"
+
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeNotAcceptable        = "not_acceptable"
+	CodeRequestTooLarge      = "request_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+)
+
+// MethodNotAllowed is returned for requests not using POST.
+type MethodNotAllowed struct {
+	Method string
+}
+
+func (e MethodNotAllowed) Error() string {
+	return fmt.Sprintf("method %s not allowed", e.Method)
+}
+
+// NotAcceptable is returned if the client accepts none of the media
+// types the response can be written in.
+type NotAcceptable struct {
+	Accept string
+}
+
+func (e NotAcceptable) Error() string {
+	return fmt.Sprintf("none of %q can be served", e.Accept)
+}
+
+// RequestTooLarge is returned for request bodies larger than Limit
+// bytes.
+type RequestTooLarge struct {
+	Limit int64
+}
+
+func (e RequestTooLarge) Error() string {
+	return fmt.Sprintf("request body larger than %d bytes", e.Limit)
+}
+
+// UnsupportedMediaType is returned for request bodies of an unknown
+// content type.
+type UnsupportedMediaType struct {
+	MediaType string
+}
+
+func (e UnsupportedMediaType) Error() string {
+	return fmt.Sprintf("unsupported media type %q", e.MediaType)
+}
+
+// ErrorBody is the JSON body of error responses.
+type ErrorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		// Path points to the failing part of the queries, if known.
+		Path string `json:"path,omitempty"`
+	} `json:"error"`
+}
+
+// ErrorEncoder writes err as ErrorBody with the status of its type. It
+// is a go-kit httptransport.ErrorEncoder. The details of internal and
+// upstream errors aren't sent to the client, see LoggingErrorEncoder.
+func ErrorEncoder(ctx context.Context, err error, w http.ResponseWriter) {
+	status, code, path := classify(err)
+
+	var body ErrorBody
+	body.Error.Code = code
+	body.Error.Message = redact(code, err).Error()
+	body.Error.Path = path
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// LoggingErrorEncoder is ErrorEncoder logging the details of internal
+// and upstream errors to logger.
+func LoggingErrorEncoder(logger log.Logger) httptransport.ErrorEncoder {
+	return func(ctx context.Context, err error, w http.ResponseWriter) {
+		_, code, _ := classify(err)
+		logDetails(logger, code, err)
+		ErrorEncoder(ctx, err, w)
+	}
+}
+
+// StatusCode returns the HTTP status ErrorEncoder writes for err.
+func StatusCode(err error) int {
+	status, _, _ := classify(err)
+	return status
+}
+
+// errDetailsWithheld replaces the details of errors sent to clients, as
+// they may expose e.g. the address of Dgraph or parts of other queries.
+var errDetailsWithheld = errors.New("details withheld")
+
+// redact returns err as it is sent to clients. Internal and upstream
+// errors lose their details.
+func redact(code string, err error) error {
+	switch code {
+	case CodeUpstreamTimeout:
+		return dgraphtools.UpstreamTimeout{Err: errDetailsWithheld}
+	case CodeUpstreamError:
+		return dgraphtools.UpstreamError{Err: errDetailsWithheld}
+	case CodeInternal:
+		return dgraphtools.InternalError{Err: errDetailsWithheld}
+	}
+	return err
+}
+
+// logDetails logs the errors whose details redact leaves out.
+func logDetails(logger log.Logger, code string, err error) {
+	switch code {
+	case CodeUpstreamTimeout, CodeUpstreamError, CodeInternal:
+		_ = logger.Log("code", code, "err", err)
+	}
+}
+
+func classify(err error) (int, string, string) {
+	var (
+		invalid         dgraphtools.InvalidRequest
+		validation      gql.ValidationErrors
+		syntax          gql.SyntaxError
+		identifier      render.InvalidIdentifierError
+		literal         render.InvalidLiteralError
+		undefined       render.UndefinedVariableError
+		unsupported     render.UnsupportedFeatureError
+		unauthenticated dgraphtools.Unauthenticated
+		unauthorized    dgraphtools.Unauthorized
+		extensionError  extension.Error
+		upstreamTimeout dgraphtools.UpstreamTimeout
+		upstreamError   dgraphtools.UpstreamError
+	)
+
+	switch {
+	case errors.As(err, new(MethodNotAllowed)):
+		return http.StatusMethodNotAllowed, CodeMethodNotAllowed, ""
+	case errors.As(err, new(NotAcceptable)):
+		return http.StatusNotAcceptable, CodeNotAcceptable, ""
+	case errors.As(err, new(RequestTooLarge)):
+		return http.StatusRequestEntityTooLarge, CodeRequestTooLarge, ""
+	case errors.As(err, new(UnsupportedMediaType)):
+		return http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, ""
+	case errors.As(err, &invalid):
+		return http.StatusBadRequest, CodeInvalidRequest, invalid.Path
+	case errors.As(err, &validation) && len(validation) > 0:
+		return http.StatusBadRequest, CodeInvalidRequest, validation[0].Path
+	case errors.As(err, &syntax), errors.As(err, &identifier), errors.As(err, &literal),
+		errors.As(err, &undefined), errors.As(err, &unsupported):
+		return http.StatusBadRequest, CodeInvalidRequest, ""
+	case errors.As(err, &unauthenticated):
+		return http.StatusUnauthorized, CodeUnauthenticated, ""
+	case errors.As(err, &unauthorized):
+		return http.StatusForbidden, CodeForbidden, ""
+	case errors.As(err, &extensionError):
+		// The response of Dgraph doesn't satisfy the query, e.g. its
+		// cardinality.
+		return http.StatusUnprocessableEntity, CodeExtensionFailed, extensionError.Path
+	case errors.As(err, &upstreamTimeout):
+		return http.StatusGatewayTimeout, CodeUpstreamTimeout, ""
+	case errors.As(err, &upstreamError):
+		return http.StatusBadGateway, CodeUpstreamError, ""
+	}
+
+	return http.StatusInternalServerError, CodeInternal, ""
+}
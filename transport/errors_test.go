@@ -0,0 +1,156 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+	"mooncamp.com/dgraphtools/gql/extension"
+	"mooncamp.com/dgraphtools/policy"
+	"mooncamp.com/dgraphtools/render"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorEncoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		path    string
+		message string
+	}{
+		{
+			name:   "invalid request",
+			err:    dgraphtools.InvalidRequest{Err: errors.New("unexpected EOF")},
+			status: http.StatusBadRequest,
+			code:   CodeInvalidRequest,
+		},
+		{
+			name:   "validation",
+			err:    dgraphtools.InvalidRequest{Path: "queries[0].children[1]", Err: gql.ValidationErrors{{Path: "queries[0].children[1]", Msg: "missing attr"}}},
+			status: http.StatusBadRequest,
+			code:   CodeInvalidRequest,
+			path:   "queries[0].children[1]",
+		},
+		{
+			name:   "unwrapped validation",
+			err:    gql.ValidationErrors{{Path: "queries[0]", Msg: "missing attr"}},
+			status: http.StatusBadRequest,
+			code:   CodeInvalidRequest,
+			path:   "queries[0]",
+		},
+		{
+			name:   "render",
+			err:    render.InvalidIdentifierError{Kind: "predicate", Value: "a b"},
+			status: http.StatusBadRequest,
+			code:   CodeInvalidRequest,
+		},
+		{
+			name:   "unauthenticated",
+			err:    dgraphtools.Unauthenticated{Reason: "missing token"},
+			status: http.StatusUnauthorized,
+			code:   CodeUnauthenticated,
+		},
+		{
+			name:   "unauthorized",
+			err:    dgraphtools.Unauthorized{},
+			status: http.StatusForbidden,
+			code:   CodeForbidden,
+		},
+		{
+			name:   "policy",
+			err:    policy.Denied{Kind: "predicate", Name: "email", Path: "queries[0].children[1]"},
+			status: http.StatusForbidden,
+			code:   CodeForbidden,
+		},
+		{
+			name:   "extension",
+			err:    extension.Error{Path: "queries[0].children[1]", Err: extension.CardinalityError{Path: "me[0].spouse", Cardinality: gql.CardinalityOne, Count: 2}},
+			status: http.StatusUnprocessableEntity,
+			code:   CodeExtensionFailed,
+			path:   "queries[0].children[1]",
+		},
+		{
+			name:    "deadline",
+			err:     dgraphtools.Upstream(fmt.Errorf("query: %w", context.DeadlineExceeded)),
+			status:  http.StatusGatewayTimeout,
+			code:    CodeUpstreamTimeout,
+			message: "dgraph timed out: details withheld",
+		},
+		{
+			name:    "grpc deadline",
+			err:     dgraphtools.Upstream(status.Error(codes.DeadlineExceeded, "deadline exceeded")),
+			status:  http.StatusGatewayTimeout,
+			code:    CodeUpstreamTimeout,
+			message: "dgraph timed out: details withheld",
+		},
+		{
+			name:    "upstream",
+			err:     dgraphtools.Upstream(status.Error(codes.Unavailable, "connection refused")),
+			status:  http.StatusBadGateway,
+			code:    CodeUpstreamError,
+			message: "dgraph failed: details withheld",
+		},
+		{
+			name:    "internal",
+			err:     dgraphtools.InternalError{Err: errors.New("parsing difference")},
+			status:  http.StatusInternalServerError,
+			code:    CodeInternal,
+			message: "internal error: details withheld",
+		},
+		{
+			name:    "unknown",
+			err:     errors.New("boom"),
+			status:  http.StatusInternalServerError,
+			code:    CodeInternal,
+			message: "internal error: details withheld",
+		},
+	}
+
+	for _, e := range tests {
+		t.Run(e.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ErrorEncoder(context.Background(), e.err, w)
+
+			require.Equal(t, e.status, w.Code)
+			require.Equal(t, e.status, StatusCode(e.err))
+			require.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+			var body ErrorBody
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			require.Equal(t, e.code, body.Error.Code)
+			message := e.message
+			if message == "" {
+				message = e.err.Error()
+			}
+			require.Equal(t, message, body.Error.Message)
+			require.Equal(t, e.path, body.Error.Path)
+		})
+	}
+
+	t.Run("logging", func(t *testing.T) {
+		var logged [][]interface{}
+		logger := log.LoggerFunc(func(keyvals ...interface{}) error {
+			logged = append(logged, keyvals)
+			return nil
+		})
+
+		encode := LoggingErrorEncoder(logger)
+		encode(context.Background(), dgraphtools.Unauthorized{}, httptest.NewRecorder())
+		err := dgraphtools.InternalError{Err: errors.New("parsing difference")}
+		encode(context.Background(), err, httptest.NewRecorder())
+
+		require.Equal(t, [][]interface{}{{"code", CodeInternal, "err", err}}, logged)
+	})
+}
@@ -0,0 +1,395 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+	"mooncamp.com/dgraphtools/gql/extension"
+	"mooncamp.com/dgraphtools/qb"
+	"mooncamp.com/dgraphtools/transport/pb"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	grpctransport "github.com/go-kit/kit/transport/grpc"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Endpoints are the endpoints served over gRPC, e.g. endpoint.Query and
+// the ones of qb.EndpointSet.
+type Endpoints struct {
+	Query    endpoint.Endpoint
+	Template endpoint.Endpoint
+	Parse    endpoint.Endpoint
+}
+
+// GRPCIdentityFunc extracts the identity of a request from its context,
+// which holds the incoming metadata. Its errors should be
+// dgraphtools.Unauthenticated.
+type GRPCIdentityFunc func(ctx context.Context) (dgraphtools.Identity, error)
+
+type grpcServer struct {
+	identity      GRPCIdentityFunc
+	errorLogger   log.Logger
+	serverOptions []grpctransport.ServerOption
+
+	query    grpctransport.Handler
+	template grpctransport.Handler
+	parse    grpctransport.Handler
+}
+
+type GRPCOption func(s *grpcServer)
+
+// ExtractGRPCIdentity sets the identity of query requests. Without it
+// requests are made for the zero identity.
+func ExtractGRPCIdentity(f GRPCIdentityFunc) GRPCOption {
+	return func(s *grpcServer) {
+		s.identity = f
+	}
+}
+
+// GRPCErrorLogger logs the details of internal and upstream errors,
+// which aren't sent to clients. Without it they are dropped.
+func GRPCErrorLogger(logger log.Logger) GRPCOption {
+	return func(s *grpcServer) {
+		s.errorLogger = logger
+	}
+}
+
+// GRPCServerOptions are passed on to the go-kit servers, e.g. to run
+// jwt.GRPCToContext before the endpoints.
+func GRPCServerOptions(opts ...grpctransport.ServerOption) GRPCOption {
+	return func(s *grpcServer) {
+		s.serverOptions = append(s.serverOptions, opts...)
+	}
+}
+
+// NewGRPCServer serves eps as the DgraphTools service. Errors are
+// returned with the status codes matching the HTTP status of
+// ErrorEncoder, the path of invalid requests is a BadRequest detail.
+func NewGRPCServer(eps Endpoints, opts ...GRPCOption) pb.DgraphToolsServer {
+	s := &grpcServer{errorLogger: log.NewNopLogger()}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.query = grpctransport.NewServer(eps.Query, s.decodeQueryRequest, encodeGRPCQueryResponse, s.serverOptions...)
+	s.template = grpctransport.NewServer(eps.Template, decodeGRPCTemplateRequest, encodeGRPCTemplateResponse, s.serverOptions...)
+	s.parse = grpctransport.NewServer(eps.Parse, decodeGRPCParseRequest, encodeGRPCParseResponse, s.serverOptions...)
+	return s
+}
+
+func (s *grpcServer) Query(ctx context.Context, req *pb.QueryRequest) (*pb.QueryResponse, error) {
+	_, resp, err := s.query.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return resp.(*pb.QueryResponse), nil
+}
+
+func (s *grpcServer) Template(ctx context.Context, req *pb.TemplateRequest) (*pb.TemplateResponse, error) {
+	_, resp, err := s.template.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return resp.(*pb.TemplateResponse), nil
+}
+
+func (s *grpcServer) Parse(ctx context.Context, req *pb.ParseRequest) (*pb.ParseResponse, error) {
+	_, resp, err := s.parse.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return resp.(*pb.ParseResponse), nil
+}
+
+func (s *grpcServer) decodeQueryRequest(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.QueryRequest)
+
+	var identity dgraphtools.Identity
+	if s.identity != nil {
+		var err error
+		if identity, err = s.identity(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var proof map[uint64]gql.GraphQuery
+	if req.Proof != nil {
+		proof = make(map[uint64]gql.GraphQuery, len(req.Proof))
+		for k, v := range req.Proof {
+			proof[k] = fromPBQuery(v)
+		}
+	}
+
+	return dgraphtools.QueryRequest{
+		Identity:  identity,
+		Queries:   fromPBQueries(req.Queries),
+		Alias:     req.Alias,
+		Variables: req.Variables,
+		Proof:     proof,
+	}, nil
+}
+
+func encodeGRPCQueryResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	resp := response.(dgraphtools.QueryResponse)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &pb.QueryResponse{Json: resp.Response}, nil
+}
+
+func decodeGRPCTemplateRequest(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.TemplateRequest)
+	return qb.TemplateRequest{
+		Queries:   fromPBQueries(req.Queries),
+		Alias:     req.Alias,
+		Variables: req.Variables,
+	}, nil
+}
+
+func encodeGRPCTemplateResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	resp := response.(qb.TemplateResponse)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &pb.TemplateResponse{Query: resp.Query}, nil
+}
+
+func decodeGRPCParseRequest(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.ParseRequest)
+	return qb.ParseRequest{
+		Query:     req.Query,
+		Variables: req.Variables,
+	}, nil
+}
+
+func encodeGRPCParseResponse(ctx context.Context, response interface{}) (interface{}, error) {
+	resp := response.(qb.ParseResponse)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	queries, err := toPBQueries(resp.Queries)
+	if err != nil {
+		return nil, dgraphtools.InternalError{Err: err}
+	}
+	return &pb.ParseResponse{Queries: queries}, nil
+}
+
+// NewGRPCClient returns the endpoints of a DgraphTools service. Like
+// the served endpoints they return errors in their responses, as the
+// types of the dgraphtools package. The identity of query requests
+// isn't sent, it has to be part of the metadata, e.g. set by
+// jwt.ContextToGRPC.
+func NewGRPCClient(conn *grpc.ClientConn, opts ...grpctransport.ClientOption) Endpoints {
+	const service = "dgraphtools.DgraphTools"
+
+	query := grpctransport.NewClient(conn, service, "Query", encodeGRPCQueryRequest, decodeGRPCQueryResponse, pb.QueryResponse{}, opts...).Endpoint()
+	template := grpctransport.NewClient(conn, service, "Template", encodeGRPCTemplateRequest, decodeGRPCTemplateResponse, pb.TemplateResponse{}, opts...).Endpoint()
+	parse := grpctransport.NewClient(conn, service, "Parse", encodeGRPCParseRequest, decodeGRPCParseResponse, pb.ParseResponse{}, opts...).Endpoint()
+
+	return Endpoints{
+		Query: statusResponse(query, func(err error) interface{} {
+			return dgraphtools.QueryResponse{Error: err}
+		}),
+		Template: statusResponse(template, func(err error) interface{} {
+			return qb.TemplateResponse{Error: err}
+		}),
+		Parse: statusResponse(parse, func(err error) interface{} {
+			return qb.ParseResponse{Error: err}
+		}),
+	}
+}
+
+// statusResponse returns status errors of next as response.
+func statusResponse(next endpoint.Endpoint, response func(err error) interface{}) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		resp, err := next(ctx, request)
+		if err != nil {
+			if _, ok := status.FromError(err); ok {
+				return response(fromStatus(err)), nil
+			}
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+func encodeGRPCQueryRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(dgraphtools.QueryRequest)
+
+	queries, err := toPBQueries(req.Queries)
+	if err != nil {
+		return nil, dgraphtools.InvalidRequest{Err: err}
+	}
+
+	var proof map[uint64]*pb.GraphQuery
+	if req.Proof != nil {
+		proof = make(map[uint64]*pb.GraphQuery, len(req.Proof))
+		for k, v := range req.Proof {
+			if proof[k], err = toPBQuery(&v); err != nil {
+				return nil, dgraphtools.InvalidRequest{Err: err}
+			}
+		}
+	}
+
+	return &pb.QueryRequest{
+		Queries:   queries,
+		Alias:     req.Alias,
+		Variables: req.Variables,
+		Proof:     proof,
+	}, nil
+}
+
+func decodeGRPCQueryResponse(ctx context.Context, grpcResp interface{}) (interface{}, error) {
+	resp := grpcResp.(*pb.QueryResponse)
+	return dgraphtools.QueryResponse{Response: resp.Json}, nil
+}
+
+func encodeGRPCTemplateRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(qb.TemplateRequest)
+
+	queries, err := toPBQueries(req.Queries)
+	if err != nil {
+		return nil, dgraphtools.InvalidRequest{Err: err}
+	}
+
+	return &pb.TemplateRequest{
+		Queries:   queries,
+		Alias:     req.Alias,
+		Variables: req.Variables,
+	}, nil
+}
+
+func decodeGRPCTemplateResponse(ctx context.Context, grpcResp interface{}) (interface{}, error) {
+	resp := grpcResp.(*pb.TemplateResponse)
+	return qb.TemplateResponse{Query: resp.Query}, nil
+}
+
+func encodeGRPCParseRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(qb.ParseRequest)
+	return &pb.ParseRequest{
+		Query:     req.Query,
+		Variables: req.Variables,
+	}, nil
+}
+
+func decodeGRPCParseResponse(ctx context.Context, grpcResp interface{}) (interface{}, error) {
+	resp := grpcResp.(*pb.ParseResponse)
+	return qb.ParseResponse{Queries: fromPBQueries(resp.Queries)}, nil
+}
+
+var grpcCodes = map[string]codes.Code{
+	CodeInvalidRequest:  codes.InvalidArgument,
+	CodeUnauthenticated: codes.Unauthenticated,
+	CodeForbidden:       codes.PermissionDenied,
+	CodeExtensionFailed: codes.FailedPrecondition,
+	CodeUpstreamTimeout: codes.DeadlineExceeded,
+	CodeUpstreamError:   codes.Unavailable,
+	CodeInternal:        codes.Internal,
+}
+
+// toStatus logs the details of err like LoggingErrorEncoder and converts
+// it with statusOf.
+func (s *grpcServer) toStatus(err error) error {
+	if _, ok := status.FromError(err); !ok {
+		_, code, _ := classify(err)
+		logDetails(s.errorLogger, code, err)
+	}
+	return statusOf(err)
+}
+
+// statusOf converts err to a status error. Its message leaves out the
+// prefix of the error type, which is restored by fromStatus.
+func statusOf(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	_, code, path := classify(err)
+	c, ok := grpcCodes[code]
+	if !ok {
+		c = codes.Internal
+	}
+	err = redact(code, err)
+
+	msg := err.Error()
+	var (
+		invalid         dgraphtools.InvalidRequest
+		unauthenticated dgraphtools.Unauthenticated
+		extensionError  extension.Error
+		upstreamTimeout dgraphtools.UpstreamTimeout
+		upstreamError   dgraphtools.UpstreamError
+		internal        dgraphtools.InternalError
+	)
+	switch {
+	case errors.As(err, &invalid):
+		msg = invalid.Err.Error()
+	case errors.As(err, &unauthenticated):
+		msg = unauthenticated.Reason
+	case errors.As(err, &extensionError):
+		msg = extensionError.Err.Error()
+	case errors.As(err, &upstreamTimeout):
+		msg = upstreamTimeout.Err.Error()
+	case errors.As(err, &upstreamError):
+		msg = upstreamError.Err.Error()
+	case errors.As(err, &internal):
+		msg = internal.Err.Error()
+	}
+
+	st := status.New(c, msg)
+	if path != "" {
+		var detail proto.Message = &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: path, Description: msg}},
+		}
+		if c == codes.FailedPrecondition {
+			detail = &errdetails.PreconditionFailure{
+				Violations: []*errdetails.PreconditionFailure_Violation{{Type: code, Subject: path, Description: msg}},
+			}
+		}
+
+		if withPath, err := st.WithDetails(detail); err == nil {
+			st = withPath
+		}
+	}
+	return st.Err()
+}
+
+func fromStatus(err error) error {
+	st, _ := status.FromError(err)
+	remote := errors.New(st.Message())
+
+	switch st.Code() {
+	case codes.InvalidArgument:
+		var path string
+		for _, e := range st.Details() {
+			if br, ok := e.(*errdetails.BadRequest); ok && len(br.FieldViolations) > 0 {
+				path = br.FieldViolations[0].Field
+			}
+		}
+		return dgraphtools.InvalidRequest{Path: path, Err: remote}
+	case codes.FailedPrecondition:
+		var path string
+		for _, e := range st.Details() {
+			if pf, ok := e.(*errdetails.PreconditionFailure); ok && len(pf.Violations) > 0 {
+				path = pf.Violations[0].Subject
+			}
+		}
+		return extension.Error{Path: path, Err: remote}
+	case codes.Unauthenticated:
+		return dgraphtools.Unauthenticated{Reason: st.Message()}
+	case codes.PermissionDenied:
+		return dgraphtools.Unauthorized{}
+	case codes.DeadlineExceeded:
+		return dgraphtools.UpstreamTimeout{Err: remote}
+	case codes.Unavailable:
+		return dgraphtools.UpstreamError{Err: remote}
+	}
+	return dgraphtools.InternalError{Err: err}
+}
@@ -0,0 +1,202 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+	"mooncamp.com/dgraphtools/gql/extension"
+	"mooncamp.com/dgraphtools/policy"
+	"mooncamp.com/dgraphtools/qb"
+	qbendpoint "mooncamp.com/dgraphtools/qb/endpoint"
+	"mooncamp.com/dgraphtools/render"
+	"mooncamp.com/dgraphtools/transport/pb"
+
+	"github.com/go-kit/kit/log"
+	grpctransport "github.com/go-kit/kit/transport/grpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func grpcClient(t *testing.T, eps Endpoints, opts ...GRPCOption) Endpoints {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterDgraphToolsServer(server, NewGRPCServer(eps, opts...))
+	go func() {
+		_ = server.Serve(lis)
+	}()
+
+	conn, err := grpc.Dial("bufconn", grpc.WithInsecure(), grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return NewGRPCClient(conn, grpctransport.ClientBefore(grpctransport.SetRequestHeader("subject", "harry")))
+}
+
+const grpcTestQuery = `
+{
+  me(func: anyofterms(name, "harry"), first: 10, orderdesc: age) @filter(ge(age, 18) and not uid(0x1, 0x2)) @cascade @normalize {
+    uid
+    name@en:de
+    friends @facets(close: since) @facets(eq(close, true)) (orderasc: name) {
+      count(uid)
+      n as name
+    }
+    ~owner @groupby(kind) {
+      c as count(uid)
+    }
+    s as math(c * 2 + 1)
+    expand(_all_)
+  }
+  rec(func: uid(n)) @filter(gt(val(s), 2)) @recurse(depth: 3, loop: true) {
+    friends
+  }
+}
+`
+
+func TestGRPCTemplateAndParse(t *testing.T) {
+	set := qbendpoint.NewEndpointSet()
+	client := grpcClient(t, Endpoints{Template: set.Template, Parse: set.Parse})
+	ctx := context.Background()
+
+	expected, err := gql.Parse(gql.Request{Str: grpcTestQuery})
+	require.NoError(t, err)
+
+	resp, err := client.Parse(ctx, qb.ParseRequest{Query: grpcTestQuery})
+	require.NoError(t, err)
+	parsed := resp.(qb.ParseResponse)
+	require.NoError(t, parsed.Error)
+	require.True(t, gql.EqualQueries(expected, parsed.Queries))
+
+	parsed.Queries[0].Default = map[string]interface{}{"name": "nobody", "tags": []interface{}{"a", 1.5, nil}}
+	parsed.Queries[0].Cardinality = gql.CardinalityOptionalOne
+	parsed.Queries[0].Extensions = map[string]interface{}{"custom": true}
+
+	req, err := toPBQueries(parsed.Queries)
+	require.NoError(t, err)
+	require.Equal(t, parsed.Queries, fromPBQueries(req))
+
+	expectedQuery, err := render.Render(render.Query{Queries: expected})
+	require.NoError(t, err)
+
+	resp, err = client.Template(ctx, qb.TemplateRequest{Queries: parsed.Queries})
+	require.NoError(t, err)
+	require.Equal(t, qb.TemplateResponse{Query: expectedQuery}, resp)
+
+	resp, err = client.Parse(ctx, qb.ParseRequest{Query: "{ q(func: uid(0x1)) {"})
+	require.NoError(t, err)
+	require.IsType(t, dgraphtools.InvalidRequest{}, resp.(qb.ParseResponse).Error)
+}
+
+func TestGRPCQuery(t *testing.T) {
+	var (
+		received dgraphtools.QueryRequest
+		err      error
+	)
+	query := func(ctx context.Context, request interface{}) (interface{}, error) {
+		received = request.(dgraphtools.QueryRequest)
+		if err != nil {
+			return dgraphtools.QueryResponse{Error: err}, nil
+		}
+		return dgraphtools.QueryResponse{Response: []byte(`{"me":[{"name":"harry"}]}`)}, nil
+	}
+
+	identity := func(ctx context.Context) (dgraphtools.Identity, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get("subject")) == 0 {
+			return dgraphtools.Identity{}, dgraphtools.Unauthenticated{Reason: "missing subject"}
+		}
+		return dgraphtools.Identity{Subject: md.Get("subject")[0]}, nil
+	}
+
+	var logged []interface{}
+	logger := log.LoggerFunc(func(keyvals ...interface{}) error {
+		logged = append(logged, keyvals...)
+		return nil
+	})
+
+	client := grpcClient(t, Endpoints{Query: query}, ExtractGRPCIdentity(identity), GRPCErrorLogger(logger))
+
+	req := dgraphtools.QueryRequest{
+		Queries: []gql.GraphQuery{{
+			Alias:    "me",
+			Func:     &gql.Function{Name: "uid", UID: []uint64{5}},
+			Children: []gql.GraphQuery{{Attr: "name"}},
+		}},
+		Variables: map[string]string{"$a": "b"},
+		Proof:     map[uint64]gql.GraphQuery{5: {Func: &gql.Function{Name: "uid", UID: []uint64{1}}}},
+	}
+
+	resp, rerr := client.Query(context.Background(), req)
+	require.NoError(t, rerr)
+	require.Equal(t, dgraphtools.QueryResponse{Response: []byte(`{"me":[{"name":"harry"}]}`)}, resp)
+
+	req.Identity = dgraphtools.Identity{Subject: "harry"}
+	require.Equal(t, req, received)
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{
+			name:     "invalid request",
+			err:      dgraphtools.InvalidRequest{Path: "queries[0]", Err: errors.New("missing attr")},
+			expected: dgraphtools.InvalidRequest{Path: "queries[0]", Err: errors.New("missing attr")},
+		},
+		{
+			name:     "unauthenticated",
+			err:      dgraphtools.Unauthenticated{Reason: "token is expired"},
+			expected: dgraphtools.Unauthenticated{Reason: "token is expired"},
+		},
+		{
+			name:     "forbidden",
+			err:      policy.Denied{Kind: "predicate", Name: "email", Path: "queries[0].children[0]"},
+			expected: dgraphtools.Unauthorized{},
+		},
+		{
+			name:     "extension",
+			err:      extension.Error{Path: "queries[0]", Err: extension.CardinalityError{Path: "me", Cardinality: gql.CardinalityOne, Count: 2}},
+			expected: extension.Error{Path: "queries[0]", Err: errors.New("me: expected one node, got 2")},
+		},
+		{
+			name:     "upstream timeout",
+			err:      dgraphtools.UpstreamTimeout{Err: context.DeadlineExceeded},
+			expected: dgraphtools.UpstreamTimeout{Err: errors.New("details withheld")},
+		},
+		{
+			name:     "upstream error",
+			err:      dgraphtools.UpstreamError{Err: errors.New("connection refused")},
+			expected: dgraphtools.UpstreamError{Err: errors.New("details withheld")},
+		},
+	}
+
+	for _, e := range tests {
+		t.Run(e.name, func(t *testing.T) {
+			err = e.err
+			resp, rerr := client.Query(context.Background(), req)
+			require.NoError(t, rerr)
+			require.Equal(t, e.expected, resp.(dgraphtools.QueryResponse).Error)
+		})
+	}
+
+	logged = nil
+	err = errors.New("boom")
+	resp, rerr = client.Query(context.Background(), req)
+	require.NoError(t, rerr)
+	require.IsType(t, dgraphtools.InternalError{}, resp.(dgraphtools.QueryResponse).Error)
+	require.NotContains(t, resp.(dgraphtools.QueryResponse).Error.Error(), "boom")
+	require.Equal(t, []interface{}{"code", CodeInternal, "err", err}, logged)
+}
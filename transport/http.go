@@ -0,0 +1,270 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mooncamp.com/dgraphtools"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+// DefaultMaxRequestSize is the limit of request bodies unless set by
+// MaxRequestSize.
+const DefaultMaxRequestSize = 1 << 20
+
+// IdentityFunc extracts the identity of a request. Its errors should be
+// dgraphtools.Unauthenticated.
+type IdentityFunc func(ctx context.Context, r *http.Request) (dgraphtools.Identity, error)
+
+type httpHandler struct {
+	identity       IdentityFunc
+	maxRequestSize int64
+	errorEncoder   httptransport.ErrorEncoder
+	serverOptions  []httptransport.ServerOption
+}
+
+type HTTPOption func(h *httpHandler)
+
+// ExtractIdentity sets the identity of requests. Without it requests
+// are made for the zero identity, e.g. to be set by a middleware like
+// auth.JWT.Middleware.
+func ExtractIdentity(f IdentityFunc) HTTPOption {
+	return func(h *httpHandler) {
+		h.identity = f
+	}
+}
+
+// MaxRequestSize limits request bodies to n bytes, larger ones are
+// answered with 413. n <= 0 removes the limit.
+func MaxRequestSize(n int64) HTTPOption {
+	return func(h *httpHandler) {
+		h.maxRequestSize = n
+	}
+}
+
+// ErrorLogger logs the details of internal and upstream errors, which
+// aren't sent to clients. Without it they are dropped.
+func ErrorLogger(logger log.Logger) HTTPOption {
+	return func(h *httpHandler) {
+		h.errorEncoder = LoggingErrorEncoder(logger)
+	}
+}
+
+// ServerOptions are passed on to the go-kit server, e.g. to run
+// jwt.HTTPToContext before the endpoint.
+func ServerOptions(opts ...httptransport.ServerOption) HTTPOption {
+	return func(h *httpHandler) {
+		h.serverOptions = append(h.serverOptions, opts...)
+	}
+}
+
+// NewHTTPHandler serves the query endpoint, e.g. endpoint.Query, over
+// HTTP. Requests are POSTed as QueryRequestV1 with the content type
+// MediaTypeJSON or MediaTypeQueryV1, the response uses the one the
+// client prefers. Errors are written by ErrorEncoder.
+func NewHTTPHandler(ep endpoint.Endpoint, opts ...HTTPOption) http.Handler {
+	h := &httpHandler{maxRequestSize: DefaultMaxRequestSize, errorEncoder: ErrorEncoder}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	serverOptions := append([]httptransport.ServerOption{
+		httptransport.ServerErrorEncoder(h.errorEncoder),
+	}, h.serverOptions...)
+	server := httptransport.NewServer(ep, h.decodeQueryRequest, h.encodeQueryResponse, serverOptions...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			h.errorEncoder(r.Context(), MethodNotAllowed{Method: r.Method}, w)
+			return
+		}
+
+		if err := checkContentType(r.Header.Get("Content-Type")); err != nil {
+			h.errorEncoder(r.Context(), err, w)
+			return
+		}
+
+		mediaType, err := negotiate(r.Header.Get("Accept"))
+		if err != nil {
+			h.errorEncoder(r.Context(), err, w)
+			return
+		}
+
+		if h.maxRequestSize > 0 {
+			r.Body = &limitedBody{ReadCloser: r.Body, remaining: h.maxRequestSize, limit: h.maxRequestSize}
+		}
+
+		ctx := context.WithValue(r.Context(), mediaTypeKey{}, mediaType)
+		server.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (h *httpHandler) decodeQueryRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req QueryRequestV1
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge RequestTooLarge
+		if errors.As(err, &tooLarge) {
+			return nil, tooLarge
+		}
+		return nil, dgraphtools.InvalidRequest{Err: err}
+	}
+
+	var identity dgraphtools.Identity
+	if h.identity != nil {
+		var err error
+		if identity, err = h.identity(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	return dgraphtools.QueryRequest{
+		Identity:  identity,
+		Queries:   req.Queries,
+		Alias:     req.Alias,
+		Variables: req.Variables,
+		Proof:     req.Proof,
+	}, nil
+}
+
+func (h *httpHandler) encodeQueryResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp := response.(dgraphtools.QueryResponse)
+	if resp.Error != nil {
+		h.errorEncoder(ctx, resp.Error, w)
+		return nil
+	}
+
+	mediaType, _ := ctx.Value(mediaTypeKey{}).(string)
+	if mediaType == "" {
+		mediaType = MediaTypeJSON
+	}
+
+	w.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+	_, err := w.Write(resp.Response)
+	return err
+}
+
+// BearerToken returns the token of an "Authorization: Bearer" header.
+func BearerToken(r *http.Request) (string, bool) {
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") || parts[1] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+type mediaTypeKey struct{}
+
+func checkContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != MediaTypeJSON && mediaType != MediaTypeQueryV1) {
+		return UnsupportedMediaType{MediaType: contentType}
+	}
+	return nil
+}
+
+// negotiate returns the media type the response is written in: the
+// one of highest quality in accept, the earlier one on equal quality.
+// Media types with q=0 are refused, even if a wildcard matches them.
+func negotiate(accept string) (string, error) {
+	if strings.TrimSpace(accept) == "" {
+		return MediaTypeJSON, nil
+	}
+
+	type match struct {
+		specificity int
+		quality     float64
+		index       int
+	}
+	matches := map[string]match{}
+
+	for i, e := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(e))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+		}
+
+		// The most specific media range decides the quality of a media
+		// type.
+		for _, offer := range []string{MediaTypeJSON, MediaTypeQueryV1} {
+			specificity := -1
+			switch mediaType {
+			case offer:
+				specificity = 2
+			case "application/*":
+				specificity = 1
+			case "*/*":
+				specificity = 0
+			}
+
+			if m, ok := matches[offer]; specificity < 0 || (ok && m.specificity >= specificity) {
+				continue
+			}
+			matches[offer] = match{specificity: specificity, quality: quality, index: i}
+		}
+	}
+
+	res := ""
+	for _, offer := range []string{MediaTypeJSON, MediaTypeQueryV1} {
+		m, ok := matches[offer]
+		if !ok || m.quality == 0 {
+			continue
+		}
+
+		if best, ok := matches[res]; !ok || m.quality > best.quality || (m.quality == best.quality && m.index < best.index) {
+			res = offer
+		}
+	}
+
+	if res == "" {
+		return "", NotAcceptable{Accept: accept}
+	}
+	return res, nil
+}
+
+// limitedBody fails with RequestTooLarge once more than limit bytes are
+// read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, RequestTooLarge{Limit: b.limit}
+	}
+
+	// Reading one byte more than allowed tells a body of exactly limit
+	// bytes from a larger one.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), RequestTooLarge{Limit: b.limit}
+	}
+	return n, err
+}
@@ -0,0 +1,173 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mooncamp.com/dgraphtools"
+	"mooncamp.com/dgraphtools/gql"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPHandler(t *testing.T) {
+	var received dgraphtools.QueryRequest
+	ep := func(ctx context.Context, request interface{}) (interface{}, error) {
+		received = request.(dgraphtools.QueryRequest)
+		if received.Alias == "fail" {
+			return dgraphtools.QueryResponse{Error: dgraphtools.Unauthorized{}}, nil
+		}
+		return dgraphtools.QueryResponse{Response: []byte(`{"me":[{"name":"harry"}]}`)}, nil
+	}
+
+	identity := func(ctx context.Context, r *http.Request) (dgraphtools.Identity, error) {
+		token, ok := BearerToken(r)
+		if !ok {
+			return dgraphtools.Identity{}, dgraphtools.Unauthenticated{Reason: "missing token"}
+		}
+		return dgraphtools.Identity{Subject: token}, nil
+	}
+
+	handler := NewHTTPHandler(ep, ExtractIdentity(identity), MaxRequestSize(512))
+
+	body := `{"queries": [{"alias": "me", "func": {"name": "uid", "uid": [5]}, "children": [{"attr": "name"}]}], "variables": {"$a": "b"}, "proof": {"5": {"func": {"name": "uid", "uid": [1]}}}}`
+
+	tests := []struct {
+		name        string
+		method      string
+		header      map[string]string
+		body        string
+		status      int
+		contentType string
+		code        string
+	}{
+		{
+			name:        "json",
+			header:      map[string]string{"Content-Type": "application/json; charset=utf-8"},
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+		},
+		{
+			name:        "versioned",
+			header:      map[string]string{"Content-Type": MediaTypeQueryV1, "Accept": "text/html, " + MediaTypeQueryV1},
+			status:      http.StatusOK,
+			contentType: MediaTypeQueryV1 + "; charset=utf-8",
+		},
+		{
+			name:        "any",
+			header:      map[string]string{"Accept": "*/*"},
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+		},
+		{
+			name:        "quality",
+			header:      map[string]string{"Accept": "application/json;q=0.5, " + MediaTypeQueryV1},
+			status:      http.StatusOK,
+			contentType: MediaTypeQueryV1 + "; charset=utf-8",
+		},
+		{
+			name:        "refused with wildcard",
+			header:      map[string]string{"Accept": "application/json;q=0, */*;q=0.1"},
+			status:      http.StatusOK,
+			contentType: MediaTypeQueryV1 + "; charset=utf-8",
+		},
+		{
+			name:   "refused",
+			header: map[string]string{"Accept": "application/json;q=0"},
+			status: http.StatusNotAcceptable,
+			code:   CodeNotAcceptable,
+		},
+		{
+			name:   "not acceptable",
+			header: map[string]string{"Accept": "text/html"},
+			status: http.StatusNotAcceptable,
+			code:   CodeNotAcceptable,
+		},
+		{
+			name:   "unsupported media type",
+			header: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			status: http.StatusUnsupportedMediaType,
+			code:   CodeUnsupportedMediaType,
+		},
+		{
+			name:   "method",
+			method: http.MethodGet,
+			status: http.StatusMethodNotAllowed,
+			code:   CodeMethodNotAllowed,
+		},
+		{
+			name:   "too large",
+			body:   `{"queries": [], "alias": "` + strings.Repeat("a", 512) + `"}`,
+			status: http.StatusRequestEntityTooLarge,
+			code:   CodeRequestTooLarge,
+		},
+		{
+			name:   "malformed",
+			body:   `{"queries": {}}`,
+			status: http.StatusBadRequest,
+			code:   CodeInvalidRequest,
+		},
+		{
+			name:   "unauthenticated",
+			header: map[string]string{"Authorization": ""},
+			status: http.StatusUnauthorized,
+			code:   CodeUnauthenticated,
+		},
+		{
+			name:   "endpoint error",
+			body:   `{"queries": [], "alias": "fail"}`,
+			status: http.StatusForbidden,
+			code:   CodeForbidden,
+		},
+	}
+
+	for _, e := range tests {
+		t.Run(e.name, func(t *testing.T) {
+			received = dgraphtools.QueryRequest{}
+
+			method, reqBody := e.method, e.body
+			if method == "" {
+				method = http.MethodPost
+			}
+			if reqBody == "" {
+				reqBody = body
+			}
+
+			r := httptest.NewRequest(method, "/query", strings.NewReader(reqBody))
+			r.Header.Set("Authorization", "Bearer harry")
+			for k, v := range e.header {
+				r.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			require.Equal(t, e.status, w.Code, w.Body.String())
+
+			if e.code != "" {
+				var errBody ErrorBody
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&errBody))
+				require.Equal(t, e.code, errBody.Error.Code)
+				return
+			}
+
+			require.Equal(t, e.contentType, w.Header().Get("Content-Type"))
+			require.JSONEq(t, `{"me":[{"name":"harry"}]}`, w.Body.String())
+
+			expected := dgraphtools.QueryRequest{
+				Identity: dgraphtools.Identity{Subject: "harry"},
+				Queries: []gql.GraphQuery{{
+					Alias:    "me",
+					Func:     &gql.Function{Name: "uid", UID: []uint64{5}},
+					Children: []gql.GraphQuery{{Attr: "name"}},
+				}},
+				Variables: map[string]string{"$a": "b"},
+				Proof:     map[uint64]gql.GraphQuery{5: {Func: &gql.Function{Name: "uid", UID: []uint64{1}}}},
+			}
+			require.Equal(t, expected, received)
+		})
+	}
+}
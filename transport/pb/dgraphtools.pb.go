@@ -0,0 +1,1478 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: dgraphtools.proto
+
+package pb
+
+/*
+Messages and service of the gRPC transport. The messages mirror the
+types of the gql package, see there for their documentation.
+*/
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import _struct "github.com/golang/protobuf/ptypes/struct"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type QueryRequest struct {
+	Queries              []*GraphQuery          `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries,omitempty"`
+	Alias                string                 `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	Variables            map[string]string      `protobuf:"bytes,3,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Proof                map[uint64]*GraphQuery `protobuf:"bytes,4,rep,name=proof,proto3" json:"proof,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *QueryRequest) Reset()         { *m = QueryRequest{} }
+func (m *QueryRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryRequest) ProtoMessage()    {}
+func (*QueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{0}
+}
+func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryRequest.Unmarshal(m, b)
+}
+func (m *QueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryRequest.Marshal(b, m, deterministic)
+}
+func (dst *QueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryRequest.Merge(dst, src)
+}
+func (m *QueryRequest) XXX_Size() int {
+	return xxx_messageInfo_QueryRequest.Size(m)
+}
+func (m *QueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryRequest proto.InternalMessageInfo
+
+func (m *QueryRequest) GetQueries() []*GraphQuery {
+	if m != nil {
+		return m.Queries
+	}
+	return nil
+}
+
+func (m *QueryRequest) GetAlias() string {
+	if m != nil {
+		return m.Alias
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetVariables() map[string]string {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+func (m *QueryRequest) GetProof() map[uint64]*GraphQuery {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+type QueryResponse struct {
+	Json                 []byte   `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
+func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryResponse) ProtoMessage()    {}
+func (*QueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{1}
+}
+func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryResponse.Unmarshal(m, b)
+}
+func (m *QueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryResponse.Marshal(b, m, deterministic)
+}
+func (dst *QueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryResponse.Merge(dst, src)
+}
+func (m *QueryResponse) XXX_Size() int {
+	return xxx_messageInfo_QueryResponse.Size(m)
+}
+func (m *QueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryResponse proto.InternalMessageInfo
+
+func (m *QueryResponse) GetJson() []byte {
+	if m != nil {
+		return m.Json
+	}
+	return nil
+}
+
+type TemplateRequest struct {
+	Queries              []*GraphQuery     `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries,omitempty"`
+	Alias                string            `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	Variables            map[string]string `protobuf:"bytes,3,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *TemplateRequest) Reset()         { *m = TemplateRequest{} }
+func (m *TemplateRequest) String() string { return proto.CompactTextString(m) }
+func (*TemplateRequest) ProtoMessage()    {}
+func (*TemplateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{2}
+}
+func (m *TemplateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TemplateRequest.Unmarshal(m, b)
+}
+func (m *TemplateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TemplateRequest.Marshal(b, m, deterministic)
+}
+func (dst *TemplateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TemplateRequest.Merge(dst, src)
+}
+func (m *TemplateRequest) XXX_Size() int {
+	return xxx_messageInfo_TemplateRequest.Size(m)
+}
+func (m *TemplateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TemplateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TemplateRequest proto.InternalMessageInfo
+
+func (m *TemplateRequest) GetQueries() []*GraphQuery {
+	if m != nil {
+		return m.Queries
+	}
+	return nil
+}
+
+func (m *TemplateRequest) GetAlias() string {
+	if m != nil {
+		return m.Alias
+	}
+	return ""
+}
+
+func (m *TemplateRequest) GetVariables() map[string]string {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+type TemplateResponse struct {
+	Query                string   `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TemplateResponse) Reset()         { *m = TemplateResponse{} }
+func (m *TemplateResponse) String() string { return proto.CompactTextString(m) }
+func (*TemplateResponse) ProtoMessage()    {}
+func (*TemplateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{3}
+}
+func (m *TemplateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TemplateResponse.Unmarshal(m, b)
+}
+func (m *TemplateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TemplateResponse.Marshal(b, m, deterministic)
+}
+func (dst *TemplateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TemplateResponse.Merge(dst, src)
+}
+func (m *TemplateResponse) XXX_Size() int {
+	return xxx_messageInfo_TemplateResponse.Size(m)
+}
+func (m *TemplateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TemplateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TemplateResponse proto.InternalMessageInfo
+
+func (m *TemplateResponse) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+type ParseRequest struct {
+	Query                string            `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Variables            map[string]string `protobuf:"bytes,2,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ParseRequest) Reset()         { *m = ParseRequest{} }
+func (m *ParseRequest) String() string { return proto.CompactTextString(m) }
+func (*ParseRequest) ProtoMessage()    {}
+func (*ParseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{4}
+}
+func (m *ParseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ParseRequest.Unmarshal(m, b)
+}
+func (m *ParseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ParseRequest.Marshal(b, m, deterministic)
+}
+func (dst *ParseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParseRequest.Merge(dst, src)
+}
+func (m *ParseRequest) XXX_Size() int {
+	return xxx_messageInfo_ParseRequest.Size(m)
+}
+func (m *ParseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParseRequest proto.InternalMessageInfo
+
+func (m *ParseRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *ParseRequest) GetVariables() map[string]string {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+type ParseResponse struct {
+	Queries              []*GraphQuery `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ParseResponse) Reset()         { *m = ParseResponse{} }
+func (m *ParseResponse) String() string { return proto.CompactTextString(m) }
+func (*ParseResponse) ProtoMessage()    {}
+func (*ParseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{5}
+}
+func (m *ParseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ParseResponse.Unmarshal(m, b)
+}
+func (m *ParseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ParseResponse.Marshal(b, m, deterministic)
+}
+func (dst *ParseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParseResponse.Merge(dst, src)
+}
+func (m *ParseResponse) XXX_Size() int {
+	return xxx_messageInfo_ParseResponse.Size(m)
+}
+func (m *ParseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParseResponse proto.InternalMessageInfo
+
+func (m *ParseResponse) GetQueries() []*GraphQuery {
+	if m != nil {
+		return m.Queries
+	}
+	return nil
+}
+
+type GraphQuery struct {
+	Uid                  []uint64                  `protobuf:"varint,1,rep,packed,name=uid,proto3" json:"uid,omitempty"`
+	Attr                 string                    `protobuf:"bytes,2,opt,name=attr,proto3" json:"attr,omitempty"`
+	Langs                []string                  `protobuf:"bytes,3,rep,name=langs,proto3" json:"langs,omitempty"`
+	Alias                string                    `protobuf:"bytes,4,opt,name=alias,proto3" json:"alias,omitempty"`
+	Default              *_struct.Value            `protobuf:"bytes,5,opt,name=default,proto3" json:"default,omitempty"`
+	IsCount              bool                      `protobuf:"varint,6,opt,name=is_count,json=isCount,proto3" json:"is_count,omitempty"`
+	IsInternal           bool                      `protobuf:"varint,7,opt,name=is_internal,json=isInternal,proto3" json:"is_internal,omitempty"`
+	IsGroupby            bool                      `protobuf:"varint,8,opt,name=is_groupby,json=isGroupby,proto3" json:"is_groupby,omitempty"`
+	Var                  string                    `protobuf:"bytes,9,opt,name=var,proto3" json:"var,omitempty"`
+	NeedsVar             []*VarContext             `protobuf:"bytes,10,rep,name=needs_var,json=needsVar,proto3" json:"needs_var,omitempty"`
+	Func                 *Function                 `protobuf:"bytes,11,opt,name=func,proto3" json:"func,omitempty"`
+	Expand               string                    `protobuf:"bytes,12,opt,name=expand,proto3" json:"expand,omitempty"`
+	Args                 map[string]string         `protobuf:"bytes,13,rep,name=args,proto3" json:"args,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Order                []*Order                  `protobuf:"bytes,14,rep,name=order,proto3" json:"order,omitempty"`
+	Children             []*GraphQuery             `protobuf:"bytes,15,rep,name=children,proto3" json:"children,omitempty"`
+	Filter               *FilterTree               `protobuf:"bytes,16,opt,name=filter,proto3" json:"filter,omitempty"`
+	MathExp              *MathTree                 `protobuf:"bytes,17,opt,name=math_exp,json=mathExp,proto3" json:"math_exp,omitempty"`
+	Normalize            bool                      `protobuf:"varint,18,opt,name=normalize,proto3" json:"normalize,omitempty"`
+	Recurse              bool                      `protobuf:"varint,19,opt,name=recurse,proto3" json:"recurse,omitempty"`
+	RecurseArgs          *RecurseArgs              `protobuf:"bytes,20,opt,name=recurse_args,json=recurseArgs,proto3" json:"recurse_args,omitempty"`
+	Cascade              bool                      `protobuf:"varint,21,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	CascadeFields        []string                  `protobuf:"bytes,22,rep,name=cascade_fields,json=cascadeFields,proto3" json:"cascade_fields,omitempty"`
+	IgnoreReflex         bool                      `protobuf:"varint,23,opt,name=ignore_reflex,json=ignoreReflex,proto3" json:"ignore_reflex,omitempty"`
+	Facets               *FacetParams              `protobuf:"bytes,24,opt,name=facets,proto3" json:"facets,omitempty"`
+	FacetsFilter         *FilterTree               `protobuf:"bytes,25,opt,name=facets_filter,json=facetsFilter,proto3" json:"facets_filter,omitempty"`
+	GroupbyAttrs         []*GroupByAttr            `protobuf:"bytes,26,rep,name=groupby_attrs,json=groupbyAttrs,proto3" json:"groupby_attrs,omitempty"`
+	FacetVar             map[string]string         `protobuf:"bytes,27,rep,name=facet_var,json=facetVar,proto3" json:"facet_var,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	FacetOrder           string                    `protobuf:"bytes,28,opt,name=facet_order,json=facetOrder,proto3" json:"facet_order,omitempty"`
+	FacetDesc            bool                      `protobuf:"varint,29,opt,name=facet_desc,json=facetDesc,proto3" json:"facet_desc,omitempty"`
+	UidCount             bool                      `protobuf:"varint,30,opt,name=uid_count,json=uidCount,proto3" json:"uid_count,omitempty"`
+	UidCountAlias        string                    `protobuf:"bytes,31,opt,name=uid_count_alias,json=uidCountAlias,proto3" json:"uid_count_alias,omitempty"`
+	Cardinality          string                    `protobuf:"bytes,32,opt,name=cardinality,proto3" json:"cardinality,omitempty"`
+	Extensions           map[string]*_struct.Value `protobuf:"bytes,33,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *GraphQuery) Reset()         { *m = GraphQuery{} }
+func (m *GraphQuery) String() string { return proto.CompactTextString(m) }
+func (*GraphQuery) ProtoMessage()    {}
+func (*GraphQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{6}
+}
+func (m *GraphQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GraphQuery.Unmarshal(m, b)
+}
+func (m *GraphQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GraphQuery.Marshal(b, m, deterministic)
+}
+func (dst *GraphQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GraphQuery.Merge(dst, src)
+}
+func (m *GraphQuery) XXX_Size() int {
+	return xxx_messageInfo_GraphQuery.Size(m)
+}
+func (m *GraphQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_GraphQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GraphQuery proto.InternalMessageInfo
+
+func (m *GraphQuery) GetUid() []uint64 {
+	if m != nil {
+		return m.Uid
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetAttr() string {
+	if m != nil {
+		return m.Attr
+	}
+	return ""
+}
+
+func (m *GraphQuery) GetLangs() []string {
+	if m != nil {
+		return m.Langs
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetAlias() string {
+	if m != nil {
+		return m.Alias
+	}
+	return ""
+}
+
+func (m *GraphQuery) GetDefault() *_struct.Value {
+	if m != nil {
+		return m.Default
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetIsCount() bool {
+	if m != nil {
+		return m.IsCount
+	}
+	return false
+}
+
+func (m *GraphQuery) GetIsInternal() bool {
+	if m != nil {
+		return m.IsInternal
+	}
+	return false
+}
+
+func (m *GraphQuery) GetIsGroupby() bool {
+	if m != nil {
+		return m.IsGroupby
+	}
+	return false
+}
+
+func (m *GraphQuery) GetVar() string {
+	if m != nil {
+		return m.Var
+	}
+	return ""
+}
+
+func (m *GraphQuery) GetNeedsVar() []*VarContext {
+	if m != nil {
+		return m.NeedsVar
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetFunc() *Function {
+	if m != nil {
+		return m.Func
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetExpand() string {
+	if m != nil {
+		return m.Expand
+	}
+	return ""
+}
+
+func (m *GraphQuery) GetArgs() map[string]string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetOrder() []*Order {
+	if m != nil {
+		return m.Order
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetChildren() []*GraphQuery {
+	if m != nil {
+		return m.Children
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetFilter() *FilterTree {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetMathExp() *MathTree {
+	if m != nil {
+		return m.MathExp
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetNormalize() bool {
+	if m != nil {
+		return m.Normalize
+	}
+	return false
+}
+
+func (m *GraphQuery) GetRecurse() bool {
+	if m != nil {
+		return m.Recurse
+	}
+	return false
+}
+
+func (m *GraphQuery) GetRecurseArgs() *RecurseArgs {
+	if m != nil {
+		return m.RecurseArgs
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetCascade() bool {
+	if m != nil {
+		return m.Cascade
+	}
+	return false
+}
+
+func (m *GraphQuery) GetCascadeFields() []string {
+	if m != nil {
+		return m.CascadeFields
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetIgnoreReflex() bool {
+	if m != nil {
+		return m.IgnoreReflex
+	}
+	return false
+}
+
+func (m *GraphQuery) GetFacets() *FacetParams {
+	if m != nil {
+		return m.Facets
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetFacetsFilter() *FilterTree {
+	if m != nil {
+		return m.FacetsFilter
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetGroupbyAttrs() []*GroupByAttr {
+	if m != nil {
+		return m.GroupbyAttrs
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetFacetVar() map[string]string {
+	if m != nil {
+		return m.FacetVar
+	}
+	return nil
+}
+
+func (m *GraphQuery) GetFacetOrder() string {
+	if m != nil {
+		return m.FacetOrder
+	}
+	return ""
+}
+
+func (m *GraphQuery) GetFacetDesc() bool {
+	if m != nil {
+		return m.FacetDesc
+	}
+	return false
+}
+
+func (m *GraphQuery) GetUidCount() bool {
+	if m != nil {
+		return m.UidCount
+	}
+	return false
+}
+
+func (m *GraphQuery) GetUidCountAlias() string {
+	if m != nil {
+		return m.UidCountAlias
+	}
+	return ""
+}
+
+func (m *GraphQuery) GetCardinality() string {
+	if m != nil {
+		return m.Cardinality
+	}
+	return ""
+}
+
+func (m *GraphQuery) GetExtensions() map[string]*_struct.Value {
+	if m != nil {
+		return m.Extensions
+	}
+	return nil
+}
+
+type RecurseArgs struct {
+	Depth                uint64   `protobuf:"varint,1,opt,name=depth,proto3" json:"depth,omitempty"`
+	AllowLoop            bool     `protobuf:"varint,2,opt,name=allow_loop,json=allowLoop,proto3" json:"allow_loop,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecurseArgs) Reset()         { *m = RecurseArgs{} }
+func (m *RecurseArgs) String() string { return proto.CompactTextString(m) }
+func (*RecurseArgs) ProtoMessage()    {}
+func (*RecurseArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{7}
+}
+func (m *RecurseArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecurseArgs.Unmarshal(m, b)
+}
+func (m *RecurseArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecurseArgs.Marshal(b, m, deterministic)
+}
+func (dst *RecurseArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecurseArgs.Merge(dst, src)
+}
+func (m *RecurseArgs) XXX_Size() int {
+	return xxx_messageInfo_RecurseArgs.Size(m)
+}
+func (m *RecurseArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecurseArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecurseArgs proto.InternalMessageInfo
+
+func (m *RecurseArgs) GetDepth() uint64 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+func (m *RecurseArgs) GetAllowLoop() bool {
+	if m != nil {
+		return m.AllowLoop
+	}
+	return false
+}
+
+type FacetParams struct {
+	AllKeys              bool          `protobuf:"varint,1,opt,name=all_keys,json=allKeys,proto3" json:"all_keys,omitempty"`
+	Param                []*FacetParam `protobuf:"bytes,2,rep,name=param,proto3" json:"param,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *FacetParams) Reset()         { *m = FacetParams{} }
+func (m *FacetParams) String() string { return proto.CompactTextString(m) }
+func (*FacetParams) ProtoMessage()    {}
+func (*FacetParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{8}
+}
+func (m *FacetParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FacetParams.Unmarshal(m, b)
+}
+func (m *FacetParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FacetParams.Marshal(b, m, deterministic)
+}
+func (dst *FacetParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FacetParams.Merge(dst, src)
+}
+func (m *FacetParams) XXX_Size() int {
+	return xxx_messageInfo_FacetParams.Size(m)
+}
+func (m *FacetParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_FacetParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FacetParams proto.InternalMessageInfo
+
+func (m *FacetParams) GetAllKeys() bool {
+	if m != nil {
+		return m.AllKeys
+	}
+	return false
+}
+
+func (m *FacetParams) GetParam() []*FacetParam {
+	if m != nil {
+		return m.Param
+	}
+	return nil
+}
+
+type FacetParam struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Alias                string   `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FacetParam) Reset()         { *m = FacetParam{} }
+func (m *FacetParam) String() string { return proto.CompactTextString(m) }
+func (*FacetParam) ProtoMessage()    {}
+func (*FacetParam) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{9}
+}
+func (m *FacetParam) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FacetParam.Unmarshal(m, b)
+}
+func (m *FacetParam) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FacetParam.Marshal(b, m, deterministic)
+}
+func (dst *FacetParam) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FacetParam.Merge(dst, src)
+}
+func (m *FacetParam) XXX_Size() int {
+	return xxx_messageInfo_FacetParam.Size(m)
+}
+func (m *FacetParam) XXX_DiscardUnknown() {
+	xxx_messageInfo_FacetParam.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FacetParam proto.InternalMessageInfo
+
+func (m *FacetParam) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *FacetParam) GetAlias() string {
+	if m != nil {
+		return m.Alias
+	}
+	return ""
+}
+
+type Order struct {
+	Attr                 string   `protobuf:"bytes,1,opt,name=attr,proto3" json:"attr,omitempty"`
+	Desc                 bool     `protobuf:"varint,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	Langs                []string `protobuf:"bytes,3,rep,name=langs,proto3" json:"langs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Order) Reset()         { *m = Order{} }
+func (m *Order) String() string { return proto.CompactTextString(m) }
+func (*Order) ProtoMessage()    {}
+func (*Order) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{10}
+}
+func (m *Order) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Order.Unmarshal(m, b)
+}
+func (m *Order) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Order.Marshal(b, m, deterministic)
+}
+func (dst *Order) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Order.Merge(dst, src)
+}
+func (m *Order) XXX_Size() int {
+	return xxx_messageInfo_Order.Size(m)
+}
+func (m *Order) XXX_DiscardUnknown() {
+	xxx_messageInfo_Order.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Order proto.InternalMessageInfo
+
+func (m *Order) GetAttr() string {
+	if m != nil {
+		return m.Attr
+	}
+	return ""
+}
+
+func (m *Order) GetDesc() bool {
+	if m != nil {
+		return m.Desc
+	}
+	return false
+}
+
+func (m *Order) GetLangs() []string {
+	if m != nil {
+		return m.Langs
+	}
+	return nil
+}
+
+type VarContext struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Typ                  int64    `protobuf:"varint,2,opt,name=typ,proto3" json:"typ,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VarContext) Reset()         { *m = VarContext{} }
+func (m *VarContext) String() string { return proto.CompactTextString(m) }
+func (*VarContext) ProtoMessage()    {}
+func (*VarContext) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{11}
+}
+func (m *VarContext) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VarContext.Unmarshal(m, b)
+}
+func (m *VarContext) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VarContext.Marshal(b, m, deterministic)
+}
+func (dst *VarContext) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VarContext.Merge(dst, src)
+}
+func (m *VarContext) XXX_Size() int {
+	return xxx_messageInfo_VarContext.Size(m)
+}
+func (m *VarContext) XXX_DiscardUnknown() {
+	xxx_messageInfo_VarContext.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VarContext proto.InternalMessageInfo
+
+func (m *VarContext) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *VarContext) GetTyp() int64 {
+	if m != nil {
+		return m.Typ
+	}
+	return 0
+}
+
+type Function struct {
+	Attr                 string        `protobuf:"bytes,1,opt,name=attr,proto3" json:"attr,omitempty"`
+	Lang                 string        `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"`
+	Name                 string        `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Args                 []*Arg        `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty"`
+	Uid                  []uint64      `protobuf:"varint,5,rep,packed,name=uid,proto3" json:"uid,omitempty"`
+	NeedsVar             []*VarContext `protobuf:"bytes,6,rep,name=needs_var,json=needsVar,proto3" json:"needs_var,omitempty"`
+	IsCount              bool          `protobuf:"varint,7,opt,name=is_count,json=isCount,proto3" json:"is_count,omitempty"`
+	IsValueVar           bool          `protobuf:"varint,8,opt,name=is_value_var,json=isValueVar,proto3" json:"is_value_var,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *Function) Reset()         { *m = Function{} }
+func (m *Function) String() string { return proto.CompactTextString(m) }
+func (*Function) ProtoMessage()    {}
+func (*Function) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{12}
+}
+func (m *Function) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Function.Unmarshal(m, b)
+}
+func (m *Function) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Function.Marshal(b, m, deterministic)
+}
+func (dst *Function) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Function.Merge(dst, src)
+}
+func (m *Function) XXX_Size() int {
+	return xxx_messageInfo_Function.Size(m)
+}
+func (m *Function) XXX_DiscardUnknown() {
+	xxx_messageInfo_Function.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Function proto.InternalMessageInfo
+
+func (m *Function) GetAttr() string {
+	if m != nil {
+		return m.Attr
+	}
+	return ""
+}
+
+func (m *Function) GetLang() string {
+	if m != nil {
+		return m.Lang
+	}
+	return ""
+}
+
+func (m *Function) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Function) GetArgs() []*Arg {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+func (m *Function) GetUid() []uint64 {
+	if m != nil {
+		return m.Uid
+	}
+	return nil
+}
+
+func (m *Function) GetNeedsVar() []*VarContext {
+	if m != nil {
+		return m.NeedsVar
+	}
+	return nil
+}
+
+func (m *Function) GetIsCount() bool {
+	if m != nil {
+		return m.IsCount
+	}
+	return false
+}
+
+func (m *Function) GetIsValueVar() bool {
+	if m != nil {
+		return m.IsValueVar
+	}
+	return false
+}
+
+type Arg struct {
+	Value                string   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	IsValueVar           bool     `protobuf:"varint,2,opt,name=is_value_var,json=isValueVar,proto3" json:"is_value_var,omitempty"`
+	IsGraphQlVar         bool     `protobuf:"varint,3,opt,name=is_graph_ql_var,json=isGraphQlVar,proto3" json:"is_graph_ql_var,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Arg) Reset()         { *m = Arg{} }
+func (m *Arg) String() string { return proto.CompactTextString(m) }
+func (*Arg) ProtoMessage()    {}
+func (*Arg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{13}
+}
+func (m *Arg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Arg.Unmarshal(m, b)
+}
+func (m *Arg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Arg.Marshal(b, m, deterministic)
+}
+func (dst *Arg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Arg.Merge(dst, src)
+}
+func (m *Arg) XXX_Size() int {
+	return xxx_messageInfo_Arg.Size(m)
+}
+func (m *Arg) XXX_DiscardUnknown() {
+	xxx_messageInfo_Arg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Arg proto.InternalMessageInfo
+
+func (m *Arg) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *Arg) GetIsValueVar() bool {
+	if m != nil {
+		return m.IsValueVar
+	}
+	return false
+}
+
+func (m *Arg) GetIsGraphQlVar() bool {
+	if m != nil {
+		return m.IsGraphQlVar
+	}
+	return false
+}
+
+type FilterTree struct {
+	Op                   string        `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	Child                []*FilterTree `protobuf:"bytes,2,rep,name=child,proto3" json:"child,omitempty"`
+	Func                 *Function     `protobuf:"bytes,3,opt,name=func,proto3" json:"func,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *FilterTree) Reset()         { *m = FilterTree{} }
+func (m *FilterTree) String() string { return proto.CompactTextString(m) }
+func (*FilterTree) ProtoMessage()    {}
+func (*FilterTree) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{14}
+}
+func (m *FilterTree) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FilterTree.Unmarshal(m, b)
+}
+func (m *FilterTree) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FilterTree.Marshal(b, m, deterministic)
+}
+func (dst *FilterTree) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilterTree.Merge(dst, src)
+}
+func (m *FilterTree) XXX_Size() int {
+	return xxx_messageInfo_FilterTree.Size(m)
+}
+func (m *FilterTree) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilterTree.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilterTree proto.InternalMessageInfo
+
+func (m *FilterTree) GetOp() string {
+	if m != nil {
+		return m.Op
+	}
+	return ""
+}
+
+func (m *FilterTree) GetChild() []*FilterTree {
+	if m != nil {
+		return m.Child
+	}
+	return nil
+}
+
+func (m *FilterTree) GetFunc() *Function {
+	if m != nil {
+		return m.Func
+	}
+	return nil
+}
+
+type MathTree struct {
+	Fn                   string          `protobuf:"bytes,1,opt,name=fn,proto3" json:"fn,omitempty"`
+	Var                  string          `protobuf:"bytes,2,opt,name=var,proto3" json:"var,omitempty"`
+	Const                *Val            `protobuf:"bytes,3,opt,name=const,proto3" json:"const,omitempty"`
+	Val                  map[uint64]*Val `protobuf:"bytes,4,rep,name=val,proto3" json:"val,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Child                []*MathTree     `protobuf:"bytes,5,rep,name=child,proto3" json:"child,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *MathTree) Reset()         { *m = MathTree{} }
+func (m *MathTree) String() string { return proto.CompactTextString(m) }
+func (*MathTree) ProtoMessage()    {}
+func (*MathTree) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{15}
+}
+func (m *MathTree) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MathTree.Unmarshal(m, b)
+}
+func (m *MathTree) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MathTree.Marshal(b, m, deterministic)
+}
+func (dst *MathTree) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MathTree.Merge(dst, src)
+}
+func (m *MathTree) XXX_Size() int {
+	return xxx_messageInfo_MathTree.Size(m)
+}
+func (m *MathTree) XXX_DiscardUnknown() {
+	xxx_messageInfo_MathTree.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MathTree proto.InternalMessageInfo
+
+func (m *MathTree) GetFn() string {
+	if m != nil {
+		return m.Fn
+	}
+	return ""
+}
+
+func (m *MathTree) GetVar() string {
+	if m != nil {
+		return m.Var
+	}
+	return ""
+}
+
+func (m *MathTree) GetConst() *Val {
+	if m != nil {
+		return m.Const
+	}
+	return nil
+}
+
+func (m *MathTree) GetVal() map[uint64]*Val {
+	if m != nil {
+		return m.Val
+	}
+	return nil
+}
+
+func (m *MathTree) GetChild() []*MathTree {
+	if m != nil {
+		return m.Child
+	}
+	return nil
+}
+
+type Val struct {
+	Tid                  int32          `protobuf:"varint,1,opt,name=tid,proto3" json:"tid,omitempty"`
+	Value                *_struct.Value `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Val) Reset()         { *m = Val{} }
+func (m *Val) String() string { return proto.CompactTextString(m) }
+func (*Val) ProtoMessage()    {}
+func (*Val) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{16}
+}
+func (m *Val) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Val.Unmarshal(m, b)
+}
+func (m *Val) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Val.Marshal(b, m, deterministic)
+}
+func (dst *Val) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Val.Merge(dst, src)
+}
+func (m *Val) XXX_Size() int {
+	return xxx_messageInfo_Val.Size(m)
+}
+func (m *Val) XXX_DiscardUnknown() {
+	xxx_messageInfo_Val.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Val proto.InternalMessageInfo
+
+func (m *Val) GetTid() int32 {
+	if m != nil {
+		return m.Tid
+	}
+	return 0
+}
+
+func (m *Val) GetValue() *_struct.Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type GroupByAttr struct {
+	Attr                 string   `protobuf:"bytes,1,opt,name=attr,proto3" json:"attr,omitempty"`
+	Alias                string   `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	Langs                []string `protobuf:"bytes,3,rep,name=langs,proto3" json:"langs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GroupByAttr) Reset()         { *m = GroupByAttr{} }
+func (m *GroupByAttr) String() string { return proto.CompactTextString(m) }
+func (*GroupByAttr) ProtoMessage()    {}
+func (*GroupByAttr) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dgraphtools_07975e6d6548932c, []int{17}
+}
+func (m *GroupByAttr) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupByAttr.Unmarshal(m, b)
+}
+func (m *GroupByAttr) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupByAttr.Marshal(b, m, deterministic)
+}
+func (dst *GroupByAttr) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupByAttr.Merge(dst, src)
+}
+func (m *GroupByAttr) XXX_Size() int {
+	return xxx_messageInfo_GroupByAttr.Size(m)
+}
+func (m *GroupByAttr) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupByAttr.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupByAttr proto.InternalMessageInfo
+
+func (m *GroupByAttr) GetAttr() string {
+	if m != nil {
+		return m.Attr
+	}
+	return ""
+}
+
+func (m *GroupByAttr) GetAlias() string {
+	if m != nil {
+		return m.Alias
+	}
+	return ""
+}
+
+func (m *GroupByAttr) GetLangs() []string {
+	if m != nil {
+		return m.Langs
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*QueryRequest)(nil), "dgraphtools.QueryRequest")
+	proto.RegisterMapType((map[uint64]*GraphQuery)(nil), "dgraphtools.QueryRequest.ProofEntry")
+	proto.RegisterMapType((map[string]string)(nil), "dgraphtools.QueryRequest.VariablesEntry")
+	proto.RegisterType((*QueryResponse)(nil), "dgraphtools.QueryResponse")
+	proto.RegisterType((*TemplateRequest)(nil), "dgraphtools.TemplateRequest")
+	proto.RegisterMapType((map[string]string)(nil), "dgraphtools.TemplateRequest.VariablesEntry")
+	proto.RegisterType((*TemplateResponse)(nil), "dgraphtools.TemplateResponse")
+	proto.RegisterType((*ParseRequest)(nil), "dgraphtools.ParseRequest")
+	proto.RegisterMapType((map[string]string)(nil), "dgraphtools.ParseRequest.VariablesEntry")
+	proto.RegisterType((*ParseResponse)(nil), "dgraphtools.ParseResponse")
+	proto.RegisterType((*GraphQuery)(nil), "dgraphtools.GraphQuery")
+	proto.RegisterMapType((map[string]string)(nil), "dgraphtools.GraphQuery.ArgsEntry")
+	proto.RegisterMapType((map[string]*_struct.Value)(nil), "dgraphtools.GraphQuery.ExtensionsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "dgraphtools.GraphQuery.FacetVarEntry")
+	proto.RegisterType((*RecurseArgs)(nil), "dgraphtools.RecurseArgs")
+	proto.RegisterType((*FacetParams)(nil), "dgraphtools.FacetParams")
+	proto.RegisterType((*FacetParam)(nil), "dgraphtools.FacetParam")
+	proto.RegisterType((*Order)(nil), "dgraphtools.Order")
+	proto.RegisterType((*VarContext)(nil), "dgraphtools.VarContext")
+	proto.RegisterType((*Function)(nil), "dgraphtools.Function")
+	proto.RegisterType((*Arg)(nil), "dgraphtools.Arg")
+	proto.RegisterType((*FilterTree)(nil), "dgraphtools.FilterTree")
+	proto.RegisterType((*MathTree)(nil), "dgraphtools.MathTree")
+	proto.RegisterMapType((map[uint64]*Val)(nil), "dgraphtools.MathTree.ValEntry")
+	proto.RegisterType((*Val)(nil), "dgraphtools.Val")
+	proto.RegisterType((*GroupByAttr)(nil), "dgraphtools.GroupByAttr")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// DgraphToolsClient is the client API for DgraphTools service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DgraphToolsClient interface {
+	// Query runs queries and returns the JSON response of Dgraph.
+	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Template renders queries to GraphQL+-.
+	Template(ctx context.Context, in *TemplateRequest, opts ...grpc.CallOption) (*TemplateResponse, error)
+	// Parse parses GraphQL+- into queries.
+	Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+}
+
+type dgraphToolsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDgraphToolsClient(cc *grpc.ClientConn) DgraphToolsClient {
+	return &dgraphToolsClient{cc}
+}
+
+func (c *dgraphToolsClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, "/dgraphtools.DgraphTools/Query", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dgraphToolsClient) Template(ctx context.Context, in *TemplateRequest, opts ...grpc.CallOption) (*TemplateResponse, error) {
+	out := new(TemplateResponse)
+	err := c.cc.Invoke(ctx, "/dgraphtools.DgraphTools/Template", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dgraphToolsClient) Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, "/dgraphtools.DgraphTools/Parse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DgraphToolsServer is the server API for DgraphTools service.
+type DgraphToolsServer interface {
+	// Query runs queries and returns the JSON response of Dgraph.
+	Query(context.Context, *QueryRequest) (*QueryResponse, error)
+	// Template renders queries to GraphQL+-.
+	Template(context.Context, *TemplateRequest) (*TemplateResponse, error)
+	// Parse parses GraphQL+- into queries.
+	Parse(context.Context, *ParseRequest) (*ParseResponse, error)
+}
+
+func RegisterDgraphToolsServer(s *grpc.Server, srv DgraphToolsServer) {
+	s.RegisterService(&_DgraphTools_serviceDesc, srv)
+}
+
+func _DgraphTools_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DgraphToolsServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dgraphtools.DgraphTools/Query",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DgraphToolsServer).Query(ctx, req.(*QueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DgraphTools_Template_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DgraphToolsServer).Template(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dgraphtools.DgraphTools/Template",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DgraphToolsServer).Template(ctx, req.(*TemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DgraphTools_Parse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DgraphToolsServer).Parse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dgraphtools.DgraphTools/Parse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DgraphToolsServer).Parse(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DgraphTools_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dgraphtools.DgraphTools",
+	HandlerType: (*DgraphToolsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Query",
+			Handler:    _DgraphTools_Query_Handler,
+		},
+		{
+			MethodName: "Template",
+			Handler:    _DgraphTools_Template_Handler,
+		},
+		{
+			MethodName: "Parse",
+			Handler:    _DgraphTools_Parse_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dgraphtools.proto",
+}
+
+func init() { proto.RegisterFile("dgraphtools.proto", fileDescriptor_dgraphtools_07975e6d6548932c) }
+
+var fileDescriptor_dgraphtools_07975e6d6548932c = []byte{
+	// 1409 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0x5b, 0x6f, 0x1b, 0xb7,
+	0x12, 0x86, 0x6e, 0xd6, 0x6a, 0x24, 0xd9, 0x0e, 0x4f, 0x2e, 0x8c, 0xe2, 0x24, 0x8a, 0x72, 0x39,
+	0x3e, 0xc8, 0x39, 0x8e, 0x4f, 0x92, 0xa2, 0x45, 0x92, 0x06, 0xb0, 0x13, 0xdb, 0x0d, 0xda, 0xa0,
+	0xc9, 0x22, 0x55, 0x81, 0xbe, 0x08, 0xb4, 0x96, 0x92, 0xb7, 0xa1, 0x77, 0x37, 0xe4, 0xae, 0x6b,
+	0xf5, 0x27, 0xf5, 0x07, 0xf5, 0x0f, 0xf4, 0xb5, 0x2f, 0x7d, 0x2d, 0x50, 0xa0, 0xe0, 0x90, 0xab,
+	0xdd, 0xb5, 0x57, 0x6e, 0x8d, 0xb6, 0x6f, 0xe4, 0xf0, 0x9b, 0x0b, 0x87, 0x1f, 0x67, 0x48, 0xb8,
+	0xe0, 0x4d, 0x25, 0x8b, 0x0e, 0xe2, 0x30, 0x14, 0x6a, 0x23, 0x92, 0x61, 0x1c, 0x92, 0x76, 0x4e,
+	0xd4, 0x5b, 0x9b, 0x86, 0xe1, 0x54, 0xf0, 0x07, 0xb8, 0xb4, 0x9f, 0x4c, 0x1e, 0xa8, 0x58, 0x26,
+	0xe3, 0xd8, 0x40, 0x07, 0x3f, 0x57, 0xa1, 0xf3, 0x36, 0xe1, 0x72, 0xe6, 0xf2, 0x0f, 0x09, 0x57,
+	0x31, 0xf9, 0x3f, 0x34, 0x3f, 0x24, 0x5c, 0xfa, 0x5c, 0xd1, 0x4a, 0xbf, 0xb6, 0xde, 0x7e, 0x78,
+	0x65, 0x23, 0xef, 0x60, 0x4f, 0x0f, 0x8d, 0x42, 0x8a, 0x23, 0x17, 0xa1, 0xc1, 0x84, 0xcf, 0x14,
+	0xad, 0xf6, 0x2b, 0xeb, 0x2d, 0xd7, 0x4c, 0xc8, 0x2e, 0xb4, 0x8e, 0x98, 0xf4, 0xd9, 0xbe, 0xe0,
+	0x8a, 0xd6, 0xd0, 0xd4, 0x7a, 0xc1, 0x54, 0xde, 0xed, 0xc6, 0x30, 0x85, 0xee, 0x04, 0xb1, 0x9c,
+	0xb9, 0x99, 0x2a, 0x79, 0x02, 0x8d, 0x48, 0x86, 0xe1, 0x84, 0xd6, 0xd1, 0xc6, 0x9d, 0xc5, 0x36,
+	0xde, 0x68, 0x98, 0xd1, 0x37, 0x2a, 0xbd, 0x67, 0xb0, 0x5c, 0x34, 0x4c, 0x56, 0xa1, 0xf6, 0x9e,
+	0xcf, 0x68, 0x05, 0x23, 0xd5, 0x43, 0x1d, 0xfd, 0x11, 0x13, 0x09, 0x4f, 0xa3, 0xc7, 0xc9, 0x93,
+	0xea, 0x27, 0x95, 0xde, 0x5b, 0x80, 0xcc, 0x64, 0x5e, 0xb3, 0x6e, 0x34, 0xff, 0x97, 0xd7, 0x3c,
+	0x23, 0x51, 0x99, 0xc9, 0xc1, 0x6d, 0xe8, 0xda, 0x90, 0x55, 0x14, 0x06, 0x8a, 0x13, 0x02, 0xf5,
+	0x6f, 0x55, 0x18, 0xa0, 0xd9, 0x8e, 0x8b, 0xe3, 0xc1, 0x4f, 0x15, 0x58, 0x79, 0xc7, 0x0f, 0x23,
+	0xc1, 0x62, 0xfe, 0xb7, 0x1f, 0xcb, 0xab, 0xd3, 0xc7, 0x72, 0xbf, 0x60, 0xea, 0x84, 0xe7, 0xc5,
+	0x27, 0xf3, 0xd7, 0xb2, 0x3b, 0x58, 0x87, 0xd5, 0xcc, 0x95, 0xcd, 0xc6, 0x45, 0x68, 0xe8, 0xe8,
+	0x53, 0x0b, 0x66, 0x32, 0xf8, 0xa1, 0x02, 0x9d, 0x37, 0x4c, 0xaa, 0x79, 0x32, 0x4a, 0x61, 0x45,
+	0xc2, 0x55, 0x4b, 0x08, 0x97, 0xb7, 0xf1, 0x8f, 0x6d, 0x6b, 0x1b, 0xba, 0xd6, 0x8f, 0xdd, 0xd3,
+	0xf9, 0x4f, 0x6e, 0xf0, 0x6b, 0x1b, 0x20, 0x93, 0x6b, 0xf7, 0x89, 0xef, 0xa1, 0x76, 0xdd, 0xd5,
+	0x43, 0xcd, 0x1a, 0x16, 0xc7, 0xd2, 0x7a, 0xc7, 0xb1, 0x0e, 0x49, 0xb0, 0x60, 0x6a, 0x0e, 0xb5,
+	0xe5, 0x9a, 0x49, 0x46, 0x82, 0x7a, 0x9e, 0x04, 0x9b, 0xd0, 0xf4, 0xf8, 0x84, 0x25, 0x22, 0xa6,
+	0x0d, 0xe4, 0xee, 0xe5, 0x0d, 0x53, 0x25, 0x36, 0xd2, 0x2a, 0xb1, 0x31, 0xd4, 0x3b, 0x72, 0x53,
+	0x18, 0xb9, 0x0a, 0x8e, 0xaf, 0x46, 0xe3, 0x30, 0x09, 0x62, 0xba, 0xd4, 0xaf, 0xac, 0x3b, 0x6e,
+	0xd3, 0x57, 0x2f, 0xf4, 0x94, 0xdc, 0x84, 0xb6, 0xaf, 0x46, 0x7e, 0x10, 0x73, 0x19, 0x30, 0x41,
+	0x9b, 0xb8, 0x0a, 0xbe, 0x7a, 0x65, 0x25, 0xe4, 0x3a, 0x80, 0xaf, 0x46, 0x53, 0x19, 0x26, 0xd1,
+	0xfe, 0x8c, 0x3a, 0xb8, 0xde, 0xf2, 0xd5, 0x9e, 0x11, 0xe8, 0xed, 0x1d, 0x31, 0x49, 0x5b, 0x26,
+	0xbb, 0x47, 0x4c, 0x92, 0xc7, 0xd0, 0x0a, 0x38, 0xf7, 0xd4, 0x48, 0xcb, 0xa1, 0x24, 0x69, 0x43,
+	0x26, 0x5f, 0x84, 0x41, 0xcc, 0x8f, 0x63, 0xd7, 0x41, 0xe4, 0x90, 0x49, 0xf2, 0x1f, 0xa8, 0x4f,
+	0x92, 0x60, 0x4c, 0xdb, 0xb8, 0xa3, 0x4b, 0x05, 0x85, 0xdd, 0x24, 0x18, 0xc7, 0x7e, 0x18, 0xb8,
+	0x08, 0x21, 0x97, 0x61, 0x89, 0x1f, 0x47, 0x2c, 0xf0, 0x68, 0x07, 0xbd, 0xda, 0x19, 0xf9, 0x08,
+	0xea, 0x4c, 0x4e, 0x15, 0xed, 0xa2, 0xcf, 0x5b, 0x0b, 0x0e, 0x6a, 0x63, 0x4b, 0x4e, 0x2d, 0x6d,
+	0x10, 0x4e, 0xd6, 0xa1, 0x11, 0x4a, 0x8f, 0x4b, 0xba, 0x8c, 0x7a, 0xa4, 0xa0, 0xf7, 0xa5, 0x5e,
+	0x71, 0x0d, 0x80, 0x3c, 0x02, 0x67, 0x7c, 0xe0, 0x0b, 0x4f, 0xf2, 0x80, 0xae, 0x9c, 0xcd, 0x86,
+	0x39, 0x90, 0x3c, 0x80, 0xa5, 0x89, 0x2f, 0x62, 0x2e, 0xe9, 0x6a, 0x49, 0xa1, 0xd9, 0xc5, 0xa5,
+	0x77, 0x92, 0x73, 0xd7, 0xc2, 0xc8, 0x26, 0x38, 0x87, 0x2c, 0x3e, 0x18, 0xf1, 0xe3, 0x88, 0x5e,
+	0x28, 0xc9, 0xc6, 0x6b, 0x16, 0x1f, 0xa0, 0x42, 0x53, 0xc3, 0x76, 0x8e, 0x23, 0xb2, 0x06, 0xad,
+	0x20, 0x94, 0x87, 0x4c, 0xf8, 0xdf, 0x73, 0x4a, 0xcc, 0x09, 0xcd, 0x05, 0x84, 0x42, 0x53, 0xf2,
+	0x71, 0x22, 0x15, 0xa7, 0xff, 0x32, 0x67, 0x6f, 0xa7, 0xe4, 0x29, 0x74, 0xec, 0x70, 0x84, 0x89,
+	0xbb, 0x88, 0xde, 0x68, 0xc1, 0x9b, 0x6b, 0x00, 0x3a, 0x69, 0x6e, 0x5b, 0x66, 0x13, 0x6d, 0x76,
+	0xcc, 0xd4, 0x98, 0x79, 0x9c, 0x5e, 0x32, 0x66, 0xed, 0x94, 0xdc, 0x85, 0x65, 0x3b, 0x1c, 0x4d,
+	0x7c, 0x2e, 0x3c, 0x45, 0x2f, 0x23, 0xa9, 0xbb, 0x56, 0xba, 0x8b, 0x42, 0x72, 0x1b, 0xba, 0xfe,
+	0x34, 0x08, 0x25, 0x1f, 0x49, 0x3e, 0x11, 0xfc, 0x98, 0x5e, 0x41, 0x33, 0x1d, 0x23, 0x74, 0x51,
+	0x46, 0x36, 0x61, 0x69, 0xc2, 0xc6, 0x3c, 0x56, 0x94, 0x96, 0x04, 0xb7, 0xab, 0x97, 0xde, 0x30,
+	0xc9, 0x0e, 0x95, 0x6b, 0x71, 0xe4, 0x19, 0x74, 0xcd, 0x68, 0x64, 0xd3, 0x7e, 0xf5, 0xec, 0xb4,
+	0x77, 0x0c, 0xda, 0x48, 0xc8, 0xa7, 0xd0, 0xb5, 0x54, 0x1f, 0xe9, 0x7b, 0xa9, 0x68, 0xaf, 0x5f,
+	0x3b, 0xe5, 0x16, 0xb9, 0xbf, 0x3d, 0xdb, 0x8a, 0x63, 0xe9, 0x76, 0x2c, 0x5c, 0x4f, 0x14, 0xd9,
+	0x86, 0x16, 0x9a, 0x43, 0xee, 0x5f, 0x43, 0xd5, 0xbb, 0x8b, 0x78, 0x88, 0xc1, 0x0f, 0x99, 0x34,
+	0x5c, 0x74, 0x26, 0x76, 0xaa, 0x6f, 0xa4, 0xb1, 0x61, 0x58, 0xb9, 0x86, 0x1c, 0x07, 0x14, 0x21,
+	0x1b, 0xf5, 0x8d, 0x34, 0x00, 0x8f, 0xab, 0x31, 0xbd, 0x6e, 0xce, 0x1b, 0x25, 0x2f, 0xb9, 0x1a,
+	0x93, 0x6b, 0xd0, 0x4a, 0x7c, 0xcf, 0xde, 0xf6, 0x1b, 0xb8, 0xea, 0x24, 0xbe, 0x67, 0xae, 0xfb,
+	0x3d, 0x58, 0x99, 0x2f, 0x8e, 0x4c, 0x6d, 0xb9, 0x89, 0x0e, 0xba, 0x29, 0x64, 0x4b, 0x0b, 0x49,
+	0x1f, 0xda, 0x63, 0x26, 0x3d, 0x3f, 0x60, 0xc2, 0x8f, 0x67, 0xb4, 0x8f, 0x98, 0xbc, 0x88, 0xec,
+	0x01, 0xf0, 0xe3, 0x98, 0x07, 0xca, 0x0f, 0x03, 0x45, 0x6f, 0xe1, 0x5e, 0xff, 0xbd, 0x68, 0xaf,
+	0x3b, 0x73, 0xa4, 0xd9, 0x6d, 0x4e, 0xb5, 0xf7, 0x31, 0xb4, 0xe6, 0x57, 0xf2, 0x5c, 0x1d, 0xfe,
+	0x29, 0x74, 0x0b, 0x39, 0x3c, 0x97, 0xf2, 0x57, 0xb0, 0x72, 0x22, 0xa8, 0x12, 0xf5, 0xff, 0x16,
+	0xdf, 0x08, 0x8b, 0xea, 0x6c, 0xa1, 0x81, 0xb4, 0x73, 0x37, 0x46, 0xfb, 0xf7, 0x78, 0x14, 0x1f,
+	0xd8, 0x87, 0x87, 0x99, 0xe8, 0x03, 0x64, 0x42, 0x84, 0xdf, 0x8d, 0x44, 0x18, 0x46, 0x68, 0xdb,
+	0x71, 0x5b, 0x28, 0xf9, 0x22, 0x0c, 0xa3, 0xc1, 0xd7, 0xd0, 0xce, 0x11, 0x5b, 0x17, 0x6f, 0x26,
+	0xc4, 0xe8, 0x3d, 0x9f, 0x29, 0x34, 0xe3, 0xb8, 0x4d, 0x26, 0xc4, 0xe7, 0x7c, 0xa6, 0xf4, 0x1b,
+	0x26, 0xd2, 0x20, 0xdb, 0x30, 0xaf, 0x2c, 0xb8, 0x1c, 0xae, 0x41, 0x0d, 0x1e, 0x03, 0x64, 0xc2,
+	0xf2, 0x6c, 0x9d, 0x7e, 0x73, 0x0c, 0x76, 0xa0, 0x61, 0x78, 0x97, 0xf6, 0xad, 0x4a, 0xae, 0x6f,
+	0x11, 0xa8, 0x23, 0x0b, 0xcd, 0x26, 0x70, 0x5c, 0xde, 0xcb, 0x06, 0x0f, 0x01, 0xb2, 0xc2, 0xaf,
+	0xf5, 0x02, 0x76, 0xc8, 0x53, 0x5b, 0x7a, 0xac, 0x03, 0x8a, 0x67, 0x26, 0x1f, 0x35, 0x57, 0x0f,
+	0x07, 0xbf, 0x54, 0xc0, 0x49, 0x8b, 0xff, 0x22, 0xf7, 0xda, 0x7a, 0xda, 0x4a, 0xf5, 0x78, 0x6e,
+	0xba, 0x96, 0x33, 0x7d, 0xc7, 0xb6, 0x06, 0xf3, 0x0a, 0x5d, 0x2d, 0xe4, 0x69, 0x4b, 0x4e, 0x6d,
+	0x27, 0xb0, 0xad, 0xba, 0x91, 0xb5, 0xea, 0x42, 0x2f, 0x5b, 0xfa, 0xb3, 0xbd, 0x2c, 0xdf, 0x6e,
+	0x9b, 0xc5, 0x76, 0xdb, 0x87, 0x8e, 0xaf, 0xad, 0x89, 0x84, 0xa3, 0x4d, 0x27, 0xed, 0xb7, 0xc8,
+	0xa4, 0x21, 0x93, 0x03, 0x0f, 0x6a, 0x5b, 0x72, 0x9a, 0x31, 0xb7, 0x92, 0x63, 0xee, 0x29, 0xf5,
+	0xea, 0x49, 0x75, 0x72, 0x17, 0x56, 0xb0, 0x5d, 0xb3, 0xe8, 0x60, 0xf4, 0x41, 0x20, 0xa8, 0x66,
+	0xeb, 0xaa, 0x32, 0x17, 0x52, 0x68, 0x2f, 0x47, 0x00, 0x59, 0x0d, 0x24, 0xcb, 0x50, 0x0d, 0x23,
+	0xeb, 0xa9, 0x1a, 0x46, 0x9a, 0x57, 0xd8, 0xbf, 0xca, 0x79, 0x35, 0xd7, 0x73, 0x0d, 0x6a, 0xde,
+	0xbb, 0x6b, 0x7f, 0xd8, 0xbb, 0x07, 0xbf, 0x55, 0xc0, 0x49, 0x1b, 0x98, 0x76, 0x3b, 0x09, 0x52,
+	0xb7, 0x93, 0x20, 0x7d, 0x4b, 0x54, 0xb3, 0xb7, 0xc4, 0x3d, 0x68, 0x8c, 0xc3, 0x40, 0xc5, 0xd6,
+	0xf4, 0xea, 0x89, 0xdc, 0x0b, 0xd7, 0x2c, 0x93, 0x4d, 0xad, 0x29, 0xec, 0xf1, 0xde, 0x28, 0x6d,
+	0x97, 0x1a, 0x6e, 0x8a, 0x8f, 0x86, 0x92, 0xfb, 0xe9, 0x16, 0x1b, 0xfd, 0xda, 0xa9, 0xa0, 0x53,
+	0x1d, 0xbb, 0xc1, 0xde, 0x67, 0xe0, 0xa4, 0xda, 0x25, 0x3f, 0x89, 0x7b, 0xc5, 0x2a, 0x51, 0x12,
+	0x64, 0x56, 0x1f, 0x76, 0xa0, 0x36, 0x64, 0x02, 0xa9, 0x8e, 0x8f, 0xc2, 0xca, 0x7a, 0xc3, 0xd5,
+	0xc3, 0xf3, 0x95, 0x9a, 0xc1, 0x6b, 0x68, 0xe7, 0x9a, 0x50, 0xe9, 0xd5, 0x28, 0xff, 0x40, 0x94,
+	0xde, 0xcd, 0x87, 0x3f, 0x56, 0xa0, 0xfd, 0x12, 0x83, 0x7e, 0xa7, 0x83, 0x26, 0xcf, 0xa1, 0x61,
+	0x1e, 0xaf, 0x57, 0x17, 0xfe, 0xd7, 0x7a, 0xbd, 0xb2, 0x25, 0xfb, 0x6a, 0xde, 0x03, 0x27, 0xfd,
+	0x1d, 0x90, 0xb5, 0xb3, 0xfe, 0x27, 0xbd, 0xeb, 0x0b, 0x56, 0xad, 0xa1, 0xe7, 0xd0, 0xc0, 0xf7,
+	0xf8, 0x89, 0x40, 0xf2, 0x7f, 0x81, 0x5e, 0xaf, 0x6c, 0xc9, 0xe8, 0x6f, 0xd7, 0xbf, 0xa9, 0x46,
+	0xfb, 0xfb, 0x4b, 0x98, 0xc4, 0x47, 0xbf, 0x0f, 0x00, 0x2f, 0x0c, 0x29, 0x30, 0x6d, 0x0f, 0x00,
+	0x00,
+}
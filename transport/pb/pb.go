@@ -0,0 +1,5 @@
+// Package pb holds the protobuf messages and the gRPC service of the
+// transport package.
+package pb
+
+//go:generate protoc --go_out=plugins=grpc,Mgoogle/protobuf/struct.proto=github.com/golang/protobuf/ptypes/struct:. dgraphtools.proto
@@ -0,0 +1,34 @@
+package transport
+
+import "mooncamp.com/dgraphtools/gql"
+
+// Media types of the query endpoint. Requests and responses without a
+// version use the latest one.
+const (
+	MediaTypeJSON    = "application/json"
+	MediaTypeQueryV1 = "application/vnd.dgraphtools.query.v1+json"
+)
+
+// QueryRequestV1 is version 1 of the JSON body of query requests:
+//
+//	{
+//	  "queries": [{"alias": "me", "func": {"name": "uid", "uid": [5]}, "children": [{"attr": "name"}]}],
+//	  "alias": "",
+//	  "variables": {},
+//	  "proof": {"5": {"func": {"name": "uid", "uid": [1]}, "children": [{"attr": "friends"}]}}
+//	}
+//
+// The response is the JSON of the Dgraph response after the
+// extensions were applied, errors are an ErrorBody.
+type QueryRequestV1 struct {
+	// Queries are the blocks to run.
+	Queries []gql.GraphQuery `json:"queries"`
+	// Alias renders the queries as a named query.
+	Alias string `json:"alias,omitempty"`
+	// Variables are the values of the GraphQL variables, keyed by name
+	// including the leading $.
+	Variables map[string]string `json:"variables,omitempty"`
+	// Proof maps uids used by the queries to queries showing that the
+	// identity may access them.
+	Proof map[uint64]gql.GraphQuery `json:"proof,omitempty"`
+}